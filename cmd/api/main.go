@@ -2,11 +2,11 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"soundtube/cmd/di"
+	"soundtube/pkg/config"
 	"syscall"
 	"time"
 )
@@ -33,7 +33,12 @@ import (
 // @description Type "Bearer" followed by a space and JWT token
 
 func getContainer() *di.Container {
-	var container, err = di.NewContainer()
+	source, err := config.SourceFromFlags(os.Args[1:])
+	if err != nil {
+		os.Exit(2)
+	}
+
+	container, err := di.NewContainer(source)
 	if err != nil {
 		panic(err)
 	}
@@ -50,20 +55,44 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	go func() {
+		for range hangup {
+			_ = container.Reload()
+		}
+	}()
+	container.WatchConfig()
+
 	container.Logger.Info("initialization completed")
 
 	go func() {
-		info := fmt.Sprintf("Server is starting on http://localhost%s\n", container.Server.Addr)
-		container.Logger.Info(info)
+		container.Logger.Info("Server is starting", "addr", container.Server.Addr)
 		if err := container.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			container.Logger.Info("listen: http://localhost%s\n", err)
+			container.Logger.Error("listen failed", err)
 		}
 	}()
 
-	<-quit
+	if container.AdminServer != nil {
+		go func() {
+			container.Logger.Info("Admin server is starting", "addr", container.AdminServer.Addr)
+			if err := container.AdminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				container.Logger.Error("admin listen failed", err)
+			}
+		}()
+	}
 
-	container.Logger.Info("Shutting down server")
-	container.Close()
+	sig := <-quit
+
+	container.Logger.Info("Shutting down server", "signal", sig.String())
+	container.Drain()
+
+	// SIGTERM comes from an orchestrator: keep serving until load balancers have seen
+	// /ready fail. Ctrl-C during development stops right away.
+	if sig == syscall.SIGTERM {
+		time.Sleep(container.Config.Health.DrainDelay)
+	}
 
 	ctx, cancle := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancle()
@@ -72,5 +101,15 @@ func main() {
 		container.Logger.Error("Server forced to shutdown", err)
 	}
 
+	if container.AdminServer != nil {
+		if err := container.AdminServer.Shutdown(ctx); err != nil {
+			container.Logger.Error("Admin server forced to shutdown", err)
+		}
+	}
+
+	if err := container.Close(); err != nil {
+		container.Logger.Error("Container close failed", err)
+	}
+
 	container.Logger.Info("OK ")
 }
@@ -1,23 +1,40 @@
 package di
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
+	"path/filepath"
 	"soundtube/internal/domain"
 	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/chart"
+	"soundtube/internal/domain/feature"
+	"soundtube/internal/domain/feed"
+	"soundtube/internal/domain/listening"
+	"soundtube/internal/domain/mail"
+	"soundtube/internal/domain/ratelimit"
+	"soundtube/internal/domain/realtime"
 	"soundtube/internal/handlers"
 	"soundtube/internal/repositories"
 	"soundtube/internal/services"
 	"soundtube/pkg"
 	"soundtube/pkg/config"
+	"soundtube/pkg/health"
+	"soundtube/pkg/metrics"
 	"soundtube/pkg/middleware"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
@@ -26,43 +43,89 @@ import (
 )
 
 type Container struct {
-	isShuttingDown bool
+	reloadMu sync.Mutex
 
-	Config *config.Config
+	ConfigSource config.Source
+	Config       *config.Config
 
 	TraceProvider *tracesdk.TracerProvider
 	Tracer        trace.Tracer
 	Logger        *pkg.CustomLogger
+	LogLevel      *slog.LevelVar
 
 	Engine *gin.Engine
 	Redis  *redis.Client
 	Cache  domain.ICache
+	Feed   feed.IFeedStore
 
-	Server *http.Server
+	ListeningSessions listening.ISessionTracker
+	Charts            chart.IChartStore
+	EventBus          realtime.IEventBus
+	FeatureNotifier   feature.IFlagNotifier
+	Mailer            mail.IMailer
 
-	RateLimiter *pkg.RateLimiter
+	Server      *http.Server
+	AdminServer *http.Server
+
+	Metrics *metrics.Metrics
+	Health  *health.Registry
+
+	ClientIPResolver  *pkg.ClientIPResolver
+	CORSPolicy        *middleware.CORSPolicy
+	RateLimiter       ratelimit.ILimiter
+	RateLimitPolicies *pkg.RateLimitPolicies
 
 	TokenBlackList auth.ITokenBlacklist
 
 	Repository *repositories.RepositoryAdapter
 
-	RegisterHandler  *handlers.RegisterHandler
-	LoginHandler     *handlers.LoginHandler
-	VerifyHandler    *handlers.EmailHandler
-	SoundHandler     *handlers.SoundHandler
-	CommentHandler   *handlers.CommentHandler
-	UploadHandler    *handlers.UploadHandler
-	ReactionsHandler *handlers.ReactionHandler
-
-	Email           *services.EmailService
-	RegisterService *services.RegisterService
-	LoginService    *services.LoginService
-	SoundService    *services.SoundService
-	ReactionService *services.ReactionService
+	RegisterHandler       *handlers.RegisterHandler
+	LoginHandler          *handlers.LoginHandler
+	VerifyHandler         *handlers.EmailHandler
+	SoundHandler          *handlers.SoundHandler
+	CommentHandler        *handlers.CommentHandler
+	UploadHandler         *handlers.UploadHandler
+	ReactionsHandler      *handlers.ReactionHandler
+	ExportHandler         *handlers.ExportHandler
+	UserHandler           *handlers.UserHandler
+	FollowHandler         *handlers.FollowHandler
+	FeedHandler           *handlers.FeedHandler
+	PlaylistHandler       *handlers.PlaylistHandler
+	AlbumHandler          *handlers.AlbumHandler
+	SearchHandler         *handlers.SearchHandler
+	ListeningHandler      *handlers.ListeningHandler
+	ChartHandler          *handlers.ChartHandler
+	RecommendationHandler *handlers.RecommendationHandler
+	RealtimeHandler       *handlers.RealtimeHandler
+	NotificationHandler   *handlers.NotificationHandler
+	DigestHandler         *handlers.DigestHandler
+	FeatureHandler        *handlers.FeatureHandler
+
+	Email                 *services.EmailService
+	RegisterService       *services.RegisterService
+	LoginService          *services.LoginService
+	SoundService          *services.SoundService
+	ReactionService       *services.ReactionService
+	ExportService         *services.ExportService
+	ProfileService        *services.ProfileService
+	FollowService         *services.FollowService
+	FeedService           *services.FeedService
+	PlaylistService       *services.PlaylistService
+	AlbumService          *services.AlbumService
+	CommentService        *services.CommentService
+	SearchService         *services.SearchService
+	ListeningService      *services.ListeningService
+	ChartService          *services.ChartService
+	RecommendationService *services.RecommendationService
+	RealtimeHub           *services.RealtimeHub
+	NotificationService   *services.NotificationService
+	DigestService         *services.DigestService
+	OutboxService         *services.OutboxService
+	FeatureService        *services.FeatureService
 }
 
-func NewContainer() (*Container, error) {
-	var container = Container{}
+func NewContainer(source config.Source) (*Container, error) {
+	var container = Container{ConfigSource: source}
 
 	if err := container.init(); err != nil {
 		return nil, err
@@ -83,21 +146,42 @@ func (c *Container) init() error {
 
 func (c *Container) initCore() error {
 	var err error
-	c.Config, err = config.LoadConfig()
+	c.Config, err = config.LoadConfig(c.ConfigSource)
 	if err != nil {
 		return err
 	}
 
-	c.Logger = pkg.NewLogger(slog.Default(), c.Config.Traycing.Enabled)
+	c.LogLevel = new(slog.LevelVar)
+	c.LogLevel.Set(pkg.ParseLogLevel(c.Config.Logging.Level, c.Config.Environment.Current))
+
+	var logger = pkg.NewSlogLogger(os.Stdout, &c.Config.Logging, c.Config.Environment.Current, c.LogLevel)
+	slog.SetDefault(logger)
+	c.Logger = pkg.NewLogger(logger, c.Config.Traycing.Enabled)
+
+	if err = c.initTraycing(); err != nil {
+		return err
+	}
 
-	c.initRateLimiter()
 	c.initRedis()
 
+	if c.ClientIPResolver, err = pkg.NewClientIPResolver(c.Config.Server.TrustedProxies); err != nil {
+		return err
+	}
+
+	if err = c.initRateLimiter(); err != nil {
+		return err
+	}
+
 	if err = c.initRepositories(); err != nil {
 		return err
 	}
 
-	c.initServices()
+	c.initMetrics()
+
+	if err = c.initServices(); err != nil {
+		return err
+	}
+
 	c.initHandlers()
 
 	c.initGinEngine()
@@ -109,7 +193,7 @@ func (c *Container) initCore() error {
 
 func (c *Container) initProdFeatures() {
 	c.initHealthCheck()
-	c.initTraycing()
+	c.initMetricsEndpoint()
 
 	c.Logger.Info("prod features initialization were successful")
 }
@@ -123,16 +207,81 @@ func (c *Container) initRepositories() error {
 
 	c.TokenBlackList = repositories.NewTokenBlacklist(c.Redis, c.Logger)
 	c.Cache = repositories.NewRedisCache(c.Redis)
+	c.Feed = repositories.NewRedisFeedStore(c.Redis, c.Config.Feed.MaxLength, c.Config.Feed.TTL, c.Logger)
+	c.ListeningSessions = repositories.NewRedisSessionTracker(c.Redis, c.Config.Listening.SessionTTL, c.Logger)
+	c.Charts = repositories.NewRedisChartStore(c.Redis, 3*c.Config.Charts.RefreshInterval, c.Logger)
+	c.EventBus = repositories.NewRedisEventBus(c.Redis, int64(c.Config.Realtime.HistoryLength),
+		c.Config.Realtime.HistoryTTL, c.Logger)
+	c.FeatureNotifier = repositories.NewRedisFlagNotifier(c.Redis, c.Logger)
+
+	if c.Mailer, err = c.newMailer(); err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (c *Container) initServices() {
-	c.Email = services.NewEmailService(c.Repository.UserRepository, c.Config.Server.Host+c.Config.Server.Port, &c.Config.Email, c.Logger)
-	c.RegisterService = services.NewRegisterService(c.Repository, c.Email, c.Logger)
-	c.LoginService = services.NewLoginService(c.Config.Token, c.Repository.UserRepository, c.TokenBlackList, c.Logger)
-	c.SoundService = services.NewSoundService(c.Repository.SoundRepository, c.Repository.UserRepository, c.Logger)
-	c.ReactionService = services.NewRactionService(c.Repository.SoundReactionRepository, c.Repository.SoundPartisipantsRepository, c.Cache, c.Logger)
+func (c *Container) newMailer() (mail.IMailer, error) {
+	switch c.Config.Email.Transport {
+	case mail.TransportSMTP:
+		return repositories.NewSMTPMailer(&c.Config.Email, c.Logger), nil
+	case mail.TransportFile:
+		return repositories.NewFileMailer(c.Config.Email.DropDir, c.Config.Email.From, c.Logger)
+	case mail.TransportMemory:
+		return repositories.NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail transport %q", c.Config.Email.Transport)
+	}
+}
+
+func (c *Container) initServices() error {
+	templates, err := services.NewEmailTemplates(c.Config.Email.TemplatesDir, c.Config.Email.Locale)
+	if err != nil {
+		return err
+	}
+
+	c.Email = services.NewEmailService(c.Repository.UserRepository, c.Repository.MailOutboxRepository, c.Config.Server.PublicURL, templates, c.Logger)
+	c.OutboxService = services.NewOutboxService(c.Repository.MailOutboxRepository, c.Mailer, &c.Config.Email, c.Logger)
+	c.RegisterService = services.NewRegisterService(c.Repository, c.Email, c.Metrics, c.Logger)
+	c.LoginService = services.NewLoginService(c.Config.Token, c.Repository.UserRepository, c.Repository.SessionRepository, c.TokenBlackList, c.Metrics, c.Logger)
+	c.NotificationService = services.NewNotificationService(c.Repository.NotificationRepository, c.Repository.SoundRepository, c.Logger)
+	c.DigestService = services.NewDigestService(c.Repository.NotificationRepository, c.Repository.NotificationRepository, c.Email,
+		c.Config.Server.PublicURL, &c.Config.Digest, c.Logger)
+	c.FeedService = services.NewFeedService(c.Repository.ActivityRepository, c.Feed, c.Repository.FollowRepository, c.Repository.SoundRepository, &c.Config.Feed, c.Logger)
+	c.FollowService = services.NewFollowService(c.Repository.FollowRepository, c.Repository.UserRepository, c.NotificationService, c.FeedService, c.Logger)
+	c.SoundService = services.NewSoundService(c.Repository.SoundRepository, c.Repository.UserRepository, c.Repository.AlbumRepository, c.FeedService, c.EventBus, c.Logger)
+	c.ReactionService = services.NewRactionService(c.Repository.SoundReactionRepository, c.Repository.SoundPartisipantsRepository, c.Cache, c.EventBus, c.NotificationService, c.Metrics, c.Logger)
+	c.ExportService = services.NewExportService(c.Repository.UserRepository, c.Repository.SoundRepository, c.Repository.SoundPartisipantsRepository,
+		c.Repository.CommentRepository, c.Repository.SessionRepository, c.Email, c.Config.Server.PublicURL, c.Config.Server.StaticDir, &c.Config.Export, c.Logger)
+	c.ProfileService = services.NewProfileService(c.Repository.UserRepository, c.Repository.SoundRepository, c.Logger)
+	c.PlaylistService = services.NewPlaylistService(c.Repository.PlaylistRepository, c.Repository.SoundRepository, c.Repository.UserRepository, c.Config.Server.PublicURL, c.Logger)
+	c.CommentService = services.NewCommentService(c.Repository.CommentRepository, c.Repository.SoundRepository, c.EventBus, c.NotificationService,
+		c.FeedService, c.Logger)
+	c.AlbumService = services.NewAlbumService(c.Repository.AlbumRepository, c.Repository.SoundRepository, c.Repository.UserRepository, c.Logger)
+	c.SearchService = services.NewSearchService(c.Repository.SearchRepository, c.Logger)
+	c.ListeningService = services.NewListeningService(c.Repository.PlayRepository, c.ListeningSessions, c.Repository.SoundRepository, &c.Config.Listening, c.Logger)
+	c.ChartService = services.NewChartService(c.Repository.ChartRepository, c.Charts, c.Repository.SoundRepository, &c.Config.Charts, c.Logger)
+	c.RecommendationService = services.NewRecommendationService(c.Repository.RecommendationRepository, c.Repository.SoundRepository,
+		repositories.NewRedisLock(c.Redis, "lock:recommendations:rebuild", c.Logger), &c.Config.Recommendations, c.Logger)
+	c.RealtimeHub = services.NewRealtimeHub(c.EventBus, &c.Config.Realtime, c.Logger)
+
+	if c.FeatureService, err = services.NewFeatureService(c.Repository.FeatureFlagRepository, c.FeatureNotifier, &c.Config.Features, c.Logger); err != nil {
+		return err
+	}
+	if err = c.FeatureService.Refresh(context.Background()); err != nil {
+		c.Logger.Warn("feature flag overrides unavailable, using configured flags", err)
+	}
+
+	go c.ExportService.Run()
+	go c.ListeningService.Run()
+	go c.ChartService.Run()
+	go c.RecommendationService.Run()
+	go c.RealtimeHub.Run()
+	go c.DigestService.Run()
+	go c.OutboxService.Run()
+	go c.FeatureService.Run()
+
+	return nil
 }
 
 func (c *Container) initHandlers() {
@@ -140,50 +289,180 @@ func (c *Container) initHandlers() {
 	c.LoginHandler = handlers.NewLoginHandler(c.LoginService, c.Logger)
 	c.SoundHandler = handlers.NewSoundHandler(c.SoundService, c.Logger)
 	c.VerifyHandler = handlers.NewEmailHandler(c.Email, c.Logger)
-	c.UploadHandler = handlers.NewUploadHandler(c.SoundService, c.Logger)
+	c.UploadHandler = handlers.NewUploadHandler(c.SoundService, c.Metrics, c.Config.Server.StaticDir, c.Logger)
 	c.ReactionsHandler = handlers.NewReactionHandler(c.ReactionService, c.Logger)
+	c.ExportHandler = handlers.NewExportHandler(c.ExportService, c.Logger)
+	c.UserHandler = handlers.NewUserHandler(c.ProfileService, c.Logger)
+	c.FollowHandler = handlers.NewFollowHandler(c.FollowService, c.Logger)
+	c.FeedHandler = handlers.NewFeedHandler(c.FeedService, c.Logger)
+	c.PlaylistHandler = handlers.NewPlaylistHandler(c.PlaylistService, c.Logger)
+	c.AlbumHandler = handlers.NewAlbumHandler(c.AlbumService, c.Config.Server.StaticDir, c.Logger)
+	c.CommentHandler = handlers.NewCommentHandler(c.CommentService, c.Logger)
+	c.SearchHandler = handlers.NewSearchHandler(c.SearchService, c.Logger)
+	c.ListeningHandler = handlers.NewListeningHandler(c.ListeningService, c.Logger)
+	c.ChartHandler = handlers.NewChartHandler(c.ChartService, c.Logger)
+	c.RecommendationHandler = handlers.NewRecommendationHandler(c.RecommendationService, c.Logger)
+	c.NotificationHandler = handlers.NewNotificationHandler(c.NotificationService, c.Logger)
+	c.DigestHandler = handlers.NewDigestHandler(c.DigestService, c.Logger)
+	c.FeatureHandler = handlers.NewFeatureHandler(c.FeatureService, c.Logger)
+	c.RealtimeHandler = handlers.NewRealtimeHandler(c.RealtimeHub, c.FeatureService, c.Config.Realtime.Heartbeat, c.Logger)
 }
 
 func (c *Container) initGinEngine() {
-	c.Engine = gin.Default()
+	c.Engine = gin.New()
 
-	c.Engine.Use(middleware.SecurityMiddleware())
-	c.Engine.Use(middleware.RequsetIDMiddleware())
-	c.Engine.Use(middleware.RateLimiterMiddleware(c.RateLimiter))
+	var pageCSP = middleware.ContentSecurityPolicy(c.Config.Security.CSP)
 
-	c.Engine.Static("/static", "../../static")
-	c.Engine.LoadHTMLGlob("../../static/*.html")
+	// Keep gin's own ClientIP in line with ClientIPMiddleware for any code that calls it.
+	if err := c.Engine.SetTrustedProxies(c.Config.Server.TrustedProxies); err != nil {
+		c.Logger.Warn("invalid trusted proxies for gin", err)
+	}
 
-	var api = c.Engine.Group("/api")
+	c.Engine.Use(gin.Recovery())
+	c.Engine.Use(otelgin.Middleware(c.Config.Traycing.ServiceName))
+	c.Engine.Use(middleware.ClientIPMiddleware(c.ClientIPResolver))
+	c.Engine.Use(middleware.RequsetIDMiddleware())
+	c.Engine.Use(middleware.AccessLogMiddleware(c.Logger))
+	c.CORSPolicy = middleware.NewCORSPolicy(&c.Config.Security.CORS)
+	c.Engine.Use(middleware.CORSMiddleware(c.CORSPolicy))
+	c.Engine.Use(middleware.SecurityMiddleware(middleware.SecurityHeaders{
+		CSP:               pageCSP,
+		ReferrerPolicy:    c.Config.Security.ReferrerPolicy,
+		PermissionsPolicy: c.Config.Security.PermissionsPolicy,
+	}))
+	c.Engine.Use(middleware.MetricsMiddleware(c.Metrics))
+	c.Engine.Use(c.rateLimit("default"))
+
+	c.Engine.Static("/static", c.Config.Server.StaticDir)
+	c.Engine.LoadHTMLGlob(filepath.Join(c.Config.Server.StaticDir, "*.html"))
+
+	var adminOnly = middleware.RoleMiddleware(auth.RoleAdmin)
+
+	// JSON responses never load anything, so the API gets a policy stricter than the pages.
+	var api = c.Engine.Group("/api", middleware.ContentSecurityPolicyMiddleware(middleware.ContentSecurityPolicy{
+		"default-src":     "'none'",
+		"frame-ancestors": "'none'",
+	}))
 	{
-		var auth = api.Group("/auth")
+		var auth = api.Group("/auth", c.rateLimit("auth"))
 		{
 			auth.POST("/register", c.RegisterHandler.Register)
-			auth.POST("/login", c.LoginHandler.Login)
+			auth.POST("/login", c.rateLimit("login"), c.LoginHandler.Login)
 			auth.POST("/logout", c.LoginHandler.Logout)
 			auth.GET("/verify-email", c.VerifyHandler.VerifyEmail)
 		}
 
+		api.GET("/exports/:id", c.ExportHandler.DownloadExport)
+
+		api.GET("/email/unsubscribe", middleware.ContentSecurityPolicyMiddleware(pageCSP), c.DigestHandler.UnsubscribePage)
+		api.POST("/email/unsubscribe", middleware.ContentSecurityPolicyMiddleware(pageCSP), c.DigestHandler.Unsubscribe)
+
+		var users = api.Group("/users")
+		{
+			users.GET("/:username", c.UserHandler.GetProfile)
+			users.GET("/:username/sounds", c.UserHandler.GetUserSounds)
+			users.GET("/:username/followers", c.FollowHandler.GetFollowers)
+			users.GET("/:username/following", c.FollowHandler.GetFollowing)
+			users.GET("/:username/albums", c.AlbumHandler.GetUserAlbums)
+		}
+
+		api.GET("/albums/:id", c.AlbumHandler.GetAlbum)
+
+		api.GET("/charts/trending", c.ChartHandler.GetTrending)
+
+		var search = api.Group("/search")
+		{
+			search.GET("", c.SearchHandler.Search)
+			search.GET("/suggest", c.SearchHandler.Suggest)
+		}
+
+		api.GET("/sounds/:id/events", c.RealtimeHandler.StreamSoundEvents)
+		api.GET("/ws", middleware.WebSocketAuthMiddleware(), middleware.AuthMiddleware(c.LoginService, c.Logger), c.RealtimeHandler.Connect)
+
 		var authRequered = api.Group("")
 		authRequered.Use(middleware.AuthMiddleware(c.LoginService, c.Logger))
+		authRequered.Use(c.rateLimit("user"))
 
 		var sounds = authRequered.Group("/sounds")
 		{
 			sounds.GET("/", c.SoundHandler.GetSounds)
 			sounds.POST("/", c.SoundHandler.CreateSound)
-			sounds.POST("/upload", c.UploadHandler.UploadSoundFile)
+			sounds.POST("/upload", c.rateLimit("upload"), c.UploadHandler.UploadSoundFile)
 			sounds.PATCH("/:id", c.SoundHandler.UpdateSound)
 			sounds.DELETE("/:id", c.SoundHandler.DeleteSound)
 
-			sounds.GET("/:id/comments", c.CommentHandler.GetComments)
-			sounds.POST("/:id/comments", c.CommentHandler.CreateComment)
+			sounds.GET("/:id/comments", c.feature("comments"), c.CommentHandler.GetComments)
+			sounds.POST("/:id/comments", c.feature("comments"), c.CommentHandler.CreateComment)
 
 			sounds.PUT("/:id/reactions", c.ReactionsHandler.SetReactionSound)
 			sounds.DELETE("/:id/reactions", c.ReactionsHandler.DeleteReactionSound)
 			sounds.GET("/:id/reactions", c.ReactionsHandler.GetReactionSound)
+
+			sounds.PUT("/:id/repost", c.FeedHandler.Repost)
+			sounds.DELETE("/:id/repost", c.FeedHandler.Unrepost)
+
+			sounds.POST("/:id/listens", c.ListeningHandler.TrackListen)
+			sounds.GET("/:id/similar", c.feature("recommendations"), c.RecommendationHandler.GetSimilar)
+		}
+
+		var follows = authRequered.Group("/users")
+		{
+			follows.PUT("/:username/follow", c.FollowHandler.Follow)
+			follows.DELETE("/:username/follow", c.FollowHandler.Unfollow)
+		}
+
+		authRequered.GET("/feed", c.FeedHandler.GetFeed)
+
+		var playlists = authRequered.Group("/playlists")
+		{
+			playlists.GET("/", c.PlaylistHandler.GetMyPlaylists)
+			playlists.POST("/", c.PlaylistHandler.CreatePlaylist)
+			playlists.GET("/:id", c.PlaylistHandler.GetPlaylist)
+			playlists.PATCH("/:id", c.PlaylistHandler.UpdatePlaylist)
+			playlists.DELETE("/:id", c.PlaylistHandler.DeletePlaylist)
+			playlists.GET("/:id/export", c.PlaylistHandler.ExportPlaylist)
+
+			playlists.PUT("/:id/collaborators/:username", c.PlaylistHandler.AddCollaborator)
+			playlists.DELETE("/:id/collaborators/:username", c.PlaylistHandler.RemoveCollaborator)
+
+			playlists.POST("/:id/tracks", c.PlaylistHandler.AddTrack)
+			playlists.PATCH("/:id/tracks/:trackId", c.PlaylistHandler.MoveTrack)
+			playlists.DELETE("/:id/tracks/:trackId", c.PlaylistHandler.RemoveTrack)
+		}
+
+		var albums = authRequered.Group("/albums")
+		{
+			albums.POST("/", c.AlbumHandler.CreateAlbum)
+			albums.PATCH("/:id", c.AlbumHandler.UpdateAlbum)
+			albums.DELETE("/:id", c.AlbumHandler.DeleteAlbum)
+			albums.POST("/:id/cover", c.AlbumHandler.UploadCover)
+
+			albums.PUT("/:id/tracks", c.AlbumHandler.SetTrack)
+			albums.DELETE("/:id/tracks/:soundId", c.AlbumHandler.RemoveTrack)
+		}
+
+		var me = authRequered.Group("/me")
+		{
+			me.POST("/export", c.ExportHandler.RequestExport)
+			me.GET("/history", c.ListeningHandler.GetHistory)
+			me.GET("/recommendations", c.feature("recommendations"), c.RecommendationHandler.GetRecommendations)
+			me.GET("/email-digest", c.DigestHandler.GetSettings)
+			me.PUT("/email-digest", c.DigestHandler.UpdateSettings)
+			me.GET("/features", c.FeatureHandler.GetMyFeatures)
+		}
+
+		var notifications = authRequered.Group("/notifications")
+		{
+			notifications.GET("/", c.NotificationHandler.GetNotifications)
+			notifications.GET("/unread-count", c.NotificationHandler.GetUnreadCount)
+			notifications.POST("/read-all", c.NotificationHandler.MarkAllRead)
+			notifications.POST("/:id/read", c.NotificationHandler.MarkRead)
+
+			notifications.GET("/preferences", c.NotificationHandler.GetPreferences)
+			notifications.PUT("/preferences", c.NotificationHandler.UpdatePreferences)
 		}
 
-		var comments = authRequered.Group("/comments")
+		var comments = authRequered.Group("/comments", c.feature("comments"))
 		{
 			comments.PATCH("/:id", c.CommentHandler.UpdateComment)
 			comments.DELETE("/:id", c.CommentHandler.DeleteComment)
@@ -192,10 +471,17 @@ func (c *Container) initGinEngine() {
 			comments.DELETE("/:id/reactions", c.ReactionsHandler.DeleteReactionComment)
 			comments.GET("/:id/reactions", c.ReactionsHandler.GetReactionComment)
 		}
+
+		var admin = authRequered.Group("/admin", adminOnly)
+		{
+			admin.GET("/features", c.FeatureHandler.GetFlags)
+			admin.PUT("/features/:name", c.FeatureHandler.SetFlag)
+			admin.DELETE("/features/:name", c.FeatureHandler.ResetFlag)
+		}
 	}
 
 	c.Engine.NoRoute(func(ctx *gin.Context) {
-		ctx.HTML(http.StatusOK, "index.html", nil)
+		ctx.HTML(http.StatusOK, "index.html", gin.H{"CSPNonce": middleware.CSPNonce(ctx)})
 	})
 }
 
@@ -211,28 +497,106 @@ func (c *Container) initServer() {
 	c.Server = &http.Server{
 		Addr:         c.Config.Server.Port,
 		Handler:      c.Engine,
-		ReadTimeout:  time.Duration(c.Config.Server.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(c.Config.Server.WriteTimeout) * time.Second,
-		IdleTimeout:  time.Duration(c.Config.Server.IdleTimeout) * time.Second,
+		ReadTimeout:  c.Config.Server.ReadTimeout,
+		WriteTimeout: c.Config.Server.WriteTimeout,
+		IdleTimeout:  c.Config.Server.IdleTimeout,
 	}
 }
 
-func (c *Container) initRateLimiter() {
-	c.RateLimiter = pkg.NewRateLimiter(&c.Config.RateLimiter)
+// initMetrics creates the registry when metrics are enabled. A nil c.Metrics turns every
+// recording call into a no-op, so the rest of the wiring does not need to check.
+func (c *Container) initMetrics() {
+	if !c.Config.Metrics.Enabled {
+		return
+	}
+
+	c.Metrics = metrics.NewMetrics()
+	c.Repository.RegisterMetrics(c.Metrics, c.Config.Database.DBName)
+	c.Metrics.RegisterRedis(c.Redis)
 }
 
+// initMetricsEndpoint serves /metrics on the admin listener when one is configured and
+// falls back to the public engine only when a scrape token protects it.
+func (c *Container) initMetricsEndpoint() {
+	if c.Metrics == nil {
+		return
+	}
+
+	if c.Config.Metrics.Addr != "" {
+		admin := gin.New()
+		admin.Use(gin.Recovery())
+		if c.Config.Metrics.Token != "" {
+			admin.Use(middleware.MetricsTokenMiddleware(c.Config.Metrics.Token))
+		}
+		admin.GET("/metrics", gin.WrapH(c.Metrics.Handler()))
+
+		c.AdminServer = &http.Server{
+			Addr:              c.Config.Metrics.Addr,
+			Handler:           admin,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		return
+	}
+
+	if c.Config.Metrics.Token == "" {
+		c.Logger.Info("metrics endpoint disabled: set metrics.addr or metrics.token to expose it")
+		return
+	}
+
+	c.Engine.GET("/metrics", middleware.MetricsTokenMiddleware(c.Config.Metrics.Token), gin.WrapH(c.Metrics.Handler()))
+}
+
+func (c *Container) initRateLimiter() error {
+	var cfg = c.Config.RateLimiter
+
+	switch cfg.Backend {
+	case "redis", "":
+		c.RateLimiter = pkg.NewFallbackRateLimiter(repositories.NewRedisRateLimiter(c.Redis, c.Logger), pkg.NewMemoryRateLimiter(), c.Logger)
+	case "memory":
+		c.RateLimiter = pkg.NewMemoryRateLimiter()
+	default:
+		return fmt.Errorf("unknown rate limiter backend %q", cfg.Backend)
+	}
+
+	policies, err := pkg.ParseRateLimitPolicies(&cfg)
+	if err != nil {
+		return err
+	}
+	c.RateLimitPolicies = pkg.NewRateLimitPolicies(policies)
+
+	return nil
+}
+
+// rateLimit returns the middleware for a named policy. A policy missing from the
+// config leaves the route unthrottled beyond the default one until a reload adds it.
+func (c *Container) rateLimit(name string) gin.HandlerFunc {
+	if _, ok := c.RateLimitPolicies.Get(name); !ok {
+		c.Logger.Info("rate limit policy not configured", "policy", name)
+	}
+
+	return middleware.RateLimiterMiddleware(c.RateLimiter, c.RateLimitPolicies, name, c.Metrics, c.Logger)
+}
+
+// feature gates a route on a feature flag, see FeatureMiddleware.
+func (c *Container) feature(name string) gin.HandlerFunc {
+	return middleware.FeatureMiddleware(c.FeatureService, name)
+}
+
+// initTraycing installs the OTLP exporter. When tracing is disabled the logger keeps its
+// no-op tracer and the global provider stays the otel default, so instrumentation is free.
 func (c *Container) initTraycing() error {
 	if !c.Config.Traycing.Enabled {
 		return nil
 	}
 
-	exp, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(c.Config.Traycing.Endpoint)))
+	exp, err := c.newTraceExporter()
 	if err != nil {
-		return err
+		return fmt.Errorf("trace exporter: %w", err)
 	}
 
 	c.TraceProvider = tracesdk.NewTracerProvider(
 		tracesdk.WithBatcher(exp),
+		tracesdk.WithSampler(tracesdk.ParentBased(tracesdk.TraceIDRatioBased(c.Config.Traycing.SampleRatio))),
 		tracesdk.WithResource(resource.NewWithAttributes(
 			semconv.SchemaURL,
 			semconv.ServiceNameKey.String(c.Config.Traycing.ServiceName),
@@ -254,57 +618,58 @@ func (c *Container) initTraycing() error {
 	c.Logger.Info("Initializing tracing",
 		"service_name", c.Config.Traycing.ServiceName,
 		"endpoint", c.Config.Traycing.Endpoint,
+		"protocol", c.Config.Traycing.Protocol,
 	)
 
 	return nil
 }
 
-func (c *Container) initHealthCheck() {
-	c.Engine.GET("/health", func(ctx *gin.Context) {
-		health := map[string]string{
-			"status":    "ok",
-			"timestamp": time.Now().UTC().Format(time.RFC3339),
-		}
-
-		if err := c.Repository.HealthCheck(ctx.Request.Context()); err != nil {
-			health["database"] = "unhealthy"
-			health["status"] = "degraded"
-			ctx.JSON(http.StatusInternalServerError, health)
-			return
-		}
+func (c *Container) newTraceExporter() (*otlptrace.Exporter, error) {
+	var ctx = context.Background()
+	var cfg = c.Config.Traycing
 
-		if err := c.Redis.Ping().Err(); err != nil {
-			health["redis"] = "unhealthy"
-			health["status"] = "degraded"
-			ctx.JSON(http.StatusInternalServerError, health)
-			return
+	switch cfg.Protocol {
+	case "grpc", "":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
 		}
-
-		health["database"] = "healthy"
-		health["redis"] = "healthy"
-		ctx.JSON(http.StatusOK, health)
-	})
-
-	c.Engine.GET("/ready", func(ctx *gin.Context) {
-		if c.isShuttingDown {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"status": "shutting down"})
-			return
+		return otlptracegrpc.New(ctx, opts...)
+	case "http":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
 		}
-
-		ctx.JSON(http.StatusOK, gin.H{"status": "ready"})
-	})
-
-	c.Engine.GET("/live", func(ctx *gin.Context) {
-		ctx.JSON(http.StatusOK, gin.H{"status": "live"})
-	})
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing protocol %q", cfg.Protocol)
+	}
 }
 
 func (c *Container) Close() error {
-	c.isShuttingDown = true
+	c.Health.Drain()
+
+	c.ExportService.Stop()
+	c.ListeningService.Stop()
+	c.ChartService.Stop()
+	c.RecommendationService.Stop()
+	c.RealtimeHub.Stop()
+	c.DigestService.Stop()
+	c.OutboxService.Stop()
+	c.FeatureService.Stop()
 
 	if err := c.Repository.Close(); err != nil {
 		return err
 	}
 
+	if c.TraceProvider != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := c.TraceProvider.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,37 @@
+package di
+
+import (
+	"context"
+	"soundtube/pkg/config"
+	"testing"
+)
+
+func TestNewTraceExporter(t *testing.T) {
+	tests := []struct {
+		protocol string
+		wantErr  bool
+	}{
+		{protocol: ""},
+		{protocol: "grpc"},
+		{protocol: "http"},
+		{protocol: "zipkin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			c := &Container{Config: &config.Config{Traycing: config.Traycing{
+				Endpoint: "localhost:4317",
+				Protocol: tt.protocol,
+				Insecure: true,
+			}}}
+
+			exp, err := c.newTraceExporter()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if exp != nil {
+				_ = exp.Shutdown(context.Background())
+			}
+		})
+	}
+}
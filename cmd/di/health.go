@@ -0,0 +1,91 @@
+package di
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"soundtube/pkg/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// initHealthCheck registers a check for every dependency and mounts /health (full
+// report), /ready (traffic gate) and /live (process is up).
+func (c *Container) initHealthCheck() {
+	c.Health = health.NewRegistry(c.Config.Health.CacheTTL, c.Config.Health.Timeout, c.Logger)
+
+	c.Health.Register(health.Check{
+		Name:     "postgres",
+		Type:     health.TypeDatastore,
+		Critical: true,
+		Probe:    c.Repository.HealthCheck,
+	})
+	c.Health.Register(health.Check{
+		Name:     "redis",
+		Type:     health.TypeDatastore,
+		Critical: true,
+		Probe: func(ctx context.Context) error {
+			return c.Redis.WithContext(ctx).Ping().Err()
+		},
+	})
+	c.Health.Register(health.Check{
+		Name:     "storage",
+		Type:     health.TypeSystem,
+		Critical: true,
+		Probe:    health.WritableDir(filepath.Join(c.Config.Server.StaticDir, "uploads"), c.Config.Export.Dir),
+	})
+
+	// The memory mailer has nothing to check.
+	if mailer, ok := c.Mailer.(interface{ HealthCheck(context.Context) error }); ok {
+		c.Health.Register(health.Check{
+			Name:  "mailer",
+			Type:  health.TypeComponent,
+			Probe: mailer.HealthCheck,
+		})
+	}
+
+	c.Health.Register(health.Check{
+		Name:      "mail-outbox",
+		Type:      health.TypeComponent,
+		DependsOn: []string{"postgres", "mailer"},
+		Probe:     c.OutboxService.HealthCheck,
+	})
+	c.Health.Register(health.Check{
+		Name:      "export-queue",
+		Type:      health.TypeComponent,
+		DependsOn: []string{"postgres", "storage"},
+		Probe:     c.ExportService.HealthCheck,
+	})
+
+	c.Engine.GET("/health", func(ctx *gin.Context) {
+		report := c.Health.Report(ctx.Request.Context())
+
+		ctx.Header("Content-Type", "application/health+json")
+		ctx.JSON(report.HTTPStatus(), report)
+	})
+
+	c.Engine.GET("/ready", func(ctx *gin.Context) {
+		ready, report := c.Health.Ready(ctx.Request.Context())
+
+		ctx.Header("Content-Type", "application/health+json")
+		switch {
+		case c.Health.Draining():
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": health.StatusFail, "output": "shutting down"})
+		case !ready:
+			ctx.JSON(http.StatusServiceUnavailable, report)
+		default:
+			ctx.JSON(http.StatusOK, gin.H{"status": report.Status})
+		}
+	})
+
+	c.Engine.GET("/live", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"status": "live"})
+	})
+}
+
+// Drain fails /ready right away so that load balancers stop routing new requests here,
+// while requests already in flight keep being served until the server shuts down.
+func (c *Container) Drain() {
+	c.Health.Drain()
+	c.Logger.Info("draining, readiness check is now failing")
+}
@@ -0,0 +1,73 @@
+package di
+
+import (
+	"context"
+	"log/slog"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+)
+
+// Reload re-reads the configuration and applies the settings that can change without a
+// restart (see config.Reloadable). The new config is loaded and validated in full and
+// everything that can fail is prepared before anything is switched, so an invalid
+// reload is rejected as a whole and the running config stays in place.
+func (c *Container) Reload() error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	next, err := config.LoadConfig(c.ConfigSource)
+	if err != nil {
+		c.Logger.Error("config reload rejected", err)
+		return err
+	}
+
+	current := c.Config
+	merged := current.Reloadable(next)
+
+	if ignored := merged.Changes(next); len(ignored) > 0 {
+		c.Logger.LogContext(context.Background(), slog.LevelWarn, "config changes need a restart and were not applied", "keys", ignored)
+	}
+
+	policies, err := pkg.ParseRateLimitPolicies(&merged.RateLimiter)
+	if err != nil {
+		c.Logger.Error("config reload rejected", err)
+		return err
+	}
+
+	flags, err := services.ParseFeatureFlags(merged.Features.Flags)
+	if err != nil {
+		c.Logger.Error("config reload rejected", err)
+		return err
+	}
+
+	// Templates on disk may have been edited without touching the config, so they are
+	// re-read on every reload.
+	var templates *services.EmailTemplates
+	if merged.Email.TemplatesDir != "" || merged.Email != current.Email {
+		if templates, err = services.NewEmailTemplates(merged.Email.TemplatesDir, merged.Email.Locale); err != nil {
+			c.Logger.Error("config reload rejected", err)
+			return err
+		}
+	}
+
+	c.LogLevel.Set(pkg.ParseLogLevel(merged.Logging.Level, merged.Environment.Current))
+	c.RateLimitPolicies.Store(policies)
+	c.CORSPolicy.Update(&merged.Security.CORS)
+	c.FeatureService.SetDefaults(flags)
+	if templates != nil {
+		c.Email.SetTemplates(templates)
+	}
+
+	c.Config = merged
+	c.Logger.Info("config reloaded", "changed", current.Changes(merged))
+
+	return nil
+}
+
+// WatchConfig reloads whenever the config file is written. SIGHUP is handled in main.
+func (c *Container) WatchConfig() {
+	if !config.Watch(c.ConfigSource, func() { _ = c.Reload() }) {
+		c.Logger.Info("no config file to watch, reload with SIGHUP only")
+	}
+}
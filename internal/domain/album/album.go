@@ -0,0 +1,73 @@
+package album
+
+import (
+	"errors"
+	"soundtube/scripts"
+	"time"
+)
+
+const ReleaseDateLayout = "2006-01-02"
+
+var ErrInvalidTrackNumber = errors.New("track number must be positive")
+
+type Album struct {
+	id          int
+	artistID    int
+	artistName  string
+	title       string
+	releaseDate string
+	coverPath   string
+	createdAt   string
+}
+
+func (a *Album) ID() int             { return a.id }
+func (a *Album) ArtistID() int       { return a.artistID }
+func (a *Album) ArtistName() string  { return a.artistName }
+func (a *Album) Title() string       { return a.title }
+func (a *Album) ReleaseDate() string { return a.releaseDate }
+func (a *Album) CoverPath() string   { return a.coverPath }
+func (a *Album) CreatedAt() string   { return a.createdAt }
+
+func NewAlbum(artistID int, title, releaseDate string) (*Album, error) {
+	if artistID <= 0 {
+		return nil, errors.New("invalid artist id")
+	}
+
+	a := &Album{artistID: artistID}
+	if err := a.Update(title, releaseDate); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func RebuildAlbumFromStorage(id, artistID int, artistName, title, releaseDate, coverPath, createdAt string) *Album {
+	return &Album{
+		id:          id,
+		artistID:    artistID,
+		artistName:  artistName,
+		title:       title,
+		releaseDate: releaseDate,
+		coverPath:   coverPath,
+		createdAt:   createdAt,
+	}
+}
+
+func (a *Album) Update(title, releaseDate string) error {
+	if title == "" || scripts.ValidateXSS(title) {
+		return errors.New("album title cannot be empty")
+	}
+	if releaseDate != "" {
+		if _, err := time.Parse(ReleaseDateLayout, releaseDate); err != nil {
+			return errors.New("release date must be in YYYY-MM-DD format")
+		}
+	}
+
+	a.title = title
+	a.releaseDate = releaseDate
+	return nil
+}
+
+func (a *Album) IsOwnedBy(userID int) bool {
+	return a.artistID == userID
+}
@@ -0,0 +1,35 @@
+package album
+
+import "soundtube/internal/domain/sound"
+
+type AlbumDTO struct {
+	ID          int                 `json:"id"`
+	Artist      sound.AuthorSummary `json:"artist"`
+	Title       string              `json:"title"`
+	ReleaseDate string              `json:"release_date,omitempty"`
+	CoverPath   string              `json:"cover_path,omitempty"`
+	CreatedAt   string              `json:"created_at"`
+	Tracks      []*sound.SoundDTO   `json:"tracks,omitempty"`
+}
+
+func (a *Album) ToDTO() *AlbumDTO {
+	return &AlbumDTO{
+		ID: a.id,
+		Artist: sound.AuthorSummary{
+			ID:       a.artistID,
+			Username: a.artistName,
+		},
+		Title:       a.title,
+		ReleaseDate: a.releaseDate,
+		CoverPath:   a.coverPath,
+		CreatedAt:   a.createdAt,
+	}
+}
+
+func AlbumsToDTO(albums []*Album) []*AlbumDTO {
+	dtos := make([]*AlbumDTO, len(albums))
+	for i, a := range albums {
+		dtos[i] = a.ToDTO()
+	}
+	return dtos
+}
@@ -0,0 +1,36 @@
+package album
+
+import "testing"
+
+func TestNewAlbum(t *testing.T) {
+	cases := map[string]struct {
+		artistID    int
+		title       string
+		releaseDate string
+		wantErr     string
+	}{
+		"valid":           {artistID: 1, title: "Nightfall", releaseDate: "2024-03-01"},
+		"no release date": {artistID: 1, title: "Nightfall"},
+		"invalid artist":  {artistID: 0, title: "Nightfall", wantErr: "invalid artist id"},
+		"empty title":     {artistID: 1, title: "", wantErr: "album title cannot be empty"},
+		"markup in title": {artistID: 1, title: "<script>x</script>", wantErr: "album title cannot be empty"},
+		"bad release date": {artistID: 1, title: "Nightfall", releaseDate: "01/03/2024",
+			wantErr: "release date must be in YYYY-MM-DD format"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := NewAlbum(tc.artistID, tc.title, tc.releaseDate)
+			switch {
+			case tc.wantErr != "":
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("err = %v, want %q", err, tc.wantErr)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case a.Title() != tc.title || a.ReleaseDate() != tc.releaseDate || !a.IsOwnedBy(tc.artistID):
+				t.Errorf("album = %+v", a.ToDTO())
+			}
+		})
+	}
+}
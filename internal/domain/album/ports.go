@@ -0,0 +1,21 @@
+package album
+
+import "context"
+
+type IAlbumRepository interface {
+	IAlbumRepositoryReader
+	IAlbumRepositoryWriter
+}
+
+type IAlbumRepositoryReader interface {
+	GetAlbumByID(ctx context.Context, id int) (*Album, error)
+	GetAlbumByTitle(ctx context.Context, artistID int, title string) (*Album, error)
+	GetAlbumsByArtist(ctx context.Context, artistID int) ([]*Album, error)
+}
+
+type IAlbumRepositoryWriter interface {
+	CreateAlbum(ctx context.Context, album *Album) (int, error)
+	UpdateAlbum(ctx context.Context, id int, album *Album) error
+	UpdateAlbumCover(ctx context.Context, id int, coverPath string) error
+	DeleteAlbum(ctx context.Context, id int) error
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"soundtube/internal/domain/mail"
 	"time"
 )
 
@@ -19,15 +20,27 @@ type IUserRepositoryReader interface {
 }
 
 type IUserRepositoryWriter interface {
-	CreateUser(ctx context.Context, user *User) error
+	// CreateUser stores the user and, in the same transaction, puts outbox messages
+	// (such as the verification email) into the mail outbox.
+	CreateUser(ctx context.Context, user *User, outbox ...*mail.Message) error
 	DeleteUser(ctx context.Context, id int) error
 }
 
+type IProfileRepository interface {
+	GetProfileByName(ctx context.Context, username string) (*Profile, error)
+}
+
+type ISessionRepository interface {
+	CreateSession(ctx context.Context, session *Session) error
+	EndSession(ctx context.Context, id string, at time.Time) error
+	GetSessionsByUser(ctx context.Context, userID int) ([]*Session, error)
+}
+
 type ITokenBlacklist interface {
 	Add(ctx context.Context, token string, duration time.Duration) error
 	Exist(ctx context.Context, token string) (bool, error)
 }
 
 type IEmailSener interface {
-	SendVerificationEmail(ctx context.Context, email, verifyToken string) error
+	VerificationEmail(ctx context.Context, email, verifyToken string) (*mail.Message, error)
 }
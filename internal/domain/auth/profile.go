@@ -0,0 +1,31 @@
+package auth
+
+type Profile struct {
+	id             int
+	username       string
+	joinedAt       string
+	uploadsCount   int
+	followersCount int
+	totalLikes     int
+	totalDislikes  int
+}
+
+func (p *Profile) ID() int             { return p.id }
+func (p *Profile) Username() string    { return p.username }
+func (p *Profile) JoinedAt() string    { return p.joinedAt }
+func (p *Profile) UploadsCount() int   { return p.uploadsCount }
+func (p *Profile) FollowersCount() int { return p.followersCount }
+func (p *Profile) TotalLikes() int     { return p.totalLikes }
+func (p *Profile) TotalDislikes() int  { return p.totalDislikes }
+
+func RebuildProfileFromStorage(id int, username, joinedAt string, uploadsCount, followersCount, totalLikes, totalDislikes int) *Profile {
+	return &Profile{
+		id:             id,
+		username:       username,
+		joinedAt:       joinedAt,
+		uploadsCount:   uploadsCount,
+		followersCount: followersCount,
+		totalLikes:     totalLikes,
+		totalDislikes:  totalDislikes,
+	}
+}
@@ -0,0 +1,23 @@
+package auth
+
+type ProfileDTO struct {
+	ID             int    `json:"id"`
+	Username       string `json:"username"`
+	JoinedAt       string `json:"joined_at"`
+	UploadsCount   int    `json:"uploads_count"`
+	FollowersCount int    `json:"followers_count"`
+	TotalLikes     int    `json:"total_likes"`
+	TotalDislikes  int    `json:"total_dislikes"`
+}
+
+func (p *Profile) ToDTO() *ProfileDTO {
+	return &ProfileDTO{
+		ID:             p.id,
+		Username:       p.username,
+		JoinedAt:       p.joinedAt,
+		UploadsCount:   p.uploadsCount,
+		FollowersCount: p.followersCount,
+		TotalLikes:     p.totalLikes,
+		TotalDislikes:  p.totalDislikes,
+	}
+}
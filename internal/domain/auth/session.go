@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+// Session is one sign-in. Only the token's jti claim is kept, never the token itself.
+type Session struct {
+	id        string
+	userID    int
+	createdAt time.Time
+	expiresAt time.Time
+	endedAt   time.Time
+}
+
+func (s *Session) ID() string           { return s.id }
+func (s *Session) UserID() int          { return s.userID }
+func (s *Session) CreatedAt() time.Time { return s.createdAt }
+func (s *Session) ExpiresAt() time.Time { return s.expiresAt }
+
+// EndedAt is when the user logged out, zero while the session is open.
+func (s *Session) EndedAt() time.Time { return s.endedAt }
+
+func NewSession(id string, userID int, createdAt, expiresAt time.Time) (*Session, error) {
+	if id == "" {
+		return nil, errors.New("session id is requered")
+	}
+	if userID <= 0 {
+		return nil, errors.New("invalid user id")
+	}
+	if !expiresAt.After(createdAt) {
+		return nil, errors.New("session must expire after it starts")
+	}
+
+	return &Session{id: id, userID: userID, createdAt: createdAt, expiresAt: expiresAt}, nil
+}
+
+func RebuildSessionFromStorage(id string, userID int, createdAt, expiresAt, endedAt time.Time) *Session {
+	return &Session{id: id, userID: userID, createdAt: createdAt, expiresAt: expiresAt, endedAt: endedAt}
+}
@@ -5,11 +5,18 @@ import (
 	"soundtube/scripts"
 )
 
+// Roles a user can have. Everyone starts as RoleUser; admins are promoted in the database.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	id          int
 	username    string
 	email       string
 	password    string
+	role        string
 	isVerified  bool
 	isBanned    bool
 	verifyToken string
@@ -18,6 +25,7 @@ type User struct {
 func (u *User) ID() int          { return u.id }
 func (u *User) Username() string { return u.username }
 func (u *User) Email() string    { return u.email }
+func (u *User) Role() string     { return u.role }
 func (u *User) IsVerified() bool { return u.isVerified }
 func (u *User) IsBanned() bool   { return u.isBanned }
 
@@ -43,18 +51,20 @@ func NewUser(username, email, password, verifyToken string) (*User, error) {
 		username:    username,
 		email:       email,
 		password:    password,
+		role:        RoleUser,
 		verifyToken: verifyToken,
 		isVerified:  true,
 		isBanned:    false,
 	}, nil
 }
 
-func RebuildUserFromStorage(id int, username, email, password string, isVerified, isBanned bool, token string) *User {
+func RebuildUserFromStorage(id int, username, email, password, role string, isVerified, isBanned bool, token string) *User {
 	return &User{
 		id:          id,
 		username:    username,
 		email:       email,
 		password:    password,
+		role:        role,
 		isVerified:  isVerified,
 		isBanned:    isBanned,
 		verifyToken: token,
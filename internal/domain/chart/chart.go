@@ -0,0 +1,86 @@
+package chart
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	Window24h = "24h"
+	Window7d  = "7d"
+)
+
+// AllGenres is the chart key used when no genre filter is given.
+const AllGenres = "all"
+
+// wilsonZ is the z-score for a 95% confidence interval.
+const wilsonZ = 1.96
+
+var Windows = map[string]time.Duration{
+	Window24h: 24 * time.Hour,
+	Window7d:  7 * 24 * time.Hour,
+}
+
+func ParseWindow(window string) (string, time.Duration, error) {
+	if window == "" {
+		window = Window24h
+	}
+
+	duration, ok := Windows[window]
+	if !ok {
+		return "", 0, errors.New("window must be 24h or 7d")
+	}
+
+	return window, duration, nil
+}
+
+func NormalizeGenre(genre string) string {
+	genre = strings.ToLower(strings.TrimSpace(genre))
+	if genre == "" {
+		return AllGenres
+	}
+	return genre
+}
+
+type SoundStats struct {
+	SoundID     int
+	Genre       string
+	Likes       int
+	Dislikes    int
+	WindowPlays int64
+	UploadedAt  time.Time
+}
+
+// WilsonLowerBound is the lower bound of the Wilson score interval for the share of
+// likes, so a sound with 9 of 10 likes ranks below one with 90 of 100.
+func WilsonLowerBound(likes, dislikes int) float64 {
+	n := float64(likes + dislikes)
+	if n == 0 {
+		return 0
+	}
+
+	p := float64(likes) / n
+	z2 := wilsonZ * wilsonZ
+	return (p + z2/(2*n) - wilsonZ*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
+
+// HotScore combines approval and play velocity within the window and halves it for every
+// window length that has passed since upload, so fresh sounds can outrank old favorites.
+func (s *SoundStats) HotScore(now time.Time, window time.Duration) float64 {
+	velocity := float64(s.WindowPlays) / window.Hours()
+	base := WilsonLowerBound(s.Likes, s.Dislikes) + math.Log10(1+velocity)
+
+	age := now.Sub(s.UploadedAt)
+	if age < 0 {
+		age = 0
+	}
+
+	return base * math.Pow(0.5, age.Hours()/window.Hours())
+}
+
+type Entry struct {
+	SoundID int
+	Score   float64
+}
@@ -0,0 +1,102 @@
+package chart
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWilsonLowerBound(t *testing.T) {
+	tests := []struct {
+		likes    int
+		dislikes int
+		want     float64
+	}{
+		{likes: 0, dislikes: 0, want: 0},
+		{likes: 1, dislikes: 0, want: 0.2065},
+		{likes: 9, dislikes: 1, want: 0.5958},
+		{likes: 90, dislikes: 10, want: 0.8256},
+		{likes: 50, dislikes: 50, want: 0.4038},
+		{likes: 0, dislikes: 10, want: 0},
+		{likes: 1000, dislikes: 0, want: 0.9962},
+	}
+
+	for _, tt := range tests {
+		if got := WilsonLowerBound(tt.likes, tt.dislikes); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("WilsonLowerBound(%d, %d) = %.4f, want %.4f", tt.likes, tt.dislikes, got, tt.want)
+		}
+	}
+
+	if WilsonLowerBound(9, 1) >= WilsonLowerBound(90, 10) {
+		t.Errorf("9 of 10 likes should rank below 90 of 100")
+	}
+}
+
+func TestHotScore(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name  string
+		stats SoundStats
+		want  float64
+	}{
+		{name: "no activity", stats: SoundStats{UploadedAt: now}, want: 0},
+		{name: "plays only", stats: SoundStats{WindowPlays: 216, UploadedAt: now}, want: 1},
+		{name: "likes only", stats: SoundStats{Likes: 9, Dislikes: 1, UploadedAt: now}, want: 0.5958},
+		{name: "one window old halves", stats: SoundStats{WindowPlays: 216, UploadedAt: now.Add(-day)}, want: 0.5},
+		{name: "two windows old quarters", stats: SoundStats{WindowPlays: 216, UploadedAt: now.Add(-2 * day)}, want: 0.25},
+		{name: "future upload counts as new", stats: SoundStats{WindowPlays: 216, UploadedAt: now.Add(time.Hour)}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.HotScore(now, day); math.Abs(got-tt.want) > 1e-4 {
+				t.Fatalf("HotScore = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		window       string
+		wantWindow   string
+		wantDuration time.Duration
+		wantErr      bool
+	}{
+		{window: "", wantWindow: Window24h, wantDuration: 24 * time.Hour},
+		{window: "24h", wantWindow: Window24h, wantDuration: 24 * time.Hour},
+		{window: "7d", wantWindow: Window7d, wantDuration: 7 * 24 * time.Hour},
+		{window: "30d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		window, duration, err := ParseWindow(tt.window)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWindow(%q) err = %v, wantErr %v", tt.window, err, tt.wantErr)
+			continue
+		}
+		if window != tt.wantWindow || duration != tt.wantDuration {
+			t.Errorf("ParseWindow(%q) = %q, %s, want %q, %s", tt.window, window, duration, tt.wantWindow, tt.wantDuration)
+		}
+	}
+}
+
+func TestNormalizeGenre(t *testing.T) {
+	tests := []struct {
+		genre string
+		want  string
+	}{
+		{genre: "", want: AllGenres},
+		{genre: "   ", want: AllGenres},
+		{genre: " Hip-Hop ", want: "hip-hop"},
+		{genre: "jazz", want: "jazz"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeGenre(tt.genre); got != tt.want {
+			t.Errorf("NormalizeGenre(%q) = %q, want %q", tt.genre, got, tt.want)
+		}
+	}
+}
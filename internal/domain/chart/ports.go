@@ -0,0 +1,15 @@
+package chart
+
+import (
+	"context"
+	"time"
+)
+
+type IChartStatsRepository interface {
+	GetSoundStats(ctx context.Context, window time.Duration) ([]*SoundStats, error)
+}
+
+type IChartStore interface {
+	Replace(ctx context.Context, window, genre string, entries []Entry) error
+	Top(ctx context.Context, window, genre string, limit, offset int) ([]int, error)
+}
@@ -2,36 +2,103 @@ package comment
 
 import (
 	"errors"
+	"soundtube/scripts"
+	"strings"
+	"time"
 )
 
+const MaxContentLength = 2000
+
+var (
+	ErrEmptyContent   = errors.New("content is requered")
+	ErrContentTooLong = errors.New("content is too long")
+	ErrInvalidContent = errors.New("content contains forbidden markup")
+)
+
+// Comment is a comment on a sound. Replies point at the comment they answer with
+// parentID; top-level comments have none.
 type Comment struct {
-	id              int
-	content         string
-	isResponse      bool
-	responeTargetID int
+	id         int
+	soundID    int
+	authorID   int
+	authorName string
+	parentID   int
+	content    string
+	createdAt  time.Time
+	updatedAt  time.Time
 }
 
-func (c *Comment) ID() int               { return c.id }
-func (c *Comment) Content() string       { return c.content }
-func (c *Comment) IsResponse() bool      { return c.isResponse }
-func (c *Comment) ResponseTargetID() int { return c.responeTargetID }
+func (c *Comment) ID() int              { return c.id }
+func (c *Comment) SoundID() int         { return c.soundID }
+func (c *Comment) AuthorID() int        { return c.authorID }
+func (c *Comment) AuthorName() string   { return c.authorName }
+func (c *Comment) ParentID() int        { return c.parentID }
+func (c *Comment) IsReply() bool        { return c.parentID != 0 }
+func (c *Comment) Content() string      { return c.content }
+func (c *Comment) CreatedAt() time.Time { return c.createdAt }
+func (c *Comment) UpdatedAt() time.Time { return c.updatedAt }
+
+func NewComment(soundID, authorID, parentID int, content string) (*Comment, error) {
+	if soundID <= 0 {
+		return nil, errors.New("invalid sound id")
+	}
+	if authorID <= 0 {
+		return nil, errors.New("invalid author id")
+	}
+	if parentID < 0 {
+		return nil, errors.New("invalid parent comment id")
+	}
 
-func NewComment(content string, isResponse bool, responseTargetID int) (*Comment, error) {
-	if content == "" {
-		return nil, errors.New("content is requered")
+	content, err := validContent(content)
+	if err != nil {
+		return nil, err
 	}
+
+	now := time.Now()
 	return &Comment{
-		content:         content,
-		isResponse:      isResponse,
-		responeTargetID: responseTargetID,
+		soundID:   soundID,
+		authorID:  authorID,
+		parentID:  parentID,
+		content:   content,
+		createdAt: now,
+		updatedAt: now,
 	}, nil
 }
 
-func RestoreCommentFromStorage(id int, content string, isResponse bool, responseTargetID int, likes, dislikes int) *Comment {
+func RebuildCommentFromStorage(id, soundID, authorID int, authorName string, parentID int, content string, createdAt, updatedAt time.Time) *Comment {
 	return &Comment{
-		id:              id,
-		content:         content,
-		isResponse:      isResponse,
-		responeTargetID: responseTargetID,
+		id:         id,
+		soundID:    soundID,
+		authorID:   authorID,
+		authorName: authorName,
+		parentID:   parentID,
+		content:    content,
+		createdAt:  createdAt,
+		updatedAt:  updatedAt,
 	}
 }
+
+func (c *Comment) Edit(content string) error {
+	content, err := validContent(content)
+	if err != nil {
+		return err
+	}
+
+	c.content = content
+	c.updatedAt = time.Now()
+	return nil
+}
+
+func validContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "":
+		return "", ErrEmptyContent
+	case len([]rune(content)) > MaxContentLength:
+		return "", ErrContentTooLong
+	case scripts.ValidateXSS(content):
+		return "", ErrInvalidContent
+	}
+
+	return content, nil
+}
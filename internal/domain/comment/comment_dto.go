@@ -0,0 +1,36 @@
+package comment
+
+import (
+	"soundtube/internal/domain/sound"
+	"time"
+)
+
+type CommentDTO struct {
+	ID        int                 `json:"id"`
+	SoundID   int                 `json:"sound_id"`
+	ParentID  int                 `json:"parent_id,omitempty"`
+	Author    sound.AuthorSummary `json:"author"`
+	Content   string              `json:"content"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+func (c *Comment) ToDTO() *CommentDTO {
+	return &CommentDTO{
+		ID:        c.id,
+		SoundID:   c.soundID,
+		ParentID:  c.parentID,
+		Author:    sound.AuthorSummary{ID: c.authorID, Username: c.authorName},
+		Content:   c.content,
+		CreatedAt: c.createdAt,
+		UpdatedAt: c.updatedAt,
+	}
+}
+
+func CommentsToDTO(comments []*Comment) []*CommentDTO {
+	dtos := make([]*CommentDTO, 0, len(comments))
+	for _, c := range comments {
+		dtos = append(dtos, c.ToDTO())
+	}
+	return dtos
+}
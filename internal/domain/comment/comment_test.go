@@ -0,0 +1,63 @@
+package comment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		soundID  int
+		authorID int
+		parentID int
+		content  string
+		want     string
+		wantErr  error
+	}{
+		{name: "trims content", soundID: 1, authorID: 2, content: "  hello  ", want: "hello"},
+		{name: "reply", soundID: 1, authorID: 2, parentID: 3, content: "yes", want: "yes"},
+		{name: "empty", soundID: 1, authorID: 2, content: " \n ", wantErr: ErrEmptyContent},
+		{name: "too long", soundID: 1, authorID: 2, content: strings.Repeat("ы", MaxContentLength+1), wantErr: ErrContentTooLong},
+		{name: "max length", soundID: 1, authorID: 2, content: strings.Repeat("ы", MaxContentLength), want: strings.Repeat("ы", MaxContentLength)},
+		{name: "markup", soundID: 1, authorID: 2, content: "<b>hi</b>", wantErr: ErrInvalidContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewComment(tt.soundID, tt.authorID, tt.parentID, tt.content)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if c.Content() != tt.want {
+				t.Fatalf("content = %q, want %q", c.Content(), tt.want)
+			}
+			if c.IsReply() != (tt.parentID != 0) {
+				t.Fatalf("IsReply = %v for parent %d", c.IsReply(), tt.parentID)
+			}
+		})
+	}
+}
+
+func TestNewCommentRejectsInvalidIDs(t *testing.T) {
+	tests := []struct {
+		name                        string
+		soundID, authorID, parentID int
+	}{
+		{name: "no sound", authorID: 1},
+		{name: "no author", soundID: 1},
+		{name: "negative parent", soundID: 1, authorID: 1, parentID: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewComment(tt.soundID, tt.authorID, tt.parentID, "hi"); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package comment
+
+import "context"
+
+type ICommentRepository interface {
+	ICommentRepositoryReader
+	ICommentRepositoryWriter
+}
+
+type ICommentRepositoryReader interface {
+	GetCommentByID(ctx context.Context, id int) (*Comment, error)
+	// GetCommentsBySound returns a page of a sound's comments, oldest first.
+	GetCommentsBySound(ctx context.Context, soundID, limit, offset int) ([]*Comment, error)
+	GetCommentsByAuthor(ctx context.Context, authorID int) ([]*Comment, error)
+}
+
+type ICommentRepositoryWriter interface {
+	CreateComment(ctx context.Context, c *Comment) (int, error)
+	UpdateComment(ctx context.Context, c *Comment) error
+	DeleteComment(ctx context.Context, id int) error
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"errors"
+	"time"
+)
+
+type Archive struct {
+	id        string
+	userID    int
+	fileName  string
+	expiresAt time.Time
+}
+
+func (a *Archive) ID() string           { return a.id }
+func (a *Archive) UserID() int          { return a.userID }
+func (a *Archive) FileName() string     { return a.fileName }
+func (a *Archive) ExpiresAt() time.Time { return a.expiresAt }
+
+func (a *Archive) IsExpired(now time.Time) bool { return now.After(a.expiresAt) }
+
+func NewArchive(id string, userID int, ttl time.Duration) (*Archive, error) {
+	if id == "" {
+		return nil, errors.New("archive id cannot be empty")
+	}
+	if userID <= 0 {
+		return nil, errors.New("invalid user id")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("archive ttl must be positive")
+	}
+
+	return &Archive{
+		id:        id,
+		userID:    userID,
+		fileName:  id + ".zip",
+		expiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+type ProfileData struct {
+	ID         int    `json:"id"`
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	IsVerified bool   `json:"is_verified"`
+	IsBanned   bool   `json:"is_banned"`
+}
+
+type ReactionData struct {
+	SoundID   int    `json:"sound_id"`
+	ReactType string `json:"react_type"`
+	CreatedAt string `json:"created_at"`
+}
+
+type CommentData struct {
+	ID        int       `json:"id"`
+	SoundID   int       `json:"sound_id"`
+	ParentID  int       `json:"parent_id,omitempty"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SessionData describes a sign-in without the token, which would still be usable.
+type SessionData struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
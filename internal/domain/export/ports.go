@@ -0,0 +1,10 @@
+package export
+
+import (
+	"context"
+	"time"
+)
+
+type IExportEmailSender interface {
+	SendExportEmail(ctx context.Context, email, downloadLink string, expiresAt time.Time) error
+}
@@ -0,0 +1,36 @@
+package feature
+
+import "time"
+
+type FlagDTO struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Enabled     bool      `json:"enabled"`
+	Percentage  int       `json:"percentage"`
+	Users       []int     `json:"users"`
+	Roles       []string  `json:"roles"`
+	Source      string    `json:"source"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+}
+
+func (f *Flag) ToDTO() *FlagDTO {
+	dto := &FlagDTO{
+		Name:        f.name,
+		Description: f.description,
+		Enabled:     f.enabled,
+		Percentage:  f.percentage,
+		Users:       f.users,
+		Roles:       f.roles,
+		Source:      f.source,
+	}
+	if dto.Users == nil {
+		dto.Users = []int{}
+	}
+	if dto.Roles == nil {
+		dto.Roles = []string{}
+	}
+	if f.source == SourceOverride {
+		dto.UpdatedAt = f.updatedAt
+	}
+	return dto
+}
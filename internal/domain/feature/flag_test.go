@@ -0,0 +1,170 @@
+package feature
+
+import (
+	"errors"
+	"testing"
+)
+
+func mustFlag(t *testing.T, enabled bool, percentage int, users []int, roles []string) *Flag {
+	t.Helper()
+
+	f, err := NewFlag("new_player", "", enabled, percentage, users, roles, SourceConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// userInBucket finds a user whose bucket for the flag is inside [from, to).
+func userInBucket(t *testing.T, name string, from, to int) int {
+	t.Helper()
+
+	for userID := 1; userID < 10000; userID++ {
+		if b := Bucket(name, userID); b >= from && b < to {
+			return userID
+		}
+	}
+	t.Fatalf("no user in bucket [%d, %d)", from, to)
+	return 0
+}
+
+func TestNewFlag(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       string
+		percentage int
+		wantErr    error
+	}{
+		{name: "valid", flag: "new_player", percentage: 50},
+		{name: "dashes and digits", flag: "v2-search", percentage: 0},
+		{name: "upper case", flag: "NewPlayer", percentage: 50, wantErr: ErrInvalidName},
+		{name: "leading dash", flag: "-player", percentage: 50, wantErr: ErrInvalidName},
+		{name: "empty", flag: "", percentage: 50, wantErr: ErrInvalidName},
+		{name: "over 100", flag: "new_player", percentage: 101, wantErr: ErrInvalidPercentage},
+		{name: "negative", flag: "new_player", percentage: -1, wantErr: ErrInvalidPercentage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFlag(tt.flag, "", true, tt.percentage, nil, nil, SourceConfig)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFlagEnabledFor(t *testing.T) {
+	inside := userInBucket(t, "new_player", 0, 10)
+	outside := userInBucket(t, "new_player", 90, 100)
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		percentage int
+		users      []int
+		roles      []string
+		subject    Subject
+		want       bool
+	}{
+		{name: "kill switch beats everything", enabled: false, percentage: 100, users: []int{inside}, subject: Subject{UserID: inside}, want: false},
+		{name: "full rollout", enabled: true, percentage: 100, subject: Subject{UserID: outside}, want: true},
+		{name: "full rollout for anonymous", enabled: true, percentage: 100, subject: Subject{}, want: true},
+		{name: "partial rollout hides from anonymous", enabled: true, percentage: 99, subject: Subject{}, want: false},
+		{name: "user inside the percentage", enabled: true, percentage: 10, subject: Subject{UserID: inside}, want: true},
+		{name: "user outside the percentage", enabled: true, percentage: 10, subject: Subject{UserID: outside}, want: false},
+		{name: "listed user", enabled: true, percentage: 0, users: []int{outside}, subject: Subject{UserID: outside}, want: true},
+		{name: "listed role", enabled: true, percentage: 0, roles: []string{"admin"}, subject: Subject{UserID: outside, Role: "admin"}, want: true},
+		{name: "other role", enabled: true, percentage: 0, roles: []string{"admin"}, subject: Subject{UserID: outside, Role: "user"}, want: false},
+		{name: "zero percent", enabled: true, percentage: 0, subject: Subject{UserID: inside}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := mustFlag(t, tt.enabled, tt.percentage, tt.users, tt.roles)
+			if got := f.EnabledFor(tt.subject); got != tt.want {
+				t.Fatalf("EnabledFor(%+v) = %v, want %v", tt.subject, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucket(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   string
+		userID int
+	}{
+		{name: "first user", flag: "new_player", userID: 1},
+		{name: "large id", flag: "new_player", userID: 1 << 30},
+		{name: "other flag", flag: "comments", userID: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := Bucket(tt.flag, tt.userID)
+			if bucket < 0 || bucket > 99 {
+				t.Fatalf("Bucket = %d, want 0-99", bucket)
+			}
+			if again := Bucket(tt.flag, tt.userID); again != bucket {
+				t.Fatalf("Bucket is not stable: %d then %d", bucket, again)
+			}
+		})
+	}
+}
+
+func TestBucketRollout(t *testing.T) {
+	const users = 20000
+
+	tests := []struct {
+		name       string
+		flag       string
+		percentage int
+	}{
+		{name: "1 percent", flag: "new_player", percentage: 1},
+		{name: "10 percent", flag: "new_player", percentage: 10},
+		{name: "50 percent", flag: "comments", percentage: 50},
+		{name: "90 percent", flag: "recommendations", percentage: 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			smaller := mustFlag(t, true, tt.percentage, nil, nil)
+			smaller.name = tt.flag
+			larger := mustFlag(t, true, tt.percentage+5, nil, nil)
+			larger.name = tt.flag
+
+			enabled := 0
+			for userID := 1; userID <= users; userID++ {
+				subject := Subject{UserID: userID}
+				if !smaller.EnabledFor(subject) {
+					continue
+				}
+				enabled++
+				if !larger.EnabledFor(subject) {
+					t.Fatalf("user %d lost the feature when the rollout grew", userID)
+				}
+			}
+
+			// Allow two points of deviation from the requested share.
+			share := float64(enabled) * 100 / users
+			if share < float64(tt.percentage)-2 || share > float64(tt.percentage)+2 {
+				t.Fatalf("%.2f%% of users enabled, want about %d%%", share, tt.percentage)
+			}
+		})
+	}
+}
+
+func TestBucketDependsOnFlag(t *testing.T) {
+	same := 0
+	for userID := 1; userID <= 1000; userID++ {
+		if Bucket("new_player", userID) < 10 && Bucket("comments", userID) < 10 {
+			same++
+		}
+	}
+
+	// Independent 10% rollouts overlap on about 1% of users, not on all of them.
+	if same > 30 {
+		t.Fatalf("%d of 1000 users are in both 10%% rollouts", same)
+	}
+}
@@ -0,0 +1,18 @@
+package feature
+
+import "context"
+
+// IFlagRepository stores the overrides admins set on top of the configured flags.
+type IFlagRepository interface {
+	GetFlags(ctx context.Context) ([]*Flag, error)
+	SaveFlag(ctx context.Context, flag *Flag) error
+	DeleteFlag(ctx context.Context, name string) (bool, error)
+}
+
+// IFlagNotifier tells every server instance that the overrides changed so they drop
+// their cached copy.
+type IFlagNotifier interface {
+	Publish(ctx context.Context, name string) error
+	Listen(handle func(name string)) error
+	Close() error
+}
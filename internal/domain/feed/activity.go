@@ -0,0 +1,58 @@
+package feed
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	TypeSound   = "sound"
+	TypeRepost  = "repost"
+	TypeComment = "comment"
+)
+
+type Activity struct {
+	id           int
+	actorID      int
+	actorName    string
+	activityType string
+	soundID      int
+	createdAt    time.Time
+}
+
+func (a *Activity) ID() int              { return a.id }
+func (a *Activity) ActorID() int         { return a.actorID }
+func (a *Activity) ActorName() string    { return a.actorName }
+func (a *Activity) Type() string         { return a.activityType }
+func (a *Activity) SoundID() int         { return a.soundID }
+func (a *Activity) CreatedAt() time.Time { return a.createdAt }
+
+func NewActivity(actorID int, activityType string, soundID int) (*Activity, error) {
+	if actorID <= 0 {
+		return nil, errors.New("invalid actor id")
+	}
+	if activityType != TypeSound && activityType != TypeRepost && activityType != TypeComment {
+		return nil, errors.New("invalid activity type")
+	}
+	if soundID <= 0 {
+		return nil, errors.New("invalid sound id")
+	}
+
+	return &Activity{
+		actorID:      actorID,
+		activityType: activityType,
+		soundID:      soundID,
+		createdAt:    time.Now(),
+	}, nil
+}
+
+func RebuildActivityFromStorage(id, actorID int, actorName, activityType string, soundID int, createdAt time.Time) *Activity {
+	return &Activity{
+		id:           id,
+		actorID:      actorID,
+		actorName:    actorName,
+		activityType: activityType,
+		soundID:      soundID,
+		createdAt:    createdAt,
+	}
+}
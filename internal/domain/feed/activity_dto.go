@@ -0,0 +1,30 @@
+package feed
+
+import (
+	"soundtube/internal/domain/sound"
+	"time"
+)
+
+type ActivityDTO struct {
+	ID        int                 `json:"id"`
+	Type      string              `json:"type"`
+	Actor     sound.AuthorSummary `json:"actor"`
+	Sound     *sound.SoundDTO     `json:"sound"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+func (a *Activity) ToDTO(s *sound.Sound) *ActivityDTO {
+	dto := &ActivityDTO{
+		ID:   a.id,
+		Type: a.activityType,
+		Actor: sound.AuthorSummary{
+			ID:       a.actorID,
+			Username: a.actorName,
+		},
+		CreatedAt: a.createdAt,
+	}
+	if s != nil {
+		dto.Sound = s.ToDTO()
+	}
+	return dto
+}
@@ -0,0 +1,32 @@
+package feed
+
+import (
+	"context"
+	"time"
+)
+
+type IActivityRepository interface {
+	// Create stores the activity and returns its id, or 0 when the user has already
+	// reposted the sound.
+	Create(ctx context.Context, activity *Activity) (int, error)
+	DeleteRepost(ctx context.Context, actorID, soundID int) error
+	GetByIDs(ctx context.Context, ids []int) ([]*Activity, error)
+	GetByActors(ctx context.Context, actorIDs []int, before time.Time, limit int) ([]*Activity, error)
+}
+
+type IFeedStore interface {
+	Push(ctx context.Context, userIDs []int, activityID int, at time.Time) error
+	Range(ctx context.Context, userID int, before time.Time, limit int) ([]int, error)
+	Add(ctx context.Context, userID int, activities []*Activity) error
+	Remove(ctx context.Context, userID int, activityIDs []int) error
+}
+
+type IActivityPublisher interface {
+	Publish(ctx context.Context, actorID int, activityType string, soundID int) error
+}
+
+// IFollowFeed keeps a user's fanned-out feed in step with the accounts they follow.
+type IFollowFeed interface {
+	Backfill(ctx context.Context, followerID, followeeID int) error
+	Purge(ctx context.Context, followerID, followeeID int) error
+}
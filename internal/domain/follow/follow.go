@@ -0,0 +1,30 @@
+package follow
+
+import "errors"
+
+type Follow struct {
+	followerID int
+	followeeID int
+}
+
+func (f *Follow) FollowerID() int { return f.followerID }
+func (f *Follow) FolloweeID() int { return f.followeeID }
+
+func NewFollow(followerID, followeeID int) (*Follow, error) {
+	if followerID <= 0 || followeeID <= 0 {
+		return nil, errors.New("invalid user id")
+	}
+	if followerID == followeeID {
+		return nil, errors.New("cannot follow yourself")
+	}
+
+	return &Follow{
+		followerID: followerID,
+		followeeID: followeeID,
+	}, nil
+}
+
+type UserSummary struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
@@ -0,0 +1,22 @@
+package follow
+
+import "context"
+
+type IFollowRepository interface {
+	IFollowRepositoryReader
+	IFollowRepositoryWriter
+}
+
+type IFollowRepositoryReader interface {
+	GetFollowers(ctx context.Context, userID, limit, offset int) ([]*UserSummary, error)
+	GetFollowing(ctx context.Context, userID, limit, offset int) ([]*UserSummary, error)
+	GetFollowerIDs(ctx context.Context, userID int) ([]int, error)
+	CountFollowers(ctx context.Context, userID int) (int, error)
+	GetLargeFollowingIDs(ctx context.Context, userID, threshold int) ([]int, error)
+}
+
+// Follow reports whether the follow was new; following someone again is a no-op.
+type IFollowRepositoryWriter interface {
+	Follow(ctx context.Context, follow *Follow) (bool, error)
+	Unfollow(ctx context.Context, followerID, followeeID int) error
+}
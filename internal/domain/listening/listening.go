@@ -0,0 +1,109 @@
+package listening
+
+import (
+	"errors"
+	"soundtube/internal/domain/sound"
+	"time"
+)
+
+const (
+	EventStart    = "start"
+	EventProgress = "progress"
+	EventComplete = "complete"
+)
+
+const maxSessionIDLength = 64
+
+type Event struct {
+	userID    int
+	soundID   int
+	sessionID string
+	kind      string
+	position  int
+}
+
+func (e *Event) UserID() int       { return e.userID }
+func (e *Event) SoundID() int      { return e.soundID }
+func (e *Event) SessionID() string { return e.sessionID }
+func (e *Event) Kind() string      { return e.kind }
+func (e *Event) Position() int     { return e.position }
+
+// NewEvent validates a player event. Position is the playback offset in seconds.
+func NewEvent(userID, soundID int, sessionID, kind string, position int) (*Event, error) {
+	if userID <= 0 || soundID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	if sessionID == "" || len(sessionID) > maxSessionIDLength {
+		return nil, errors.New("invalid session id")
+	}
+	if kind != EventStart && kind != EventProgress && kind != EventComplete {
+		return nil, errors.New("unknown event type: " + kind)
+	}
+	if position < 0 {
+		return nil, errors.New("position cannot be negative")
+	}
+
+	return &Event{
+		userID:    userID,
+		soundID:   soundID,
+		sessionID: sessionID,
+		kind:      kind,
+		position:  position,
+	}, nil
+}
+
+// CountsAsPlay reports whether the event makes the session a play: the listener either
+// reached the threshold or finished a sound shorter than it. Duration is 0 when unknown.
+// Elapsed is the server time since the session started and must cover the same span as
+// the reported position, so a client cannot claim a play by sending a large offset.
+func (e *Event) CountsAsPlay(threshold, duration int, elapsed time.Duration) bool {
+	if e.kind == EventStart {
+		return false
+	}
+
+	listened := threshold
+	if e.position < threshold {
+		if e.kind != EventComplete || duration <= 0 || duration >= threshold {
+			return false
+		}
+		listened = duration
+	}
+
+	return elapsed >= time.Duration(listened)*time.Second
+}
+
+type Play struct {
+	UserID    int
+	SoundID   int
+	SessionID string
+	Seconds   int
+	PlayedAt  time.Time
+}
+
+func NewPlay(e *Event, at time.Time) *Play {
+	return &Play{
+		UserID:    e.userID,
+		SoundID:   e.soundID,
+		SessionID: e.sessionID,
+		Seconds:   e.position,
+		PlayedAt:  at,
+	}
+}
+
+type HistoryEntry struct {
+	Sound    *sound.Sound
+	PlayedAt time.Time
+}
+
+type HistoryEntryDTO struct {
+	Sound    *sound.SoundDTO `json:"sound"`
+	PlayedAt time.Time       `json:"played_at"`
+}
+
+func HistoryToDTO(entries []*HistoryEntry) []*HistoryEntryDTO {
+	dtos := make([]*HistoryEntryDTO, len(entries))
+	for i, e := range entries {
+		dtos[i] = &HistoryEntryDTO{Sound: e.Sound.ToDTO(), PlayedAt: e.PlayedAt}
+	}
+	return dtos
+}
@@ -0,0 +1,75 @@
+package listening
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewEvent(t *testing.T) {
+	longSession := strings.Repeat("s", maxSessionIDLength+1)
+
+	cases := map[string]struct {
+		userID, soundID int
+		sessionID, kind string
+		position        int
+		wantErr         string
+	}{
+		"start":             {1, 2, "s1", EventStart, 0, ""},
+		"progress":          {1, 2, "s1", EventProgress, 30, ""},
+		"missing user":      {0, 2, "s1", EventStart, 0, "invalid id"},
+		"missing sound":     {1, 0, "s1", EventStart, 0, "invalid id"},
+		"empty session":     {1, 2, "", EventStart, 0, "invalid session id"},
+		"long session":      {1, 2, longSession, EventStart, 0, "invalid session id"},
+		"unknown kind":      {1, 2, "s1", "pause", 0, "unknown event type: pause"},
+		"negative position": {1, 2, "s1", EventProgress, -1, "position cannot be negative"},
+	}
+
+	for name, tc := range cases {
+		_, err := NewEvent(tc.userID, tc.soundID, tc.sessionID, tc.kind, tc.position)
+
+		var got string
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tc.wantErr {
+			t.Errorf("%s: err = %q, want %q", name, got, tc.wantErr)
+		}
+	}
+}
+
+func TestCountsAsPlay(t *testing.T) {
+	const threshold = 30
+
+	tests := []struct {
+		name     string
+		kind     string
+		position int
+		duration int
+		elapsed  time.Duration
+		want     bool
+	}{
+		{name: "start never counts", kind: EventStart, position: 60, elapsed: time.Minute, want: false},
+		{name: "progress below threshold", kind: EventProgress, position: 29, elapsed: time.Minute, want: false},
+		{name: "progress at threshold", kind: EventProgress, position: 30, elapsed: 30 * time.Second, want: true},
+		{name: "progress ahead of the clock", kind: EventProgress, position: 30, elapsed: 5 * time.Second, want: false},
+		{name: "complete past threshold", kind: EventComplete, position: 45, elapsed: 45 * time.Second, want: true},
+		{name: "complete short sound", kind: EventComplete, position: 12, duration: 12, elapsed: 12 * time.Second, want: true},
+		{name: "complete short sound too soon", kind: EventComplete, position: 12, duration: 12, elapsed: time.Second, want: false},
+		{name: "complete skipped to end of long sound", kind: EventComplete, position: 12, duration: 240, elapsed: time.Minute, want: false},
+		{name: "complete unknown duration", kind: EventComplete, position: 12, duration: 0, elapsed: time.Minute, want: false},
+		{name: "progress on short sound", kind: EventProgress, position: 12, duration: 12, elapsed: time.Minute, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewEvent(1, 2, "s1", tt.kind, tt.position)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := event.CountsAsPlay(threshold, tt.duration, tt.elapsed); got != tt.want {
+				t.Fatalf("CountsAsPlay = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
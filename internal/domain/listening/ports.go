@@ -0,0 +1,20 @@
+package listening
+
+import (
+	"context"
+	"time"
+)
+
+type IPlayRepository interface {
+	SavePlays(ctx context.Context, plays []*Play) error
+	GetHistory(ctx context.Context, userID, limit, offset int) ([]*HistoryEntry, error)
+}
+
+// ISessionTracker remembers when listening sessions started and which were already
+// counted as a play.
+type ISessionTracker interface {
+	Start(ctx context.Context, userID int, sessionID string, soundID int, at time.Time) error
+	// StartedAt reports false when the session never sent a start event or has expired.
+	StartedAt(ctx context.Context, userID int, sessionID string, soundID int) (time.Time, bool, error)
+	MarkCounted(ctx context.Context, userID int, sessionID string, soundID int) (bool, error)
+}
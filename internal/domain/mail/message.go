@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+)
+
+const (
+	TransportSMTP   = "smtp"
+	TransportFile   = "file"
+	TransportMemory = "memory"
+)
+
+// Message is an outgoing email. The sender address is set by the transport.
+type Message struct {
+	id       int
+	to       string
+	subject  string
+	html     string
+	text     string
+	headers  map[string][]string
+	attempts int
+}
+
+func (m *Message) ID() int                      { return m.id }
+func (m *Message) To() string                   { return m.to }
+func (m *Message) Subject() string              { return m.subject }
+func (m *Message) HTML() string                 { return m.html }
+func (m *Message) Text() string                 { return m.text }
+func (m *Message) Headers() map[string][]string { return m.headers }
+func (m *Message) Attempts() int                { return m.attempts }
+
+func NewMessage(to, subject, html, text string, headers map[string][]string) (*Message, error) {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return nil, errors.New("invalid recipient address")
+	}
+	if subject == "" {
+		return nil, errors.New("subject is required")
+	}
+	if html == "" && text == "" {
+		return nil, errors.New("message body is required")
+	}
+
+	return &Message{to: to, subject: subject, html: html, text: text, headers: headers}, nil
+}
+
+func RebuildMessageFromStorage(id int, to, subject, html, text string, headers map[string][]string, attempts int) *Message {
+	return &Message{id: id, to: to, subject: subject, html: html, text: text, headers: headers, attempts: attempts}
+}
+
+// RetryDelay is how long to wait before the next delivery attempt after attempts
+// failed ones: 1, 4, 9, ... minutes, capped at six hours.
+func RetryDelay(attempts int) time.Duration {
+	delay := time.Duration(attempts*attempts) * time.Minute
+	return min(delay, 6*time.Hour)
+}
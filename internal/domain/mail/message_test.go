@@ -0,0 +1,62 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		to      string
+		subject string
+		html    string
+		text    string
+		wantErr string
+	}{
+		{name: "html only", to: "user@example.com", subject: "Hi", html: "<p>Hi</p>"},
+		{name: "text only", to: "User <user@example.com>", subject: "Hi", text: "Hi"},
+		{name: "bad recipient", to: "not-an-address", subject: "Hi", text: "Hi", wantErr: "invalid recipient address"},
+		{name: "empty recipient", to: "", subject: "Hi", text: "Hi", wantErr: "invalid recipient address"},
+		{name: "no subject", to: "user@example.com", text: "Hi", wantErr: "subject is required"},
+		{name: "no body", to: "user@example.com", subject: "Hi", wantErr: "message body is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := NewMessage(tt.to, tt.subject, tt.html, tt.text, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if msg.To() != tt.to || msg.Attempts() != 0 {
+					t.Fatalf("got to=%q attempts=%d", msg.To(), msg.Attempts())
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 4 * time.Minute},
+		{attempts: 3, want: 9 * time.Minute},
+		{attempts: 18, want: 324 * time.Minute},
+		{attempts: 19, want: 6 * time.Hour},
+		{attempts: 100, want: 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := RetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("RetryDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"context"
+	"time"
+)
+
+// IMailer delivers a message right away.
+type IMailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// IOutbox stores messages for delivery. Callers never talk to a mailer directly, so a
+// message is only lost if the data it belongs to is lost too.
+type IOutbox interface {
+	Enqueue(ctx context.Context, msg *Message) error
+}
+
+type IOutboxRepository interface {
+	IOutbox
+	// ClaimDue leases up to limit pending messages for lease, so that concurrent relays
+	// do not send the same message twice.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*Message, error)
+	MarkSent(ctx context.Context, id int) error
+	MarkFailed(ctx context.Context, id int, reason string, retryIn time.Duration) error
+	MarkDead(ctx context.Context, id int, reason string) error
+}
@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	FrequencyOff    = "off"
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+var ErrInvalidFrequency = errors.New("invalid digest frequency")
+
+func ValidFrequency(frequency string) bool {
+	return frequency == FrequencyOff || frequency == FrequencyDaily || frequency == FrequencyWeekly
+}
+
+// DigestSettings is how often a user gets the email digest and in which language. Empty
+// fields mean the server defaults.
+type DigestSettings struct {
+	userID    int
+	frequency string
+	locale    string
+}
+
+func (d *DigestSettings) UserID() int       { return d.userID }
+func (d *DigestSettings) Frequency() string { return d.frequency }
+func (d *DigestSettings) Locale() string    { return d.locale }
+
+func NewDigestSettings(userID int, frequency, locale string) (*DigestSettings, error) {
+	if userID <= 0 {
+		return nil, errors.New("invalid user id")
+	}
+	if frequency != "" && !ValidFrequency(frequency) {
+		return nil, ErrInvalidFrequency
+	}
+
+	return &DigestSettings{userID: userID, frequency: frequency, locale: locale}, nil
+}
+
+func RebuildDigestSettingsFromStorage(userID int, frequency, locale string) *DigestSettings {
+	return &DigestSettings{userID: userID, frequency: frequency, locale: locale}
+}
+
+type DigestSettingsDTO struct {
+	Frequency string `json:"frequency"`
+	Locale    string `json:"locale"`
+}
+
+// DigestClaimLease is how long a claimed digest stays hidden from other instances. If
+// the claiming instance dies before reporting back, the digest becomes due again after it.
+const DigestClaimLease = time.Hour
+
+// Digests that fail to send are retried after DigestRetryBase, doubling with every
+// consecutive failure up to DigestRetryMax.
+const (
+	DigestRetryBase = 15 * time.Minute
+	DigestRetryMax  = 24 * time.Hour
+)
+
+// DigestRetryDelay is how long to wait before retrying a digest that failed failures
+// times in a row.
+func DigestRetryDelay(failures int) time.Duration {
+	delay := DigestRetryBase
+	for i := 1; i < failures && delay < DigestRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, DigestRetryMax)
+}
+
+// DigestRecipient is a user whose digest is due, with unread notifications newer than Since.
+// Failures counts the consecutive attempts that failed before this one.
+type DigestRecipient struct {
+	UserID    int
+	Email     string
+	Username  string
+	Frequency string
+	Locale    string
+	Since     time.Time
+	Failures  int
+}
+
+// Digest is the data the digest email templates are rendered with.
+type Digest struct {
+	Username  string
+	Frequency string
+	Items     []*Notification
+	More      int
+	Link      string
+}
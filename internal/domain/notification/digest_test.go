@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigestRetryDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 0, want: 15 * time.Minute},
+		{failures: 1, want: 15 * time.Minute},
+		{failures: 2, want: 30 * time.Minute},
+		{failures: 3, want: time.Hour},
+		{failures: 7, want: 16 * time.Hour},
+		{failures: 8, want: DigestRetryMax},
+		{failures: 1000, want: DigestRetryMax},
+	}
+
+	for _, tt := range tests {
+		if got := DigestRetryDelay(tt.failures); got != tt.want {
+			t.Errorf("DigestRetryDelay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestNewDigestSettings(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		frequency string
+		wantErr   bool
+	}{
+		{name: "server default", userID: 1},
+		{name: "daily", userID: 1, frequency: FrequencyDaily},
+		{name: "off", userID: 1, frequency: FrequencyOff},
+		{name: "unknown frequency", userID: 1, frequency: "hourly", wantErr: true},
+		{name: "invalid user", userID: 0, frequency: FrequencyWeekly, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := NewDigestSettings(tt.userID, tt.frequency, "en")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && settings.Frequency() != tt.frequency {
+				t.Fatalf("frequency = %q, want %q", settings.Frequency(), tt.frequency)
+			}
+		})
+	}
+}
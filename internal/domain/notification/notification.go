@@ -0,0 +1,160 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	TypeComment    = "comment"
+	TypeReply      = "reply"
+	TypeFollow     = "follow"
+	TypeLike       = "like"
+	TypeModeration = "moderation"
+)
+
+// Types lists every notification type a user can switch off.
+var Types = []string{TypeComment, TypeReply, TypeFollow, TypeLike, TypeModeration}
+
+var ErrInvalidType = errors.New("invalid notification type")
+
+func ValidType(notificationType string) bool {
+	for _, t := range Types {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is one entry in a user's inbox. Unread notifications with the same
+// group key are merged, so it can stand for several actors; the latest one is kept.
+type Notification struct {
+	id               int
+	recipientID      int
+	actorID          int
+	actorName        string
+	actorCount       int
+	notificationType string
+	soundID          int
+	soundName        string
+	commentID        int
+	detail           string
+	read             bool
+	updatedAt        time.Time
+}
+
+func (n *Notification) ID() int              { return n.id }
+func (n *Notification) RecipientID() int     { return n.recipientID }
+func (n *Notification) ActorID() int         { return n.actorID }
+func (n *Notification) ActorName() string    { return n.actorName }
+func (n *Notification) ActorCount() int      { return n.actorCount }
+func (n *Notification) Type() string         { return n.notificationType }
+func (n *Notification) SoundID() int         { return n.soundID }
+func (n *Notification) SoundName() string    { return n.soundName }
+func (n *Notification) CommentID() int       { return n.commentID }
+func (n *Notification) Detail() string       { return n.detail }
+func (n *Notification) Read() bool           { return n.read }
+func (n *Notification) UpdatedAt() time.Time { return n.updatedAt }
+
+// GroupKey identifies the notifications that aggregate together. Moderation outcomes
+// are never merged.
+func (n *Notification) GroupKey() string {
+	switch n.notificationType {
+	case TypeLike, TypeComment:
+		return fmt.Sprintf("%s:%d", n.notificationType, n.soundID)
+	case TypeReply:
+		return fmt.Sprintf("%s:%d", n.notificationType, n.commentID)
+	case TypeFollow:
+		return TypeFollow
+	default:
+		return ""
+	}
+}
+
+// NewNotification validates an event emitted by another service. actorID is zero for
+// system events such as moderation outcomes.
+func NewNotification(recipientID, actorID int, notificationType string, soundID, commentID int, detail string) (*Notification, error) {
+	if recipientID <= 0 {
+		return nil, errors.New("invalid recipient id")
+	}
+	if actorID < 0 {
+		return nil, errors.New("invalid actor id")
+	}
+	if !ValidType(notificationType) {
+		return nil, ErrInvalidType
+	}
+
+	switch notificationType {
+	case TypeLike, TypeComment, TypeModeration:
+		if soundID <= 0 {
+			return nil, errors.New("invalid sound id")
+		}
+	case TypeReply:
+		if commentID <= 0 {
+			return nil, errors.New("invalid comment id")
+		}
+	}
+
+	if notificationType == TypeModeration {
+		if detail == "" {
+			return nil, errors.New("moderation outcome is required")
+		}
+	} else if actorID == 0 {
+		return nil, errors.New("invalid actor id")
+	}
+
+	return &Notification{
+		recipientID:      recipientID,
+		actorID:          actorID,
+		notificationType: notificationType,
+		soundID:          soundID,
+		commentID:        commentID,
+		detail:           detail,
+	}, nil
+}
+
+func RebuildNotificationFromStorage(id, recipientID, actorID int, actorName string, actorCount int, notificationType string,
+	soundID int, soundName string, commentID int, detail string, read bool, updatedAt time.Time) *Notification {
+	return &Notification{
+		id:               id,
+		recipientID:      recipientID,
+		actorID:          actorID,
+		actorName:        actorName,
+		actorCount:       actorCount,
+		notificationType: notificationType,
+		soundID:          soundID,
+		soundName:        soundName,
+		commentID:        commentID,
+		detail:           detail,
+		read:             read,
+		updatedAt:        updatedAt,
+	}
+}
+
+// Message renders the notification as a sentence, e.g. `12 people liked "Intro"`.
+func (n *Notification) Message() string {
+	who := n.actorName
+	if n.actorCount > 1 {
+		who = fmt.Sprintf("%d people", n.actorCount)
+	}
+
+	switch n.notificationType {
+	case TypeLike:
+		return fmt.Sprintf("%s liked %q", who, n.soundName)
+	case TypeComment:
+		return fmt.Sprintf("%s commented on %q", who, n.soundName)
+	case TypeReply:
+		return fmt.Sprintf("%s replied to your comment", who)
+	case TypeFollow:
+		return fmt.Sprintf("%s started following you", who)
+	case TypeModeration:
+		if n.commentID != 0 {
+			return fmt.Sprintf("Your comment on %q was %s", n.soundName, n.detail)
+		}
+		return fmt.Sprintf("Your sound %q was %s", n.soundName, n.detail)
+	default:
+		return ""
+	}
+}
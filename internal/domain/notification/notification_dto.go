@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"soundtube/internal/domain/sound"
+	"time"
+)
+
+type NotificationDTO struct {
+	ID         int                  `json:"id"`
+	Type       string               `json:"type"`
+	Message    string               `json:"message"`
+	Actor      *sound.AuthorSummary `json:"actor,omitempty"`
+	ActorCount int                  `json:"actor_count"`
+	SoundID    int                  `json:"sound_id,omitempty"`
+	CommentID  int                  `json:"comment_id,omitempty"`
+	Read       bool                 `json:"read"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+type InboxDTO struct {
+	Notifications []*NotificationDTO `json:"notifications"`
+	UnreadCount   int                `json:"unread_count"`
+}
+
+func (n *Notification) ToDTO() *NotificationDTO {
+	dto := &NotificationDTO{
+		ID:         n.id,
+		Type:       n.notificationType,
+		Message:    n.Message(),
+		ActorCount: n.actorCount,
+		SoundID:    n.soundID,
+		CommentID:  n.commentID,
+		Read:       n.read,
+		UpdatedAt:  n.updatedAt,
+	}
+	if n.actorID != 0 {
+		dto.Actor = &sound.AuthorSummary{ID: n.actorID, Username: n.actorName}
+	}
+	return dto
+}
+
+func ToInboxDTO(notifications []*Notification, unread int) *InboxDTO {
+	dtos := make([]*NotificationDTO, 0, len(notifications))
+	for _, n := range notifications {
+		dtos = append(dtos, n.ToDTO())
+	}
+	return &InboxDTO{Notifications: dtos, UnreadCount: unread}
+}
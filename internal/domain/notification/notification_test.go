@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       string
+		actorCount int
+		commentID  int
+		detail     string
+		want       string
+	}{
+		{name: "like", kind: TypeLike, actorCount: 1, want: `alice liked "Intro"`},
+		{name: "aggregated likes", kind: TypeLike, actorCount: 12, want: `12 people liked "Intro"`},
+		{name: "comment", kind: TypeComment, actorCount: 1, commentID: 4, want: `alice commented on "Intro"`},
+		{name: "reply", kind: TypeReply, actorCount: 2, commentID: 4, want: "2 people replied to your comment"},
+		{name: "follow", kind: TypeFollow, actorCount: 1, want: "alice started following you"},
+		{name: "sound moderated", kind: TypeModeration, detail: "removed", want: `Your sound "Intro" was removed`},
+		{name: "comment moderated", kind: TypeModeration, commentID: 4, detail: "removed", want: `Your comment on "Intro" was removed`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := RebuildNotificationFromStorage(1, 2, 3, "alice", tt.actorCount, tt.kind, 5, "Intro", tt.commentID, tt.detail, false, time.Now())
+			if got := n.Message(); got != tt.want {
+				t.Fatalf("Message() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNotification(t *testing.T) {
+	tests := []struct {
+		name      string
+		recipient int
+		actor     int
+		kind      string
+		soundID   int
+		commentID int
+		detail    string
+		wantErr   bool
+	}{
+		{name: "like", recipient: 1, actor: 2, kind: TypeLike, soundID: 3},
+		{name: "like without sound", recipient: 1, actor: 2, kind: TypeLike, wantErr: true},
+		{name: "reply", recipient: 1, actor: 2, kind: TypeReply, commentID: 3},
+		{name: "reply without comment", recipient: 1, actor: 2, kind: TypeReply, soundID: 3, wantErr: true},
+		{name: "follow", recipient: 1, actor: 2, kind: TypeFollow},
+		{name: "follow without actor", recipient: 1, kind: TypeFollow, wantErr: true},
+		{name: "moderation from the system", recipient: 1, kind: TypeModeration, soundID: 3, detail: "removed"},
+		{name: "moderation without outcome", recipient: 1, kind: TypeModeration, soundID: 3, wantErr: true},
+		{name: "unknown type", recipient: 1, actor: 2, kind: "poke", wantErr: true},
+		{name: "no recipient", actor: 2, kind: TypeFollow, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNotification(tt.recipient, tt.actor, tt.kind, tt.soundID, tt.commentID, tt.detail)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	tests := []struct {
+		kind string
+		want string
+	}{
+		{kind: TypeLike, want: "like:5"},
+		{kind: TypeComment, want: "comment:5"},
+		{kind: TypeReply, want: "reply:4"},
+		{kind: TypeFollow, want: "follow"},
+		{kind: TypeModeration, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			n := RebuildNotificationFromStorage(1, 2, 3, "alice", 1, tt.kind, 5, "Intro", 4, "removed", false, time.Now())
+			if got := n.GroupKey(); got != tt.want {
+				t.Fatalf("GroupKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package notification
+
+import (
+	"context"
+	"time"
+)
+
+type INotificationRepository interface {
+	INotificationRepositoryReader
+	INotificationRepositoryWriter
+}
+
+type INotificationRepositoryReader interface {
+	GetNotifications(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]*Notification, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	GetPreferences(ctx context.Context, userID int) (map[string]bool, error)
+	IsNotificationEnabled(ctx context.Context, userID int, notificationType string) (bool, error)
+}
+
+type INotificationRepositoryWriter interface {
+	CreateNotification(ctx context.Context, n *Notification) error
+	MarkRead(ctx context.Context, userID, id int) (bool, error)
+	MarkAllRead(ctx context.Context, userID int) error
+	SetPreferences(ctx context.Context, userID int, preferences map[string]bool) error
+}
+
+type IDigestRepository interface {
+	GetDigestSettings(ctx context.Context, userID int) (*DigestSettings, error)
+	SaveDigestSettings(ctx context.Context, settings *DigestSettings) error
+	// ClaimDueDigests returns up to limit users whose digest is due and who have unread
+	// notifications since their last digest, and postpones them by lease so that other
+	// instances skip them.
+	ClaimDueDigests(ctx context.Context, defaultFrequency string, limit int, lease time.Duration) ([]*DigestRecipient, error)
+	MarkDigestSent(ctx context.Context, userID int) error
+	// MarkDigestFailed counts a failed attempt and makes the digest due again after retryAfter.
+	MarkDigestFailed(ctx context.Context, userID int, retryAfter time.Duration) error
+}
+
+type IDigestEmailSender interface {
+	SupportsLocale(locale string) bool
+	SendDigestEmail(ctx context.Context, email, locale string, digest *Digest, unsubscribeURL string) error
+}
+
+// INotifier is how other services emit notifications.
+type INotifier interface {
+	Notify(ctx context.Context, n *Notification) error
+	// NotifySoundAuthor addresses a notification about a sound to its author.
+	NotifySoundAuthor(ctx context.Context, actorID int, notificationType string, soundID int) error
+}
@@ -0,0 +1,82 @@
+package playlist
+
+import (
+	"encoding/xml"
+	"fmt"
+	"soundtube/internal/domain/sound"
+	"strings"
+)
+
+type xspfPlaylist struct {
+	XMLName    xml.Name    `xml:"playlist"`
+	Version    string      `xml:"version,attr"`
+	Namespace  string      `xml:"xmlns,attr"`
+	Title      string      `xml:"title"`
+	Creator    string      `xml:"creator"`
+	Annotation string      `xml:"annotation,omitempty"`
+	Tracks     []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Title    string `xml:"title"`
+	Creator  string `xml:"creator"`
+	Album    string `xml:"album,omitempty"`
+	Duration int    `xml:"duration,omitempty"`
+}
+
+// ToM3U8 renders the tracks as an extended M3U playlist. Sounds without an uploaded
+// file are skipped. baseURL is prepended to the stored file path.
+func ToM3U8(p *Playlist, sounds []*sound.Sound, baseURL string) string {
+	var b strings.Builder
+
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#PLAYLIST:%s\n", m3uText(p.title))
+
+	for _, s := range sounds {
+		if s.FilePath() == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "#EXTINF:%d,%s - %s\n", s.Duration(), m3uText(s.AuthorName()), m3uText(s.Name()))
+		b.WriteString(m3uText(baseURL+"/static/"+s.FilePath()) + "\n")
+	}
+
+	return b.String()
+}
+
+// m3uText keeps a value on its line. M3U has no escaping, so a line break in a title
+// would start a new entry or directive.
+func m3uText(value string) string {
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+}
+
+// ToXSPF renders the tracks as an XSPF (XML Shareable Playlist Format) document.
+func ToXSPF(p *Playlist, sounds []*sound.Sound, baseURL string) ([]byte, error) {
+	doc := xspfPlaylist{
+		Version:    "1",
+		Namespace:  "http://xspf.org/ns/0/",
+		Title:      p.title,
+		Creator:    p.ownerName,
+		Annotation: p.description,
+	}
+
+	for _, s := range sounds {
+		if s.FilePath() == "" {
+			continue
+		}
+		doc.Tracks = append(doc.Tracks, xspfTrack{
+			Location: baseURL + "/static/" + s.FilePath(),
+			Title:    s.Name(),
+			Creator:  s.AuthorName(),
+			Album:    s.Ablum(),
+			Duration: s.Duration() * 1000,
+		})
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,74 @@
+package playlist
+
+import (
+	"soundtube/internal/domain/sound"
+	"strings"
+	"testing"
+)
+
+func TestToM3U8(t *testing.T) {
+	tests := []struct {
+		name   string
+		title  string
+		sounds []*sound.Sound
+		want   string
+	}{
+		{
+			name:  "plain",
+			title: "Road trip",
+			sounds: []*sound.Sound{
+				sound.RebuildSoundFromStorage(1, 2, 180, "Intro", "", "", "intro.mp3", "uploads/intro.mp3", 10, "mp3", "", "alice", 0, 0, 0),
+			},
+			want: "#EXTM3U\n#PLAYLIST:Road trip\n#EXTINF:180,alice - Intro\nhttps://soundtube.example/static/uploads/intro.mp3\n",
+		},
+		{
+			name:  "line breaks in titles",
+			title: "Road\r\n#EXTINF:1,evil\r\nhttp://evil.example/x.mp3",
+			sounds: []*sound.Sound{
+				sound.RebuildSoundFromStorage(1, 2, 180, "Intro\nhttp://evil.example/y.mp3", "", "", "intro.mp3", "uploads/intro.mp3", 10, "mp3", "", "ali\rce", 0, 0, 0),
+			},
+			want: "#EXTM3U\n#PLAYLIST:Road #EXTINF:1,evil http://evil.example/x.mp3\n" +
+				"#EXTINF:180,ali ce - Intro http://evil.example/y.mp3\nhttps://soundtube.example/static/uploads/intro.mp3\n",
+		},
+		{
+			name:  "sounds without files are skipped",
+			title: "Drafts",
+			sounds: []*sound.Sound{
+				sound.RebuildSoundFromStorage(1, 2, 180, "Draft", "", "", "", "", 0, "", "", "alice", 0, 0, 0),
+			},
+			want: "#EXTM3U\n#PLAYLIST:Drafts\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := RebuildPlaylistFromStorage(1, 2, "alice", tt.title, "", VisibilityPublic, nil, "")
+
+			got := ToM3U8(p, tt.sounds, "https://soundtube.example")
+			if got != tt.want {
+				t.Fatalf("ToM3U8() =\n%q\nwant\n%q", got, tt.want)
+			}
+			if strings.Contains(got, "\r") {
+				t.Fatal("output contains a carriage return")
+			}
+		})
+	}
+}
+
+func TestToXSPFEscapesMarkup(t *testing.T) {
+	p := RebuildPlaylistFromStorage(1, 2, "alice", "Rock & <Roll>", "", VisibilityPublic, nil, "")
+	sounds := []*sound.Sound{
+		sound.RebuildSoundFromStorage(1, 2, 3, "A & B", "", "", "a.mp3", "uploads/a.mp3", 10, "mp3", "", "alice", 0, 0, 0),
+	}
+
+	body, err := ToXSPF(p, sounds, "https://soundtube.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"<title>Rock &amp; &lt;Roll&gt;</title>", "<title>A &amp; B</title>", "<duration>3000</duration>"} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("XSPF is missing %s:\n%s", want, body)
+		}
+	}
+}
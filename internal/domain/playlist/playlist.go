@@ -0,0 +1,140 @@
+package playlist
+
+import (
+	"errors"
+	"slices"
+	"soundtube/scripts"
+)
+
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private"
+)
+
+type Playlist struct {
+	id            int
+	ownerID       int
+	ownerName     string
+	title         string
+	description   string
+	visibility    string
+	collaborators []int
+	createdAt     string
+}
+
+func (p *Playlist) ID() int              { return p.id }
+func (p *Playlist) OwnerID() int         { return p.ownerID }
+func (p *Playlist) OwnerName() string    { return p.ownerName }
+func (p *Playlist) Title() string        { return p.title }
+func (p *Playlist) Description() string  { return p.description }
+func (p *Playlist) Visibility() string   { return p.visibility }
+func (p *Playlist) Collaborators() []int { return p.collaborators }
+func (p *Playlist) CreatedAt() string    { return p.createdAt }
+
+func NewPlaylist(ownerID int, title, description, visibility string) (*Playlist, error) {
+	if ownerID <= 0 {
+		return nil, errors.New("invalid owner id")
+	}
+
+	p := &Playlist{ownerID: ownerID}
+	if err := p.Update(title, description, visibility); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func RebuildPlaylistFromStorage(id, ownerID int, ownerName, title, description, visibility string, collaborators []int, createdAt string) *Playlist {
+	return &Playlist{
+		id:            id,
+		ownerID:       ownerID,
+		ownerName:     ownerName,
+		title:         title,
+		description:   description,
+		visibility:    visibility,
+		collaborators: collaborators,
+		createdAt:     createdAt,
+	}
+}
+
+func (p *Playlist) Update(title, description, visibility string) error {
+	if title == "" {
+		return errors.New("playlist title cannot be empty")
+	}
+	if scripts.ValidateXSS(title) {
+		return errors.New("invalid playlist title")
+	}
+	if scripts.ValidateXSS(description) {
+		return errors.New("invalid playlist description")
+	}
+	if visibility == "" {
+		visibility = VisibilityPublic
+	}
+	if visibility != VisibilityPublic && visibility != VisibilityUnlisted && visibility != VisibilityPrivate {
+		return errors.New("visibility must be public, unlisted or private")
+	}
+
+	p.title = title
+	p.description = description
+	p.visibility = visibility
+	return nil
+}
+
+func (p *Playlist) IsCollaborator(userID int) bool {
+	return slices.Contains(p.collaborators, userID)
+}
+
+// CanView reports whether the user may open the playlist. Unlisted playlists are
+// reachable by anyone who knows the id but are never listed on profiles.
+func (p *Playlist) CanView(userID int) bool {
+	return p.visibility != VisibilityPrivate || p.CanEditTracks(userID)
+}
+
+func (p *Playlist) CanEditTracks(userID int) bool {
+	return p.ownerID == userID || p.IsCollaborator(userID)
+}
+
+func (p *Playlist) CanManage(userID int) bool {
+	return p.ownerID == userID
+}
+
+type Track struct {
+	id         int
+	playlistID int
+	soundID    int
+	rank       string
+	addedBy    int
+}
+
+func (t *Track) ID() int         { return t.id }
+func (t *Track) PlaylistID() int { return t.playlistID }
+func (t *Track) SoundID() int    { return t.soundID }
+func (t *Track) Rank() string    { return t.rank }
+func (t *Track) AddedBy() int    { return t.addedBy }
+
+func NewTrack(playlistID, soundID, addedBy int, rank string) (*Track, error) {
+	if playlistID <= 0 || soundID <= 0 {
+		return nil, errors.New("invalid track params")
+	}
+	if rank == "" {
+		return nil, errors.New("track rank cannot be empty")
+	}
+
+	return &Track{
+		playlistID: playlistID,
+		soundID:    soundID,
+		rank:       rank,
+		addedBy:    addedBy,
+	}, nil
+}
+
+func RebuildTrackFromStorage(id, playlistID, soundID int, rank string, addedBy int) *Track {
+	return &Track{
+		id:         id,
+		playlistID: playlistID,
+		soundID:    soundID,
+		rank:       rank,
+		addedBy:    addedBy,
+	}
+}
@@ -0,0 +1,61 @@
+package playlist
+
+import "soundtube/internal/domain/sound"
+
+type PlaylistDTO struct {
+	ID            int                 `json:"id"`
+	Owner         sound.AuthorSummary `json:"owner"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	Visibility    string              `json:"visibility"`
+	Collaborators []int               `json:"collaborators"`
+	CreatedAt     string              `json:"created_at"`
+	Tracks        []*TrackDTO         `json:"tracks,omitempty"`
+}
+
+type TrackDTO struct {
+	ID      int             `json:"id"`
+	Rank    string          `json:"rank"`
+	AddedBy int             `json:"added_by"`
+	Sound   *sound.SoundDTO `json:"sound"`
+}
+
+func (p *Playlist) ToDTO() *PlaylistDTO {
+	collaborators := p.collaborators
+	if collaborators == nil {
+		collaborators = []int{}
+	}
+
+	return &PlaylistDTO{
+		ID: p.id,
+		Owner: sound.AuthorSummary{
+			ID:       p.ownerID,
+			Username: p.ownerName,
+		},
+		Title:         p.title,
+		Description:   p.description,
+		Visibility:    p.visibility,
+		Collaborators: collaborators,
+		CreatedAt:     p.createdAt,
+	}
+}
+
+func (t *Track) ToDTO(s *sound.Sound) *TrackDTO {
+	dto := &TrackDTO{
+		ID:      t.id,
+		Rank:    t.rank,
+		AddedBy: t.addedBy,
+	}
+	if s != nil {
+		dto.Sound = s.ToDTO()
+	}
+	return dto
+}
+
+func PlaylistsToDTO(playlists []*Playlist) []*PlaylistDTO {
+	dtos := make([]*PlaylistDTO, len(playlists))
+	for i, p := range playlists {
+		dtos[i] = p.ToDTO()
+	}
+	return dtos
+}
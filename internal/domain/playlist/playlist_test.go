@@ -0,0 +1,67 @@
+package playlist
+
+import "testing"
+
+func TestPlaylistUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		title          string
+		description    string
+		visibility     string
+		wantErr        string
+		wantVisibility string
+	}{
+		{name: "defaults to public", title: "Mix", wantVisibility: VisibilityPublic},
+		{name: "private", title: "Mix", visibility: VisibilityPrivate, wantVisibility: VisibilityPrivate},
+		{name: "empty title", title: "", wantErr: "playlist title cannot be empty"},
+		{name: "markup in title", title: "<b>Mix</b>", wantErr: "invalid playlist title"},
+		{name: "markup in description", title: "Mix", description: "<i>x</i>", wantErr: "invalid playlist description"},
+		{name: "unknown visibility", title: "Mix", visibility: "friends", wantErr: "visibility must be public, unlisted or private"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPlaylist(1, tt.title, tt.description, tt.visibility)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Visibility() != tt.wantVisibility {
+				t.Fatalf("visibility = %q, want %q", p.Visibility(), tt.wantVisibility)
+			}
+		})
+	}
+}
+
+func TestPlaylistAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility string
+		userID     int
+		canView    bool
+		canEdit    bool
+	}{
+		{name: "owner of private", visibility: VisibilityPrivate, userID: 1, canView: true, canEdit: true},
+		{name: "collaborator of private", visibility: VisibilityPrivate, userID: 2, canView: true, canEdit: true},
+		{name: "stranger on private", visibility: VisibilityPrivate, userID: 3},
+		{name: "stranger on unlisted", visibility: VisibilityUnlisted, userID: 3, canView: true},
+		{name: "anonymous on public", visibility: VisibilityPublic, canView: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := RebuildPlaylistFromStorage(10, 1, "owner", "Mix", "", tt.visibility, []int{2}, "")
+			if got := p.CanView(tt.userID); got != tt.canView {
+				t.Fatalf("CanView = %v, want %v", got, tt.canView)
+			}
+			if got := p.CanEditTracks(tt.userID); got != tt.canEdit {
+				t.Fatalf("CanEditTracks = %v, want %v", got, tt.canEdit)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package playlist
+
+import "context"
+
+type IPlaylistRepository interface {
+	IPlaylistRepositoryReader
+	IPlaylistRepositoryWriter
+}
+
+type IPlaylistRepositoryReader interface {
+	GetPlaylistByID(ctx context.Context, id int) (*Playlist, error)
+	GetPlaylistsByUser(ctx context.Context, userID int) ([]*Playlist, error)
+	GetTracks(ctx context.Context, playlistID int) ([]*Track, error)
+	GetTrack(ctx context.Context, playlistID, trackID int) (*Track, error)
+}
+
+type IPlaylistRepositoryWriter interface {
+	CreatePlaylist(ctx context.Context, playlist *Playlist) (int, error)
+	UpdatePlaylist(ctx context.Context, id int, playlist *Playlist) error
+	DeletePlaylist(ctx context.Context, id int) error
+	AddCollaborator(ctx context.Context, playlistID, userID int) error
+	RemoveCollaborator(ctx context.Context, playlistID, userID int) error
+	// AppendTrack adds the sound after the last track, choosing its rank atomically.
+	AppendTrack(ctx context.Context, playlistID, soundID, addedBy int) (int, error)
+	RemoveTrack(ctx context.Context, playlistID, trackID int) error
+	// MoveTrack re-ranks the track after afterTrackID atomically, reporting false when either is missing.
+	MoveTrack(ctx context.Context, playlistID, trackID, afterTrackID int) (bool, error)
+}
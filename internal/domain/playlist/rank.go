@@ -0,0 +1,174 @@
+package playlist
+
+import (
+	"errors"
+	"strings"
+)
+
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// RankBetween returns a key that sorts strictly between prev and next. An empty prev
+// means the start of the list, an empty next means the end. Keys never end with the
+// zero digit, so there is always room for another key between two neighbours.
+func RankBetween(prev, next string) (string, error) {
+	if next != "" && prev >= next {
+		return "", errors.New("previous rank must sort before next rank")
+	}
+	if !validRank(prev) || !validRank(next) {
+		return "", errors.New("invalid rank")
+	}
+
+	switch {
+	case prev == "" && next == "":
+		return string(rankDigits[len(rankDigits)/2]), nil
+	case next == "":
+		return rankAfter(prev), nil
+	case prev == "":
+		return rankBefore(next), nil
+	}
+
+	base := len(rankDigits)
+	var rank []byte
+
+	for i := 0; ; i++ {
+		low := 0
+		if i < len(prev) {
+			low = strings.IndexByte(rankDigits, prev[i])
+		}
+
+		high := base
+		if next != "" {
+			high = 0
+			if i < len(next) {
+				high = strings.IndexByte(rankDigits, next[i])
+			}
+		}
+
+		if low == high {
+			rank = append(rank, rankDigits[low])
+			continue
+		}
+
+		if mid := (low + high) / 2; mid > low {
+			rank = append(rank, rankDigits[mid])
+			return string(rank), nil
+		}
+
+		rank = append(rank, rankDigits[low])
+		next = ""
+	}
+}
+
+// rankAfter steps one unit past the last digit of rank. Only a key made of top digits
+// cannot step, so it is padded to twice its length first; appends therefore grow keys
+// logarithmically rather than by a digit every few tracks.
+func rankAfter(rank string) string {
+	if next, ok := stepRank(rank, len(rank), 1); ok {
+		return next
+	}
+	next, _ := stepRank(rank, 2*len(rank)+1, 1)
+	return next
+}
+
+// rankBefore is the prepend counterpart of rankAfter.
+func rankBefore(rank string) string {
+	if prev, ok := stepRank(rank, len(rank), -1); ok {
+		return prev
+	}
+	prev, _ := stepRank(rank, 2*len(rank)+1, -1)
+	return prev
+}
+
+// stepRank treats rank as a number with the given count of digits and moves it by
+// delta units of its last digit, skipping values that would end with the zero digit.
+// It reports false when the result would overflow or reach zero.
+func stepRank(rank string, length, delta int) (string, bool) {
+	digits := make([]int, length)
+	for i := 0; i < len(rank); i++ {
+		digits[i] = strings.IndexByte(rankDigits, rank[i])
+	}
+
+	base := len(rankDigits)
+	for {
+		carry := delta
+		for i := length - 1; i >= 0 && carry != 0; i-- {
+			digits[i] += carry
+			carry = 0
+			if digits[i] >= base {
+				digits[i] -= base
+				carry = 1
+			} else if digits[i] < 0 {
+				digits[i] += base
+				carry = -1
+			}
+		}
+		if carry != 0 {
+			return "", false
+		}
+		if digits[length-1] != 0 {
+			break
+		}
+	}
+
+	out := make([]byte, length)
+	for i, digit := range digits {
+		out[i] = rankDigits[digit]
+	}
+	return string(out), true
+}
+
+func validRank(rank string) bool {
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MoveRank picks the rank that places trackID right after afterTrackID, or at the top
+// when afterTrackID is 0. tracks must be sorted by rank. It reports false when either
+// track is not in the list.
+func MoveRank(tracks []*Track, trackID, afterTrackID int) (string, bool, error) {
+	others := make([]*Track, 0, len(tracks))
+	found := false
+	for _, track := range tracks {
+		if track.ID() == trackID {
+			found = true
+			continue
+		}
+		others = append(others, track)
+	}
+
+	if !found {
+		return "", false, nil
+	}
+
+	position := 0
+	if afterTrackID != 0 {
+		position = -1
+		for i, track := range others {
+			if track.ID() == afterTrackID {
+				position = i + 1
+				break
+			}
+		}
+		if position < 0 {
+			return "", false, nil
+		}
+	}
+
+	var prev, next string
+	if position > 0 {
+		prev = others[position-1].Rank()
+	}
+	if position < len(others) {
+		next = others[position].Rank()
+	}
+
+	rank, err := RankBetween(prev, next)
+	if err != nil {
+		return "", false, err
+	}
+	return rank, true, nil
+}
@@ -0,0 +1,152 @@
+package playlist
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name    string
+		prev    string
+		next    string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty list", want: "i"},
+		{name: "append", prev: "i", want: "j"},
+		{name: "prepend", next: "i", want: "h"},
+		{name: "between", prev: "a", next: "c", want: "b"},
+		{name: "adjacent digits", prev: "a", next: "b", want: "ai"},
+		{name: "after a longer key", prev: "az", next: "b", want: "azi"},
+		{name: "append after an inserted key", prev: "ai", want: "aj"},
+		{name: "append carries into the previous digit", prev: "az", want: "b1"},
+		{name: "append after the last digit", prev: "z", want: "z01"},
+		{name: "prepend borrows from the previous digit", next: "b1", want: "az"},
+		{name: "before the smallest key", next: "1", want: "0zz"},
+		{name: "out of order", prev: "c", next: "a", wantErr: true},
+		{name: "equal", prev: "c", next: "c", wantErr: true},
+		{name: "invalid digit", prev: "A", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RankBetween(tt.prev, tt.next)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Fatalf("RankBetween(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+			}
+			if got <= tt.prev || (tt.next != "" && got >= tt.next) {
+				t.Fatalf("RankBetween(%q, %q) = %q does not sort between them", tt.prev, tt.next, got)
+			}
+		})
+	}
+}
+
+func TestRankBetweenRepeatedInserts(t *testing.T) {
+	tests := []struct {
+		name   string
+		insert func(ranks []string) (string, string)
+	}{
+		{name: "append", insert: func(ranks []string) (string, string) { return ranks[len(ranks)-1], "" }},
+		{name: "prepend", insert: func(ranks []string) (string, string) { return "", ranks[0] }},
+		{name: "after first", insert: func(ranks []string) (string, string) { return ranks[0], ranks[1] }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranks := []string{"i", "r"}
+			for i := 0; i < 200; i++ {
+				prev, next := tt.insert(ranks)
+				rank, err := RankBetween(prev, next)
+				if err != nil {
+					t.Fatalf("insert %d between %q and %q: %v", i, prev, next, err)
+				}
+				if rank[len(rank)-1] == '0' {
+					t.Fatalf("rank %q ends with the zero digit", rank)
+				}
+				ranks = append(ranks, rank)
+				sort.Strings(ranks)
+			}
+
+			for i := 1; i < len(ranks); i++ {
+				if ranks[i-1] == ranks[i] {
+					t.Fatalf("duplicate rank %q", ranks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRankBetweenKeepsEndKeysShort(t *testing.T) {
+	const inserts = 5000
+	const maxLength = 8
+
+	tests := []struct {
+		name   string
+		insert func(last string) (string, string)
+		before bool
+	}{
+		{name: "append", insert: func(last string) (string, string) { return last, "" }},
+		{name: "prepend", insert: func(last string) (string, string) { return "", last }, before: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last := "i"
+			for i := 0; i < inserts; i++ {
+				rank, err := RankBetween(tt.insert(last))
+				if err != nil {
+					t.Fatalf("insert %d next to %q: %v", i, last, err)
+				}
+				if (rank > last) == tt.before {
+					t.Fatalf("insert %d: %q is on the wrong side of %q", i, rank, last)
+				}
+				if len(rank) > maxLength {
+					t.Fatalf("insert %d: rank %q is longer than %d digits", i, rank, maxLength)
+				}
+				last = rank
+			}
+		})
+	}
+}
+
+func TestMoveRank(t *testing.T) {
+	tracks := []*Track{
+		RebuildTrackFromStorage(1, 1, 10, "a", 1),
+		RebuildTrackFromStorage(2, 1, 11, "b", 1),
+		RebuildTrackFromStorage(3, 1, 12, "c", 1),
+	}
+
+	tests := []struct {
+		name         string
+		trackID      int
+		afterTrackID int
+		want         string
+		wantFound    bool
+	}{
+		{name: "to the top", trackID: 3, want: "9", wantFound: true},
+		{name: "between neighbours", trackID: 1, afterTrackID: 2, want: "bi", wantFound: true},
+		{name: "to the end", trackID: 1, afterTrackID: 3, want: "d", wantFound: true},
+		{name: "unknown track", trackID: 9},
+		{name: "unknown anchor", trackID: 1, afterTrackID: 9},
+		{name: "after itself", trackID: 1, afterTrackID: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := MoveRank(tracks, tt.trackID, tt.afterTrackID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.wantFound || got != tt.want {
+				t.Fatalf("MoveRank(%d, %d) = %q, %v, want %q, %v", tt.trackID, tt.afterTrackID, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Policy allows Limit requests per Window. PerUser policies are keyed by the
+// authenticated user when there is one and by client IP otherwise.
+type Policy struct {
+	name    string
+	limit   int
+	window  time.Duration
+	perUser bool
+}
+
+func (p Policy) Name() string          { return p.name }
+func (p Policy) Limit() int            { return p.limit }
+func (p Policy) Window() time.Duration { return p.window }
+func (p Policy) PerUser() bool         { return p.perUser }
+
+func NewPolicy(name string, limit int, window time.Duration, perUser bool) (Policy, error) {
+	if name == "" {
+		return Policy{}, errors.New("policy name is required")
+	}
+	if limit <= 0 {
+		return Policy{}, fmt.Errorf("policy %s: limit must be positive", name)
+	}
+	if window <= 0 {
+		return Policy{}, fmt.Errorf("policy %s: window must be positive", name)
+	}
+
+	return Policy{name: name, limit: limit, window: window, perUser: perUser}, nil
+}
+
+// EmissionInterval is the time one request "costs"; a client that has been idle for a
+// full window can burst up to Limit requests.
+func (p Policy) EmissionInterval() time.Duration {
+	return p.window / time.Duration(p.limit)
+}
+
+// Header renders the policy for the RateLimit-Policy response header, e.g. "100;w=60".
+func (p Policy) Header() string {
+	return fmt.Sprintf("%d;w=%d", p.limit, int(p.window.Seconds()))
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
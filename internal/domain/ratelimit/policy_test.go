@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPolicy(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		limit    int
+		window   time.Duration
+		emission time.Duration
+		header   string
+	}{
+		{"default", 100, time.Minute, 600 * time.Millisecond, "100;w=60"},
+		{"login", 5, time.Hour, 12 * time.Minute, "5;w=3600"},
+		{"export", 1, time.Second, time.Second, "1;w=1"},
+	} {
+		p, err := NewPolicy(c.name, c.limit, c.window, false)
+		if err != nil {
+			t.Errorf("NewPolicy(%q, %d, %s): %v", c.name, c.limit, c.window, err)
+			continue
+		}
+		if got := p.EmissionInterval(); got != c.emission {
+			t.Errorf("%s: EmissionInterval() = %s, want %s", c.name, got, c.emission)
+		}
+		if got := p.Header(); got != c.header {
+			t.Errorf("%s: Header() = %q, want %q", c.name, got, c.header)
+		}
+	}
+}
+
+func TestNewPolicyRejectsInvalid(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		limit  int
+		window time.Duration
+		want   string
+	}{
+		{"", 1, time.Second, "policy name is required"},
+		{"auth", 0, time.Second, "policy auth: limit must be positive"},
+		{"auth", 1, -time.Second, "policy auth: window must be positive"},
+	} {
+		if _, err := NewPolicy(c.name, c.limit, c.window, false); err == nil || err.Error() != c.want {
+			t.Errorf("NewPolicy(%q, %d, %s) err = %v, want %q", c.name, c.limit, c.window, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package ratelimit
+
+import "context"
+
+type ILimiter interface {
+	Allow(ctx context.Context, policy Policy, key string) (*Result, error)
+}
@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EventReactions = "reactions.updated"
+	EventComment   = "comment.created"
+	EventStatus    = "sound.status"
+)
+
+const StatusUploaded = "uploaded"
+
+var ErrInvalidEventID = errors.New("invalid event id")
+
+// Event is a change on a sound pushed to live subscribers. ID is assigned when the
+// event is stored in the sound's history and is what clients resume from.
+type Event struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	SoundID int             `json:"sound_id"`
+	Data    json.RawMessage `json:"data"`
+	At      time.Time       `json:"at"`
+}
+
+func NewEvent(eventType string, soundID int, data any) (*Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{Type: eventType, SoundID: soundID, Data: raw, At: time.Now()}, nil
+}
+
+type ReactionCounts struct {
+	Likes    int `json:"likes"`
+	Dislikes int `json:"dislikes"`
+}
+
+type StatusChange struct {
+	Status     string `json:"status"`
+	FileFormat string `json:"file_format,omitempty"`
+}
+
+// EventID is a history position in the "<milliseconds>-<sequence>" form.
+type EventID struct {
+	Millis   uint64
+	Sequence uint64
+}
+
+func ParseEventID(id string) (EventID, error) {
+	millis, sequence, found := strings.Cut(id, "-")
+	if !found {
+		return EventID{}, ErrInvalidEventID
+	}
+
+	ms, err := strconv.ParseUint(millis, 10, 64)
+	if err != nil {
+		return EventID{}, ErrInvalidEventID
+	}
+	seq, err := strconv.ParseUint(sequence, 10, 64)
+	if err != nil {
+		return EventID{}, ErrInvalidEventID
+	}
+
+	return EventID{Millis: ms, Sequence: seq}, nil
+}
+
+func (id EventID) After(other EventID) bool {
+	if id.Millis != other.Millis {
+		return id.Millis > other.Millis
+	}
+	return id.Sequence > other.Sequence
+}
@@ -0,0 +1,74 @@
+package realtime
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseEventID(t *testing.T) {
+	tests := []struct {
+		id      string
+		want    EventID
+		wantErr error
+	}{
+		{id: "1700000000000-0", want: EventID{Millis: 1700000000000}},
+		{id: "1700000000000-42", want: EventID{Millis: 1700000000000, Sequence: 42}},
+		{id: "0-0", want: EventID{}},
+		{id: "", wantErr: ErrInvalidEventID},
+		{id: "1700000000000", wantErr: ErrInvalidEventID},
+		{id: "-1", wantErr: ErrInvalidEventID},
+		{id: "1-", wantErr: ErrInvalidEventID},
+		{id: "abc-1", wantErr: ErrInvalidEventID},
+		{id: "1-2-3", wantErr: ErrInvalidEventID},
+		{id: "-5-1", wantErr: ErrInvalidEventID},
+		{id: "$", wantErr: ErrInvalidEventID},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEventID(tt.id)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseEventID(%q) err = %v, want %v", tt.id, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEventID(%q) = %+v, want %+v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestEventIDAfter(t *testing.T) {
+	tests := []struct {
+		id    EventID
+		other EventID
+		want  bool
+	}{
+		{id: EventID{Millis: 2}, other: EventID{Millis: 1, Sequence: 9}, want: true},
+		{id: EventID{Millis: 1, Sequence: 9}, other: EventID{Millis: 2}, want: false},
+		{id: EventID{Millis: 1, Sequence: 2}, other: EventID{Millis: 1, Sequence: 1}, want: true},
+		{id: EventID{Millis: 1, Sequence: 1}, other: EventID{Millis: 1, Sequence: 1}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.id.After(tt.other); got != tt.want {
+			t.Errorf("%+v.After(%+v) = %v, want %v", tt.id, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	event, err := NewEvent(EventReactions, 3, ReactionCounts{Likes: 2, Dislikes: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.ID != "" || event.Type != EventReactions || event.SoundID != 3 || event.At.IsZero() {
+		t.Fatalf("event = %+v", event)
+	}
+	if string(event.Data) != `{"likes":2,"dislikes":1}` {
+		t.Fatalf("data = %s", event.Data)
+	}
+
+	if _, err := NewEvent(EventStatus, 3, func() {}); err == nil {
+		t.Fatal("unencodable data accepted")
+	}
+}
@@ -0,0 +1,20 @@
+package realtime
+
+import "context"
+
+type IEventPublisher interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+// IEventHistory keeps a short per-sound backlog so that dropped connections can resume.
+type IEventHistory interface {
+	Since(ctx context.Context, soundID int, lastID string) ([]*Event, error)
+}
+
+// IEventBus delivers events published by any server instance to every instance.
+type IEventBus interface {
+	IEventPublisher
+	IEventHistory
+	Listen(handle func(*Event)) error
+	Close() error
+}
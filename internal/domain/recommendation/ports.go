@@ -0,0 +1,28 @@
+package recommendation
+
+import (
+	"context"
+	"time"
+)
+
+type IRecommendationRepository interface {
+	IRecommendationRepositoryReader
+	IRecommendationRepositoryWriter
+}
+
+type IRecommendationRepositoryReader interface {
+	GetSimilarIDs(ctx context.Context, soundID, limit int) ([]int, error)
+	GetRecommendedIDs(ctx context.Context, userID, limit int) ([]int, error)
+	GetPopularIDs(ctx context.Context, query *PopularQuery) ([]int, error)
+	GetUserGenres(ctx context.Context, userID int) ([]string, error)
+}
+
+type IRecommendationRepositoryWriter interface {
+	RebuildSimilarities(ctx context.Context, topK int) (int, error)
+}
+
+// IRebuildLock lets a single instance rebuild the similarity table per refresh interval.
+type IRebuildLock interface {
+	Acquire(ctx context.Context, ttl time.Duration) (token string, ok bool, err error)
+	Release(ctx context.Context, token string) error
+}
@@ -0,0 +1,22 @@
+package recommendation
+
+// Interaction weights used for both similarity and personal scoring. Plays are an
+// implicit signal, so they count for less than an explicit like.
+const (
+	LikeWeight    = 1.0
+	DislikeWeight = -1.0
+	PlayWeight    = 0.5
+)
+
+// Shrinkage damps similarities backed by only a few shared listeners:
+// score = cosine * support / (support + Shrinkage).
+const Shrinkage = 5
+
+// PopularQuery selects the cold-start fallback: the most liked and played sounds,
+// optionally limited to genres and skipping what the user already knows.
+type PopularQuery struct {
+	Genres        []string
+	ExcludeIDs    []int
+	ExcludeUserID int
+	Limit         int
+}
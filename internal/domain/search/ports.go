@@ -0,0 +1,9 @@
+package search
+
+import "context"
+
+type ISearchRepository interface {
+	Search(ctx context.Context, query *Query) ([]*Hit, error)
+	CountByType(ctx context.Context, query *Query) (map[string]int, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]*Suggestion, error)
+}
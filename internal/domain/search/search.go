@@ -0,0 +1,101 @@
+package search
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	TypeSound  = "sound"
+	TypeArtist = "artist"
+	TypeAlbum  = "album"
+)
+
+// Highlight markers are plain control characters so the matched text can be escaped
+// before the <mark> tags are put in place.
+const (
+	HighlightStart = "\x01"
+	HighlightStop  = "\x02"
+)
+
+const maxQueryLength = 200
+
+var AllTypes = []string{TypeSound, TypeArtist, TypeAlbum}
+
+type Query struct {
+	text   string
+	types  []string
+	limit  int
+	offset int
+}
+
+func (q *Query) Text() string    { return q.text }
+func (q *Query) Types() []string { return q.types }
+func (q *Query) Limit() int      { return q.limit }
+func (q *Query) Offset() int     { return q.offset }
+
+func NewQuery(text string, types []string, limit, offset int) (*Query, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("search query cannot be empty")
+	}
+	if len(text) > maxQueryLength {
+		return nil, errors.New("search query is too long")
+	}
+	if limit <= 0 || offset < 0 {
+		return nil, errors.New("invalid pagination")
+	}
+
+	if len(types) == 0 {
+		types = AllTypes
+	}
+	for _, t := range types {
+		if t != TypeSound && t != TypeArtist && t != TypeAlbum {
+			return nil, errors.New("unknown result type: " + t)
+		}
+	}
+
+	return &Query{text: text, types: types, limit: limit, offset: offset}, nil
+}
+
+type Hit struct {
+	Type      string
+	ID        int
+	Title     string
+	Subtitle  string
+	Highlight string
+	Rank      float64
+}
+
+type Suggestion struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+// PrefixQuery turns free text into a to_tsquery expression where every word has to
+// match a title (weight A) lexeme and the last one may be incomplete. Anything but
+// letters and digits is dropped so the result is always a valid tsquery.
+func PrefixQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	for i := range words {
+		words[i] += ":A"
+	}
+	words[len(words)-1] = strings.TrimSuffix(words[len(words)-1], ":A") + ":*A"
+	return strings.Join(words, " & ")
+}
+
+// RenderHighlight escapes the headline produced by the database and wraps matches in <mark>.
+func RenderHighlight(headline string) string {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, HighlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, HighlightStop, "</mark>")
+}
@@ -0,0 +1,42 @@
+package search
+
+type HitDTO struct {
+	Type      string  `json:"type"`
+	ID        int     `json:"id"`
+	Title     string  `json:"title"`
+	Subtitle  string  `json:"subtitle,omitempty"`
+	Highlight string  `json:"highlight"`
+	Score     float64 `json:"score"`
+}
+
+type ResultsDTO struct {
+	Query  string         `json:"query"`
+	Hits   []*HitDTO      `json:"hits"`
+	Facets map[string]int `json:"facets"`
+}
+
+func (h *Hit) ToDTO() *HitDTO {
+	return &HitDTO{
+		Type:      h.Type,
+		ID:        h.ID,
+		Title:     h.Title,
+		Subtitle:  h.Subtitle,
+		Highlight: RenderHighlight(h.Highlight),
+		Score:     h.Rank,
+	}
+}
+
+func NewResultsDTO(query string, hits []*Hit, facets map[string]int) *ResultsDTO {
+	dtos := make([]*HitDTO, len(hits))
+	for i, h := range hits {
+		dtos[i] = h.ToDTO()
+	}
+
+	for _, t := range AllTypes {
+		if _, ok := facets[t]; !ok {
+			facets[t] = 0
+		}
+	}
+
+	return &ResultsDTO{Query: query, Hits: dtos, Facets: facets}
+}
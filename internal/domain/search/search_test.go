@@ -0,0 +1,85 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		types     []string
+		limit     int
+		offset    int
+		wantErr   string
+		wantText  string
+		wantTypes []string
+	}{
+		{name: "defaults to every type", text: "  rain  ", limit: 10, wantText: "rain", wantTypes: AllTypes},
+		{name: "selected types", text: "rain", types: []string{TypeAlbum}, limit: 10, wantText: "rain", wantTypes: []string{TypeAlbum}},
+		{name: "empty", text: "   ", limit: 10, wantErr: "search query cannot be empty"},
+		{name: "too long", text: strings.Repeat("a", maxQueryLength+1), limit: 10, wantErr: "search query is too long"},
+		{name: "zero limit", text: "rain", limit: 0, wantErr: "invalid pagination"},
+		{name: "negative offset", text: "rain", limit: 10, offset: -1, wantErr: "invalid pagination"},
+		{name: "unknown type", text: "rain", types: []string{"playlist"}, limit: 10, wantErr: "unknown result type: playlist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewQuery(tt.text, tt.types, tt.limit, tt.offset)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if q.Text() != tt.wantText || !slices.Equal(q.Types(), tt.wantTypes) {
+				t.Fatalf("query = %q %v, want %q %v", q.Text(), q.Types(), tt.wantText, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestPrefixQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "", want: ""},
+		{text: "!!! ???", want: ""},
+		{text: "ra", want: "ra:*A"},
+		{text: "Rainy Day", want: "rainy:A & day:*A"},
+		{text: "rock'n'roll", want: "rock:A & n:A & roll:*A"},
+		{text: "a & b | !c", want: "a:A & b:A & c:*A"},
+		{text: "Sigur Rós 2", want: "sigur:A & rós:A & 2:*A"},
+	}
+
+	for _, tt := range tests {
+		if got := PrefixQuery(tt.text); got != tt.want {
+			t.Errorf("PrefixQuery(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRenderHighlight(t *testing.T) {
+	tests := []struct {
+		headline string
+		want     string
+	}{
+		{headline: "plain", want: "plain"},
+		{headline: HighlightStart + "rain" + HighlightStop + " day", want: "<mark>rain</mark> day"},
+		{headline: "<script>" + HighlightStart + "x" + HighlightStop, want: "&lt;script&gt;<mark>x</mark>"},
+		{headline: `"Tom & Jerry"`, want: "&#34;Tom &amp; Jerry&#34;"},
+	}
+
+	for _, tt := range tests {
+		if got := RenderHighlight(tt.headline); got != tt.want {
+			t.Errorf("RenderHighlight(%q) = %q, want %q", tt.headline, got, tt.want)
+		}
+	}
+}
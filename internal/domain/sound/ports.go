@@ -8,13 +8,19 @@ type ISoundRepository interface {
 }
 
 type ISoundRepositoryReader interface {
-	GetSounds(ctx context.Context) ([]*Sound, error)
+	FindSounds(ctx context.Context, query *Query) (*Page, error)
 	GetSoundByID(ctx context.Context, id int) (*Sound, error)
+	GetSoundsByIDs(ctx context.Context, ids []int) ([]*Sound, error)
+	GetSoundsByAlbum(ctx context.Context, albumID int) ([]*Sound, error)
+	GetNextTrackNumber(ctx context.Context, albumID int) (int, error)
 	GetSoundByName(ctx context.Context, name string) (*Sound, error)
+	GetSoundsByAuthor(ctx context.Context, authorID int) ([]*Sound, error)
+	GetSoundsPageByAuthor(ctx context.Context, authorID, limit, offset int) ([]*Sound, error)
 }
 
 type ISoundRepositoryWriter interface {
-	CreateSound(ctx context.Context, sound *Sound) error
+	CreateSound(ctx context.Context, sound *Sound) (int, error)
 	DeleteSound(ctx context.Context, sound *Sound) error
 	UpdateSoundFile(ctx context.Context, name, filename, filepath string, fileSize int64) error
+	SetSoundAlbum(ctx context.Context, soundID, albumID, trackNumber int) error
 }
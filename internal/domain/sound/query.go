@@ -0,0 +1,93 @@
+package sound
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	SortNewest     = "newest"
+	SortMostLiked  = "most_liked"
+	SortMostPlayed = "most_played"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the last sound of a page. Key holds the value of the sort column
+// in its database text form, so it round-trips without losing precision.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   int    `json:"i"`
+}
+
+func (c *Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(raw, &c); err != nil || c.ID <= 0 || c.Key == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// Query describes a page of the sound listing. Zero values mean "no filter".
+type Query struct {
+	Genre        string
+	AuthorID     int
+	UploadedFrom time.Time
+	UploadedTo   time.Time
+	MinDuration  int
+	MaxDuration  int
+	Format       string
+	Sort         string
+	Limit        int
+	After        *Cursor
+}
+
+func (q *Query) Validate() error {
+	switch q.Sort {
+	case "":
+		q.Sort = SortNewest
+	case SortNewest, SortMostLiked, SortMostPlayed:
+	default:
+		return errors.New("unknown sort: " + q.Sort)
+	}
+
+	if q.Limit <= 0 {
+		return errors.New("limit must be positive")
+	}
+	if q.AuthorID < 0 {
+		return errors.New("invalid author id")
+	}
+	if q.MinDuration < 0 || q.MaxDuration < 0 || (q.MaxDuration > 0 && q.MinDuration > q.MaxDuration) {
+		return errors.New("invalid duration range")
+	}
+	if !q.UploadedFrom.IsZero() && !q.UploadedTo.IsZero() && q.UploadedFrom.After(q.UploadedTo) {
+		return errors.New("invalid upload date range")
+	}
+	if q.After != nil && q.After.Sort != q.Sort {
+		return ErrInvalidCursor
+	}
+
+	q.Format = strings.ToLower(strings.TrimPrefix(q.Format, "."))
+	return nil
+}
+
+// Page is a slice of the listing plus the cursor of the following page, if any.
+type Page struct {
+	Sounds []*Sound
+	Next   *Cursor
+}
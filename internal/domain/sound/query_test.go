@@ -0,0 +1,94 @@
+package sound
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []Cursor{
+		{Sort: SortNewest, Key: "2025-06-01 12:00:00.123456+00", ID: 42},
+		{Sort: SortMostLiked, Key: "1000", ID: 1},
+		{Sort: SortMostPlayed, Key: "9223372036854775807", ID: 7},
+	}
+
+	for _, cursor := range tests {
+		decoded, err := DecodeCursor(cursor.Encode())
+		if err != nil {
+			t.Fatalf("DecodeCursor(%+v): %v", cursor, err)
+		}
+		if *decoded != cursor {
+			t.Fatalf("round trip = %+v, want %+v", *decoded, cursor)
+		}
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "empty", value: ""},
+		{name: "not base64", value: "!!!"},
+		{name: "not json", value: encode("cursor")},
+		{name: "missing id", value: encode(`{"s":"newest","k":"2025"}`)},
+		{name: "negative id", value: encode(`{"s":"newest","k":"2025","i":-1}`)},
+		{name: "missing key", value: encode(`{"s":"newest","i":3}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.value); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("err = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestQueryValidate(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      Query
+		wantErr    string
+		wantSort   string
+		wantFormat string
+	}{
+		{name: "defaults to newest", query: Query{Limit: 20}, wantSort: SortNewest},
+		{name: "format normalized", query: Query{Limit: 20, Sort: SortMostPlayed, Format: ".MP3"}, wantSort: SortMostPlayed, wantFormat: "mp3"},
+		{name: "open duration range", query: Query{Limit: 20, MinDuration: 60}, wantSort: SortNewest},
+		{name: "same day range", query: Query{Limit: 20, UploadedFrom: day, UploadedTo: day}, wantSort: SortNewest},
+		{name: "matching cursor", query: Query{Limit: 20, Sort: SortMostLiked, After: &Cursor{Sort: SortMostLiked, Key: "3", ID: 1}}, wantSort: SortMostLiked},
+		{name: "unknown sort", query: Query{Limit: 20, Sort: "oldest"}, wantErr: "unknown sort: oldest"},
+		{name: "zero limit", query: Query{}, wantErr: "limit must be positive"},
+		{name: "negative author", query: Query{Limit: 20, AuthorID: -1}, wantErr: "invalid author id"},
+		{name: "inverted duration", query: Query{Limit: 20, MinDuration: 300, MaxDuration: 60}, wantErr: "invalid duration range"},
+		{name: "negative duration", query: Query{Limit: 20, MaxDuration: -1}, wantErr: "invalid duration range"},
+		{name: "inverted dates", query: Query{Limit: 20, UploadedFrom: day, UploadedTo: day.Add(-time.Hour)}, wantErr: "invalid upload date range"},
+		{name: "cursor from another sort", query: Query{Limit: 20, After: &Cursor{Sort: SortMostLiked, Key: "3", ID: 1}}, wantErr: ErrInvalidCursor.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := tt.query
+			err := q.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if q.Sort != tt.wantSort || q.Format != tt.wantFormat {
+				t.Fatalf("sort = %q, format = %q, want %q and %q", q.Sort, q.Format, tt.wantSort, tt.wantFormat)
+			}
+		})
+	}
+}
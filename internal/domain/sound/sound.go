@@ -3,12 +3,17 @@ package sound
 import "errors"
 
 type Sound struct {
-	id       int
-	authorID int
-	name     string
-	album    string
-	genre    string
-	duration int
+	id         int
+	authorID   int
+	authorName string
+	name       string
+	album      string
+	genre      string
+	duration   int
+
+	albumID     int
+	trackNumber int
+	playCount   int64
 
 	fileName   string
 	filePath   string
@@ -22,50 +27,64 @@ type Sound struct {
 func (s *Sound) ID() int       { return s.id }
 func (s *Sound) AuthorID() int { return s.authorID }
 
+func (s *Sound) AuthorName() string { return s.authorName }
+
 func (s *Sound) Name() string  { return s.name }
 func (s *Sound) Ablum() string { return s.album }
 func (s *Sound) Genre() string { return s.genre }
 func (s *Sound) Duration() int { return s.duration }
 
+func (s *Sound) AlbumID() int     { return s.albumID }
+func (s *Sound) TrackNumber() int { return s.trackNumber }
+func (s *Sound) PlayCount() int64 { return s.playCount }
+
 func (s *Sound) FileName() string   { return s.fileName }
 func (s *Sound) FilePath() string   { return s.filePath }
 func (s *Sound) FileSize() int      { return s.fileSize }
 func (s *Sound) FileFormat() string { return s.fileFormat }
 
-func NewSound(name, album, genre string, authorID int) (*Sound, error) {
+func NewSound(name, genre string, authorID, albumID, trackNumber int) (*Sound, error) {
 	if name == "" {
 		return nil, errors.New("sound name cannot be empty")
 	}
-	if album == "" {
-		return nil, errors.New("album name cannot be empty")
-	}
 	if genre == "" {
 		return nil, errors.New("genre name cannot be empty")
 	}
 	if authorID < 0 {
 		return nil, errors.New("invalid id")
 	}
+	if albumID < 0 {
+		return nil, errors.New("invalid album id")
+	}
+	if trackNumber < 0 || (albumID == 0 && trackNumber != 0) {
+		return nil, errors.New("invalid track number")
+	}
 
 	return &Sound{
-		authorID: authorID,
-		name:     name,
-		album:    album,
-		genre:    genre,
+		authorID:    authorID,
+		name:        name,
+		genre:       genre,
+		albumID:     albumID,
+		trackNumber: trackNumber,
 	}, nil
 }
 
-func RebuildSoundFromStorage(id, authorID, duration int, name, album, genre, fileName, filePath string, fileSize int, fileFormat, uploadDate string) *Sound {
+func RebuildSoundFromStorage(id, authorID, duration int, name, album, genre, fileName, filePath string, fileSize int, fileFormat, uploadDate, authorName string, albumID, trackNumber int, playCount int64) *Sound {
 	return &Sound{
-		id:         id,
-		authorID:   authorID,
-		name:       name,
-		album:      album,
-		genre:      genre,
-		duration:   duration,
-		fileName:   fileName,
-		filePath:   filePath,
-		fileSize:   fileSize,
-		fileFormat: fileFormat,
-		uploadDate: uploadDate,
+		albumID:     albumID,
+		trackNumber: trackNumber,
+		playCount:   playCount,
+		id:          id,
+		authorID:    authorID,
+		authorName:  authorName,
+		name:        name,
+		album:       album,
+		genre:       genre,
+		duration:    duration,
+		fileName:    fileName,
+		filePath:    filePath,
+		fileSize:    fileSize,
+		fileFormat:  fileFormat,
+		uploadDate:  uploadDate,
 	}
 }
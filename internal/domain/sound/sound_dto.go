@@ -1,34 +1,48 @@
 package sound
 
+type AuthorSummary struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
 type SoundDTO struct {
-	ID         int    `json:"id"`
-	AuthorID   int    `json:"author_id"`
-	Name       string `json:"name"`
-	Album      string `json:"album"`
-	Genre      string `json:"genre"`
-	Duration   int    `json:"duration"`
-	FileName   string `json:"file_name"`
-	FilePath   string `json:"file_path"`
-	FileSize   int    `json:"file_size"`
-	FileFormat string `json:"file_format"`
-	Status     string `json:"status"`
-	UploadDate string `json:"upload_date"`
+	ID          int           `json:"id"`
+	Author      AuthorSummary `json:"author"`
+	Name        string        `json:"name"`
+	Album       string        `json:"album"`
+	AlbumID     int           `json:"album_id,omitempty"`
+	TrackNumber int           `json:"track_number,omitempty"`
+	Genre       string        `json:"genre"`
+	Duration    int           `json:"duration"`
+	PlayCount   int64         `json:"play_count"`
+	FileName    string        `json:"file_name"`
+	FilePath    string        `json:"file_path"`
+	FileSize    int           `json:"file_size"`
+	FileFormat  string        `json:"file_format"`
+	Status      string        `json:"status"`
+	UploadDate  string        `json:"upload_date"`
 }
 
 func (s *Sound) ToDTO() *SoundDTO {
 	return &SoundDTO{
-		ID:         s.id,
-		AuthorID:   s.authorID,
-		Name:       s.name,
-		Album:      s.album,
-		Genre:      s.genre,
-		Duration:   s.duration,
-		FileName:   s.fileName,
-		FilePath:   s.filePath,
-		FileSize:   s.fileSize,
-		FileFormat: s.fileFormat,
-		Status:     s.status,
-		UploadDate: s.uploadDate,
+		ID: s.id,
+		Author: AuthorSummary{
+			ID:       s.authorID,
+			Username: s.authorName,
+		},
+		Name:        s.name,
+		Album:       s.album,
+		AlbumID:     s.albumID,
+		TrackNumber: s.trackNumber,
+		Genre:       s.genre,
+		Duration:    s.duration,
+		PlayCount:   s.playCount,
+		FileName:    s.fileName,
+		FilePath:    s.filePath,
+		FileSize:    s.fileSize,
+		FileFormat:  s.fileFormat,
+		Status:      s.status,
+		UploadDate:  s.uploadDate,
 	}
 }
 
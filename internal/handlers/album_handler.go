@@ -0,0 +1,428 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"soundtube/internal/domain/album"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCoverSize caps album cover uploads; larger images are rejected before
+// they reach the disk.
+const maxCoverSize = 5 << 20
+
+// coverExtensions maps sniffed cover content types to the extension the file
+// is stored under, so the client-supplied file name never decides the format.
+var coverExtensions = map[string]string{"image/jpeg": ".jpg", "image/png": ".png", "image/webp": ".webp"}
+
+var (
+	errCoverTooLarge    = errors.New("cover image is too large")
+	errCoverUnsupported = errors.New("unsupported cover format")
+)
+
+// coverExtension validates the uploaded cover by size and by sniffing its
+// leading bytes, returning the extension to store it under.
+func coverExtension(file *multipart.FileHeader) (string, error) {
+	if file.Size > maxCoverSize {
+		return "", errCoverTooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	ext, ok := coverExtensions[http.DetectContentType(head[:n])]
+	if !ok {
+		return "", errCoverUnsupported
+	}
+	return ext, nil
+}
+
+type AlbumHandler struct {
+	service   *services.AlbumService
+	staticDir string
+	logger    *pkg.CustomLogger
+}
+
+func NewAlbumHandler(service *services.AlbumService, staticDir string, logger *pkg.CustomLogger) *AlbumHandler {
+	return &AlbumHandler{service: service, staticDir: staticDir, logger: logger}
+}
+
+// CreateAlbum creates a new album
+// @Summary Create album
+// @Description Create an album owned by the current user
+// @Tags albums
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body AlbumRequest true "Album data"
+// @Success 201 {object} map[string]int "Created album id"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/albums [post]
+func (h *AlbumHandler) CreateAlbum(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "AlbumHandler.CreateAlbum")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		ReleaseDate string `json:"release_date"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := h.service.CreateAlbum(ctx, userID, req.Title, req.ReleaseDate)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// GetAlbum returns an album with its tracklist
+// @Summary Get album
+// @Description Get album information and tracks ordered by track number
+// @Tags albums
+// @Produce json
+// @Param id path int true "Album ID"
+// @Success 200 {object} album.AlbumDTO "Album"
+// @Failure 404 {object} map[string]string "Album not found"
+// @Router /api/albums/{id} [get]
+func (h *AlbumHandler) GetAlbum(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "AlbumHandler.GetAlbum")
+	defer span.End()
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetAlbum(ctx, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetUserAlbums lists albums of an artist
+// @Summary Get user albums
+// @Description Get albums released by the user, newest first
+// @Tags albums
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {array} album.AlbumDTO "Albums"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /api/users/{username}/albums [get]
+func (h *AlbumHandler) GetUserAlbums(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "AlbumHandler.GetUserAlbums")
+	defer span.End()
+
+	albums, err := h.service.GetArtistAlbums(ctx, c.Param("username"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, album.AlbumsToDTO(albums))
+}
+
+// UpdateAlbum updates album information
+// @Summary Update album
+// @Description Update album title and release date
+// @Tags albums
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param request body AlbumRequest true "Album data"
+// @Success 200 {object} map[string]string "Album updated"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Not album owner"
+// @Failure 404 {object} map[string]string "Album not found"
+// @Router /api/albums/{id} [patch]
+func (h *AlbumHandler) UpdateAlbum(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "AlbumHandler.UpdateAlbum")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		ReleaseDate string `json:"release_date"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateAlbum(ctx, userID, id, req.Title, req.ReleaseDate); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "album updated"})
+}
+
+// DeleteAlbum deletes an album
+// @Summary Delete album
+// @Description Delete an album; its sounds are kept without an album
+// @Tags albums
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Album ID"
+// @Success 200 {object} map[string]string "Album deleted"
+// @Failure 403 {object} map[string]string "Not album owner"
+// @Failure 404 {object} map[string]string "Album not found"
+// @Router /api/albums/{id} [delete]
+func (h *AlbumHandler) DeleteAlbum(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "AlbumHandler.DeleteAlbum")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAlbum(ctx, userID, id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "album deleted"})
+}
+
+// UploadCover uploads album cover art
+// @Summary Upload album cover
+// @Description Upload a jpg, png or webp cover image for the album
+// @Tags albums
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param file formData file true "Cover image"
+// @Success 200 {object} map[string]string "Cover path"
+// @Failure 400 {object} map[string]string "Missing or unsupported file"
+// @Failure 403 {object} map[string]string "Not album owner"
+// @Failure 404 {object} map[string]string "Album not found"
+// @Failure 413 {object} map[string]string "Cover image too large"
+// @Router /api/albums/{id}/cover [post]
+func (h *AlbumHandler) UploadCover(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "AlbumHandler.UploadCover")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCoverSize+1<<20)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errCoverTooLarge.Error()})
+			return
+		}
+		h.logger.WarnContext(ctx, "failed to get file from form", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	fileExt, err := coverExtension(file)
+	switch {
+	case errors.Is(err, errCoverTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errCoverUnsupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		h.logger.ErrorContext(ctx, "failed to read cover file", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
+	}
+
+	uploadDir := filepath.Join("uploads", "covers")
+	fullUploadDir := filepath.Join(h.staticDir, uploadDir)
+
+	if err := ensureUploadDir(fullUploadDir); err != nil {
+		h.logger.ErrorContext(ctx, "failed to create upload directory", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload directory"})
+		return
+	}
+
+	fileName := fmt.Sprintf("album-%d%s", id, fileExt)
+	dbFilePath := filepath.Join(uploadDir, fileName)
+
+	if err := h.service.UpdateCover(ctx, userID, id, dbFilePath); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	if err := c.SaveUploadedFile(file, filepath.Join(fullUploadDir, fileName)); err != nil {
+		h.logger.ErrorContext(ctx, "failed to save file", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save file"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cover_path": dbFilePath})
+}
+
+// SetTrack adds a sound to the album or changes its track number
+// @Summary Set album track
+// @Description Attach one of the current user's sounds to the album; track_number 0 appends it
+// @Tags albums
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param request body AlbumTrackRequest true "Track data"
+// @Success 200 {object} map[string]string "Track set"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Not album or sound owner"
+// @Failure 404 {object} map[string]string "Album or sound not found"
+// @Router /api/albums/{id}/tracks [put]
+func (h *AlbumHandler) SetTrack(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "AlbumHandler.SetTrack")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		SoundID     int `json:"sound_id"`
+		TrackNumber int `json:"track_number"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetTrack(ctx, userID, id, req.SoundID, req.TrackNumber); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "track set"})
+}
+
+// RemoveTrack detaches a sound from the album
+// @Summary Remove album track
+// @Description Remove a sound from the album without deleting it
+// @Tags albums
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param soundId path int true "Sound ID"
+// @Success 200 {object} map[string]string "Track removed"
+// @Failure 403 {object} map[string]string "Not album owner"
+// @Failure 404 {object} map[string]string "Album or track not found"
+// @Router /api/albums/{id}/tracks/{soundId} [delete]
+func (h *AlbumHandler) RemoveTrack(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "AlbumHandler.RemoveTrack")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	soundID, ok := h.pathID(c, "soundId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveTrack(ctx, userID, id, soundID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "track removed"})
+}
+
+func (h *AlbumHandler) pathID(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid path id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h *AlbumHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.AlbumNotFound), errors.Is(err, services.SoundNotFound),
+		errors.Is(err, services.UserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.AlbumForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.logger.ErrorContext(c.Request.Context(), "album request failed", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+)
+
+func coverHeader(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("POST", "/", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	_, header, err := req.FormFile("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
+
+func TestCoverExtension(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpeg := []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	webp := []byte("RIFF\x00\x00\x00\x00WEBPVP8 ")
+
+	tests := []struct {
+		name     string
+		fileName string
+		content  []byte
+		size     int64
+		wantExt  string
+		wantErr  error
+	}{
+		{name: "png", fileName: "cover.png", content: png, wantExt: ".png"},
+		{name: "jpeg", fileName: "cover.jpeg", content: jpeg, wantExt: ".jpg"},
+		{name: "webp", fileName: "cover.webp", content: webp, wantExt: ".webp"},
+		{name: "extension does not decide format", fileName: "cover.webp", content: png, wantExt: ".png"},
+		{name: "html renamed to png", fileName: "cover.png", content: []byte("<html><script>x</script></html>"), wantErr: errCoverUnsupported},
+		{name: "empty file", fileName: "cover.png", content: nil, wantErr: errCoverUnsupported},
+		{name: "too large", fileName: "cover.png", content: png, size: maxCoverSize + 1, wantErr: errCoverTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := coverHeader(t, tt.fileName, tt.content)
+			if tt.size != 0 {
+				header.Size = tt.size
+			}
+
+			ext, err := coverExtension(header)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ext != tt.wantExt {
+				t.Fatalf("ext = %q, want %q", ext, tt.wantExt)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/domain/sound"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChartHandler struct {
+	service *services.ChartService
+	logger  *pkg.CustomLogger
+}
+
+func NewChartHandler(service *services.ChartService, logger *pkg.CustomLogger) *ChartHandler {
+	return &ChartHandler{service: service, logger: logger}
+}
+
+// GetTrending returns the trending chart
+// @Summary Get trending sounds
+// @Description Get sounds ranked by time-decayed hot score built from likes, dislikes and play velocity. Charts are refreshed periodically
+// @Tags charts
+// @Produce json
+// @Param genre query string false "Genre, all genres when empty"
+// @Param window query string false "Time window" Enums(24h, 7d)
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} sound.SoundDTO "Trending sounds"
+// @Failure 400 {object} map[string]string "Invalid window or pagination"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/charts/trending [get]
+func (h *ChartHandler) GetTrending(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "ChartHandler.GetTrending")
+	defer span.End()
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid pagination params", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sounds, err := h.service.GetTrending(ctx, c.Query("genre"), c.Query("window"), limit, offset)
+	if errors.Is(err, services.InvalidChartQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get trending error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get chart"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sound.SoundsToDTO(sounds))
+}
@@ -1,50 +1,109 @@
 package handlers
 
 import (
+	"errors"
+	"net/http"
+	"soundtube/internal/services"
 	"soundtube/pkg"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type CommentHandler struct {
-	logger *pkg.CustomLogger
+	service *services.CommentService
+	logger  *pkg.CustomLogger
 }
 
-func NewCommentHandler(logger *pkg.CustomLogger) *CommentHandler {
-	return &CommentHandler{logger: logger}
+func NewCommentHandler(service *services.CommentService, logger *pkg.CustomLogger) *CommentHandler {
+	return &CommentHandler{service: service, logger: logger}
 }
 
 // GetComments retrieves comments for a specific sound
 // @Summary Get sound comments
-// @Description Get all comments for a specific sound
+// @Description Get a page of comments for a specific sound, oldest first
 // @Tags comments
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "Sound ID"
-// @Success 200 {array} object "List of comments"
+// @Param limit query int false "Page size"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} comment.CommentDTO "List of comments"
 // @Failure 400 {object} map[string]string "Invalid sound ID"
+// @Failure 404 {object} map[string]string "Sound not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/sounds/{id}/comments [get]
 func (h *CommentHandler) GetComments(c *gin.Context) {
-	//TODO
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "CommentHandler.GetComments")
+	defer span.End()
+
+	soundID, ok := h.pathID(c)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comments, err := h.service.GetComments(ctx, soundID, limit, offset)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
 }
 
 // CreateComment creates a new comment for a sound
 // @Summary Create comment
-// @Description Add a new comment to a sound
+// @Description Add a new comment to a sound, or a reply when parent_id is set
 // @Tags comments
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "Sound ID"
 // @Param request body CreateCommentRequest true "Comment data"
-// @Success 201 {object} object "Comment created successfully"
+// @Success 201 {object} comment.CommentDTO "Comment created successfully"
 // @Failure 400 {object} map[string]string "Invalid input or sound ID"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sound or parent comment not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/sounds/{id}/comments [post]
 func (h *CommentHandler) CreateComment(c *gin.Context) {
-	//TODO
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "CommentHandler.CreateComment")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	soundID, ok := h.pathID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Content  string `json:"content"`
+		ParentID int    `json:"parent_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.CreateComment(ctx, userID, soundID, req.ParentID, req.Content)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
 }
 
 // UpdateComment updates an existing comment
@@ -56,26 +115,98 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Comment ID"
 // @Param request body UpdateCommentRequest true "Updated comment data"
-// @Success 200 {object} object "Comment updated successfully"
+// @Success 200 {object} comment.CommentDTO "Comment updated successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 403 {object} map[string]string "Forbidden - not comment owner"
 // @Failure 404 {object} map[string]string "Comment not found"
 // @Router /api/comments/{id} [patch]
 func (h *CommentHandler) UpdateComment(c *gin.Context) {
-	//TODO
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "CommentHandler.UpdateComment")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Content string `json:"content"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.UpdateComment(ctx, userID, id, req.Content)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
 }
 
 // DeleteComment deletes a comment
 // @Summary Delete comment
-// @Description Delete a comment by ID
+// @Description Delete a comment by ID together with its replies. The sound's author and admins may delete any comment on it
 // @Tags comments
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "Comment ID"
-// @Success 200 {object} object "Comment deleted successfully"
-// @Failure 403 {object} map[string]string "Forbidden - not comment owner"
+// @Success 200 {object} map[string]string "Comment deleted successfully"
+// @Failure 403 {object} map[string]string "Forbidden - not comment owner or moderator"
 // @Failure 404 {object} map[string]string "Comment not found"
 // @Router /api/comments/{id} [delete]
 func (h *CommentHandler) DeleteComment(c *gin.Context) {
-	//TODO
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "CommentHandler.DeleteComment")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteComment(ctx, userID, c.GetString("role"), id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment deleted"})
+}
+
+func (h *CommentHandler) pathID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid path id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h *CommentHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.CommentNotFound), errors.Is(err, services.SoundNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.CommentForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.InvalidComment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.ErrorContext(c.Request.Context(), "comment request failed", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process comment"})
+	}
 }
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"soundtube/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserID reads the user id stored by AuthMiddleware. On failure it writes the
+// error response, so callers only need to return.
+func currentUserID(ctx context.Context, c *gin.Context, logger *pkg.CustomLogger) (int, bool) {
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return 0, false
+	}
+
+	userID, ok := userIDRaw.(int)
+	if !ok {
+		logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return 0, false
+	}
+
+	return userID, true
+}
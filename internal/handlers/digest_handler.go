@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DigestHandler struct {
+	service *services.DigestService
+	logger  *pkg.CustomLogger
+}
+
+func NewDigestHandler(service *services.DigestService, logger *pkg.CustomLogger) *DigestHandler {
+	return &DigestHandler{service: service, logger: logger}
+}
+
+// GetSettings returns the current user's email digest settings
+// @Summary Get email digest settings
+// @Description An empty locale means the server default
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} notification.DigestSettingsDTO "Digest settings"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/me/email-digest [get]
+func (h *DigestHandler) GetSettings(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "DigestHandler.GetSettings")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	settings, err := h.service.GetSettings(ctx, userID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get digest settings error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get digest settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings changes the current user's email digest settings
+// @Summary Update email digest settings
+// @Tags notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body DigestSettingsRequest true "Digest settings"
+// @Success 200 {object} notification.DigestSettingsDTO "Updated settings"
+// @Failure 400 {object} map[string]string "Invalid frequency or locale"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/me/email-digest [put]
+func (h *DigestHandler) UpdateSettings(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "DigestHandler.UpdateSettings")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Frequency string `json:"frequency"`
+		Locale    string `json:"locale"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(ctx, userID, req.Frequency, req.Locale)
+	switch {
+	case errors.Is(err, services.InvalidDigestSettings):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		h.logger.ErrorContext(ctx, "update digest settings error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update digest settings"})
+	default:
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+// UnsubscribePage asks to confirm unsubscribing from digests
+// @Summary Unsubscribe confirmation page
+// @Description Opened from the link in digest emails. It only renders a confirmation form so that link scanners cannot unsubscribe users
+// @Tags notifications
+// @Produce html
+// @Param token query string true "Signed unsubscribe token"
+// @Success 200 {string} string "Confirmation page"
+// @Failure 400 {string} string "Invalid link"
+// @Router /api/email/unsubscribe [get]
+func (h *DigestHandler) UnsubscribePage(c *gin.Context) {
+	token := c.Query("token")
+
+	if _, err := h.service.VerifyUnsubscribeToken(token); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid unsubscribe link", err)
+		c.HTML(http.StatusBadRequest, "unsubscribe.html", gin.H{"Invalid": true})
+		return
+	}
+
+	c.HTML(http.StatusOK, "unsubscribe.html", gin.H{"Token": token})
+}
+
+// Unsubscribe turns email digests off
+// @Summary Unsubscribe from email digests
+// @Description Target of the List-Unsubscribe header (RFC 8058 one-click) and of the confirmation form
+// @Tags notifications
+// @Produce html
+// @Param token query string true "Signed unsubscribe token"
+// @Success 200 {string} string "Unsubscribed"
+// @Failure 400 {string} string "Invalid link"
+// @Router /api/email/unsubscribe [post]
+func (h *DigestHandler) Unsubscribe(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "DigestHandler.Unsubscribe")
+	defer span.End()
+
+	err := h.service.Unsubscribe(ctx, c.Query("token"))
+	switch {
+	case errors.Is(err, services.UnsubscribeLinkInvalid):
+		c.HTML(http.StatusBadRequest, "unsubscribe.html", gin.H{"Invalid": true})
+	case err != nil:
+		h.logger.ErrorContext(ctx, "unsubscribe error", err)
+		c.HTML(http.StatusInternalServerError, "unsubscribe.html", gin.H{"Failed": true})
+	default:
+		c.HTML(http.StatusOK, "unsubscribe.html", gin.H{"Done": true})
+	}
+}
@@ -0,0 +1,14 @@
+// album_dto.go
+package handlers
+
+// AlbumRequest represents the request body for creating or updating an album
+type AlbumRequest struct {
+	Title       string `json:"title" example:"Summer Vibes"`
+	ReleaseDate string `json:"release_date" example:"2025-06-21"`
+}
+
+// AlbumTrackRequest represents the request body for placing a sound on an album
+type AlbumTrackRequest struct {
+	SoundID     int `json:"sound_id" example:"42"`
+	TrackNumber int `json:"track_number" example:"3"`
+}
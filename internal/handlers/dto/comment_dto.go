@@ -3,7 +3,8 @@ package handlers
 
 // CreateCommentRequest represents the request body for creating a comment
 type CreateCommentRequest struct {
-	Content string `json:"content" example:"Great sound!"`
+	Content  string `json:"content" example:"Great sound!"`
+	ParentID int    `json:"parent_id,omitempty" example:"0"`
 }
 
 // UpdateCommentRequest represents the request body for updating a comment
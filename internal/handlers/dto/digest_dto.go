@@ -0,0 +1,8 @@
+// digest_dto.go
+package handlers
+
+// DigestSettingsRequest represents email digest settings
+type DigestSettingsRequest struct {
+	Frequency string `json:"frequency" example:"weekly" enums:"off,daily,weekly"`
+	Locale    string `json:"locale" example:"en" enums:"en,ru"`
+}
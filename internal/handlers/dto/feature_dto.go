@@ -0,0 +1,11 @@
+// feature_dto.go
+package handlers
+
+// FeatureFlagRequest overrides a feature flag
+type FeatureFlagRequest struct {
+	Description string   `json:"description" example:"New audio player"`
+	Enabled     bool     `json:"enabled" example:"true"`
+	Percentage  int      `json:"percentage" example:"10" minimum:"0" maximum:"100"`
+	Users       []int    `json:"users" example:"1,2"`
+	Roles       []string `json:"roles" example:"admin"`
+}
@@ -0,0 +1,9 @@
+// listening_dto.go
+package handlers
+
+// ListenEventRequest represents a player event reported by the client
+type ListenEventRequest struct {
+	SessionID string `json:"session_id" example:"5f0c6c1e-8d1a-4d8e-9a57-0d3b8f1f6a41"`
+	Event     string `json:"event" example:"progress" enums:"start,progress,complete"`
+	Position  int    `json:"position" example:"35"`
+}
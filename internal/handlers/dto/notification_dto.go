@@ -0,0 +1,11 @@
+// notification_dto.go
+package handlers
+
+// NotificationPreferencesRequest switches notification types on or off
+type NotificationPreferencesRequest struct {
+	Comment    *bool `json:"comment,omitempty" example:"true"`
+	Reply      *bool `json:"reply,omitempty" example:"true"`
+	Follow     *bool `json:"follow,omitempty" example:"true"`
+	Like       *bool `json:"like,omitempty" example:"false"`
+	Moderation *bool `json:"moderation,omitempty" example:"true"`
+}
@@ -0,0 +1,26 @@
+// playlist_dto.go
+package handlers
+
+// CreatePlaylistRequest represents the request body for creating a playlist
+type CreatePlaylistRequest struct {
+	Title       string `json:"title" example:"Late Night Drive"`
+	Description string `json:"description" example:"Slow synths for the road"`
+	Visibility  string `json:"visibility" example:"public" enums:"public,unlisted,private"`
+}
+
+// UpdatePlaylistRequest represents the request body for updating a playlist
+type UpdatePlaylistRequest struct {
+	Title       string `json:"title" example:"Late Night Drive"`
+	Description string `json:"description" example:"Slow synths for the road"`
+	Visibility  string `json:"visibility" example:"unlisted" enums:"public,unlisted,private"`
+}
+
+// AddTrackRequest represents the request body for adding a sound to a playlist
+type AddTrackRequest struct {
+	SoundID int `json:"sound_id" example:"42"`
+}
+
+// MoveTrackRequest represents the request body for reordering a playlist track
+type MoveTrackRequest struct {
+	AfterTrackID int `json:"after_track_id" example:"7"`
+}
@@ -3,9 +3,11 @@ package handlers
 
 // Sound represents the request body for create sound
 type CreateSoundRequest struct {
-	Name  string `json:"name" example:"My Awesome Sound"`
-	Album string `json:"album" example:"Summer Vibes"`
-	Genre string `json:"genre" example:"Electronic"`
+	Name        string `json:"name" example:"My Awesome Sound"`
+	Album       string `json:"album" example:"Summer Vibes"`
+	AlbumID     int    `json:"album_id" example:"3"`
+	TrackNumber int    `json:"track_number" example:"1"`
+	Genre       string `json:"genre" example:"Electronic"`
 }
 
 // Sound represents the request body for update sound
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	service *services.ExportService
+	logger  *pkg.CustomLogger
+}
+
+func NewExportHandler(service *services.ExportService, logger *pkg.CustomLogger) *ExportHandler {
+	return &ExportHandler{service: service, logger: logger}
+}
+
+// RequestExport queues a personal data export for the current user
+// @Summary Request data export
+// @Description Queue a ZIP archive with the user's profile, sounds, audio files, reactions, comments and sessions. A signed download link is sent by email
+// @Tags me
+// @Security BearerAuth
+// @Produce json
+// @Success 202 {object} map[string]string "Export queued"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 503 {object} map[string]string "Export queue is full"
+// @Router /api/me/export [post]
+func (h *ExportHandler) RequestExport(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "ExportHandler.RequestExport")
+	defer span.End()
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		h.logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	userID, ok := userIDRaw.(int)
+	if !ok {
+		h.logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.service.RequestExport(ctx, userID); err != nil {
+		h.logger.ErrorContext(ctx, "failed to queue export", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "export queued, the download link will be sent by email"})
+}
+
+// DownloadExport serves an export archive by signed link
+// @Summary Download data export
+// @Description Download a previously generated export archive using the signed link from the email
+// @Tags me
+// @Produce application/zip
+// @Param id path string true "Archive ID"
+// @Param expires query int true "Link expiration (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file "Export archive"
+// @Failure 403 {object} map[string]string "Invalid link"
+// @Failure 410 {object} map[string]string "Link expired"
+// @Router /api/exports/{id} [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "ExportHandler.DownloadExport")
+	defer span.End()
+
+	path, err := h.service.ResolveDownload(ctx, c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if errors.Is(err, services.ExportLinkExpired) {
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.FileAttachment(path, "soundtube-export.zip")
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/domain/feature"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeatureHandler struct {
+	service *services.FeatureService
+	logger  *pkg.CustomLogger
+}
+
+func NewFeatureHandler(service *services.FeatureService, logger *pkg.CustomLogger) *FeatureHandler {
+	return &FeatureHandler{service: service, logger: logger}
+}
+
+// GetMyFeatures returns which features are on for the current user
+// @Summary Get my features
+// @Description Every known feature flag evaluated for the current user
+// @Tags features
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]bool "Feature states by name"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/me/features [get]
+func (h *FeatureHandler) GetMyFeatures(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FeatureHandler.GetMyFeatures")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.Evaluate(feature.Subject{UserID: userID, Role: c.GetString("role")}))
+}
+
+// GetFlags lists every feature flag in effect
+// @Summary List feature flags
+// @Description Configured flags and admin overrides; source tells which one applies
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} feature.FlagDTO "Feature flags"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not an admin"
+// @Router /api/admin/features [get]
+func (h *FeatureHandler) GetFlags(c *gin.Context) {
+	_, span := h.logger.GetTracer().Start(c.Request.Context(), "FeatureHandler.GetFlags")
+	defer span.End()
+
+	flags := h.service.List()
+
+	dtos := make([]*feature.FlagDTO, 0, len(flags))
+	for _, flag := range flags {
+		dtos = append(dtos, flag.ToDTO())
+	}
+
+	c.JSON(http.StatusOK, dtos)
+}
+
+// SetFlag overrides a feature flag on every instance
+// @Summary Override a feature flag
+// @Description The override replaces the configured flag until it is reset
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param name path string true "Flag name"
+// @Param request body FeatureFlagRequest true "Flag state"
+// @Success 200 {object} feature.FlagDTO "Stored override"
+// @Failure 400 {object} map[string]string "Invalid name or percentage"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not an admin"
+// @Router /api/admin/features/{name} [put]
+func (h *FeatureHandler) SetFlag(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FeatureHandler.SetFlag")
+	defer span.End()
+
+	var req struct {
+		Description string   `json:"description"`
+		Enabled     bool     `json:"enabled"`
+		Percentage  int      `json:"percentage"`
+		Users       []int    `json:"users"`
+		Roles       []string `json:"roles"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	flag, err := h.service.Set(ctx, c.Param("name"), req.Description, req.Enabled, req.Percentage, req.Users, req.Roles)
+	switch {
+	case errors.Is(err, services.InvalidFeatureFlag):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		h.logger.ErrorContext(ctx, "set feature flag error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set feature flag"})
+	default:
+		c.JSON(http.StatusOK, flag.ToDTO())
+	}
+}
+
+// ResetFlag removes a feature flag override
+// @Summary Reset a feature flag
+// @Description Drops the override so the configured flag applies again
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param name path string true "Flag name"
+// @Success 200 {object} map[string]string "Override removed"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not an admin"
+// @Failure 404 {object} map[string]string "No override for this flag"
+// @Router /api/admin/features/{name} [delete]
+func (h *FeatureHandler) ResetFlag(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FeatureHandler.ResetFlag")
+	defer span.End()
+
+	err := h.service.Reset(ctx, c.Param("name"))
+	switch {
+	case errors.Is(err, services.FeatureFlagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		h.logger.ErrorContext(ctx, "reset feature flag error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset feature flag"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "feature flag override removed"})
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	service *services.FeedService
+	logger  *pkg.CustomLogger
+}
+
+func NewFeedHandler(service *services.FeedService, logger *pkg.CustomLogger) *FeedHandler {
+	return &FeedHandler{service: service, logger: logger}
+}
+
+// GetFeed returns activity of followed users
+// @Summary Get activity feed
+// @Description Get newly published sounds, reposts and comments from followed users, newest first
+// @Tags feed
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Page size (max 100)"
+// @Param before query int false "Return activities older than this unix timestamp in milliseconds"
+// @Success 200 {object} object "Feed page with next cursor"
+// @Failure 400 {object} map[string]string "Invalid params"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/feed [get]
+func (h *FeedHandler) GetFeed(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FeedHandler.GetFeed")
+	defer span.End()
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		h.logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	userID, ok := userIDRaw.(int)
+	if !ok {
+		h.logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	limit, _, err := parsePagination(c)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid pagination params", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before := time.Now()
+	if raw := c.Query("before"); raw != "" {
+		millis, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			h.logger.WarnContext(ctx, "invalid before param", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be a unix timestamp in milliseconds"})
+			return
+		}
+		before = time.UnixMilli(millis)
+	}
+
+	activities, err := h.service.GetFeed(ctx, userID, before, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get feed failed", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feed"})
+		return
+	}
+
+	var next *int64
+	if len(activities) == limit {
+		cursor := activities[len(activities)-1].CreatedAt.UnixMilli()
+		next = &cursor
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"activities":  activities,
+		"next_before": next,
+	})
+}
+
+// Repost shares a sound to the current user's followers
+// @Summary Repost sound
+// @Description Repost a sound so it appears in followers' feeds. Reposting again is a no-op
+// @Tags feed
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Sound ID"
+// @Success 200 {object} map[string]string "Reposted successfully"
+// @Failure 400 {object} map[string]string "Invalid sound ID or own sound"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sound not found"
+// @Router /api/sounds/{id}/repost [put]
+func (h *FeedHandler) Repost(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FeedHandler.Repost")
+	defer span.End()
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		h.logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	userID, ok := userIDRaw.(int)
+	if !ok {
+		h.logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	soundID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(ctx, "invalid sound id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sound ID"})
+		return
+	}
+
+	if err := h.service.Repost(ctx, userID, soundID); err != nil {
+		switch {
+		case errors.Is(err, services.SoundNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.RepostOwnSound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.ErrorContext(ctx, "repost failed", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to repost"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "reposted"})
+}
+
+// Unrepost removes a repost
+// @Summary Remove repost
+// @Description Remove a previously made repost
+// @Tags feed
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Sound ID"
+// @Success 200 {object} map[string]string "Repost removed"
+// @Failure 400 {object} map[string]string "Invalid sound ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/sounds/{id}/repost [delete]
+func (h *FeedHandler) Unrepost(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FeedHandler.Unrepost")
+	defer span.End()
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		h.logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	userID, ok := userIDRaw.(int)
+	if !ok {
+		h.logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	soundID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(ctx, "invalid sound id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sound ID"})
+		return
+	}
+
+	if err := h.service.Unrepost(ctx, userID, soundID); err != nil {
+		h.logger.ErrorContext(ctx, "unrepost failed", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove repost"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "repost removed"})
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	service *services.FollowService
+	logger  *pkg.CustomLogger
+}
+
+func NewFollowHandler(service *services.FollowService, logger *pkg.CustomLogger) *FollowHandler {
+	return &FollowHandler{service: service, logger: logger}
+}
+
+// Follow subscribes the current user to another user
+// @Summary Follow user
+// @Description Follow a user to see their activity in the feed
+// @Tags follows
+// @Security BearerAuth
+// @Produce json
+// @Param username path string true "Username to follow"
+// @Success 200 {object} map[string]string "Followed successfully"
+// @Failure 400 {object} map[string]string "Invalid follow"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /api/users/{username}/follow [put]
+func (h *FollowHandler) Follow(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FollowHandler.Follow")
+	defer span.End()
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		h.logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	userID, ok := userIDRaw.(int)
+	if !ok {
+		h.logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := h.service.Follow(ctx, userID, c.Param("username"))
+	if errors.Is(err, services.UserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "follow failed", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "followed"})
+}
+
+// Unfollow unsubscribes the current user from another user
+// @Summary Unfollow user
+// @Description Stop following a user
+// @Tags follows
+// @Security BearerAuth
+// @Produce json
+// @Param username path string true "Username to unfollow"
+// @Success 200 {object} map[string]string "Unfollowed successfully"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /api/users/{username}/follow [delete]
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FollowHandler.Unfollow")
+	defer span.End()
+
+	userIDRaw, exists := c.Get("user_id")
+	if !exists {
+		h.logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	userID, ok := userIDRaw.(int)
+	if !ok {
+		h.logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := h.service.Unfollow(ctx, userID, c.Param("username"))
+	if errors.Is(err, services.UserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "unfollow failed", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "unfollowed"})
+}
+
+// GetFollowers lists followers of a user
+// @Summary Get followers
+// @Description Get a page of users following the given user
+// @Tags follows
+// @Produce json
+// @Param username path string true "Username"
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} follow.UserSummary "Followers"
+// @Failure 400 {object} map[string]string "Invalid pagination params"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /api/users/{username}/followers [get]
+func (h *FollowHandler) GetFollowers(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FollowHandler.GetFollowers")
+	defer span.End()
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid pagination params", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	followers, err := h.service.GetFollowers(ctx, c.Param("username"), limit, offset)
+	if errors.Is(err, services.UserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get followers failed", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get followers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, followers)
+}
+
+// GetFollowing lists users followed by a user
+// @Summary Get following
+// @Description Get a page of users the given user follows
+// @Tags follows
+// @Produce json
+// @Param username path string true "Username"
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} follow.UserSummary "Following"
+// @Failure 400 {object} map[string]string "Invalid pagination params"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /api/users/{username}/following [get]
+func (h *FollowHandler) GetFollowing(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "FollowHandler.GetFollowing")
+	defer span.End()
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid pagination params", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	following, err := h.service.GetFollowing(ctx, c.Param("username"), limit, offset)
+	if errors.Is(err, services.UserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get following failed", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get following"})
+		return
+	}
+
+	c.JSON(http.StatusOK, following)
+}
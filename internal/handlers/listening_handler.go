@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/domain/listening"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ListeningHandler struct {
+	service *services.ListeningService
+	logger  *pkg.CustomLogger
+}
+
+func NewListeningHandler(service *services.ListeningService, logger *pkg.CustomLogger) *ListeningHandler {
+	return &ListeningHandler{service: service, logger: logger}
+}
+
+// TrackListen records a player event
+// @Summary Track listening event
+// @Description Report start, progress heartbeats and completion of playback. A session must begin with a start event; it is counted as one play once 30 seconds (or a whole shorter sound) were listened, by both the reported position and the server clock
+// @Tags listening
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Sound ID"
+// @Param request body ListenEventRequest true "Player event"
+// @Success 202 {object} map[string]string "Event accepted"
+// @Failure 400 {object} map[string]string "Invalid event"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sound not found"
+// @Router /api/sounds/{id}/listens [post]
+func (h *ListeningHandler) TrackListen(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "ListeningHandler.TrackListen")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	soundID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid sound id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sound ID"})
+		return
+	}
+
+	var req struct {
+		SessionID string `json:"session_id"`
+		Event     string `json:"event"`
+		Position  int    `json:"position"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.service.Track(ctx, userID, soundID, req.SessionID, req.Event, req.Position)
+	switch {
+	case errors.Is(err, services.InvalidListenEvent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.SoundNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		h.logger.ErrorContext(ctx, "track listen error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to track event"})
+	default:
+		c.JSON(http.StatusAccepted, gin.H{"message": "event accepted"})
+	}
+}
+
+// GetHistory returns the current user's listening history
+// @Summary Get listening history
+// @Description Get sounds played by the current user, most recent first
+// @Tags listening
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} listening.HistoryEntryDTO "History"
+// @Failure 400 {object} map[string]string "Invalid pagination params"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/me/history [get]
+func (h *ListeningHandler) GetHistory(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "ListeningHandler.GetHistory")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid pagination params", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, err := h.service.GetHistory(ctx, userID, limit, offset)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get history error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, listening.HistoryToDTO(entries))
+}
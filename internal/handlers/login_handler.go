@@ -39,7 +39,7 @@ func (h *LoginHandler) Login(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn(JsonInputFormat, err).WithTrace(ctx)
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
@@ -50,7 +50,7 @@ func (h *LoginHandler) Login(c *gin.Context) {
 
 	token, err := h.service.Login(ctx, req.Username, req.Password)
 	if err != nil {
-		h.logger.Error("login failed", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "login failed", err)
 		c.JSON(http.StatusUnauthorized, err)
 		return
 	}
@@ -82,13 +82,13 @@ func (h *LoginHandler) Logout(c *gin.Context) {
 	)
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn(JsonInputFormat, err).WithTrace(ctx)
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.service.Logout(c.Request.Context(), req.Token); err != nil {
-		h.logger.Error("logout failed", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "logout failed", err)
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	service *services.NotificationService
+	logger  *pkg.CustomLogger
+}
+
+func NewNotificationHandler(service *services.NotificationService, logger *pkg.CustomLogger) *NotificationHandler {
+	return &NotificationHandler{service: service, logger: logger}
+}
+
+// GetNotifications returns the current user's notifications
+// @Summary Get notifications
+// @Description Get notifications of the current user, most recently updated first, with the unread count. Repeated events (likes on one sound, new followers, ...) are aggregated while unread
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} notification.InboxDTO "Notifications"
+// @Failure 400 {object} map[string]string "Invalid query params"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "NotificationHandler.GetNotifications")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid pagination params", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		if unreadOnly, err = strconv.ParseBool(raw); err != nil {
+			h.logger.WarnContext(ctx, "invalid unread param", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unread must be a boolean"})
+			return
+		}
+	}
+
+	inbox, err := h.service.GetNotifications(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get notifications error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, inbox)
+}
+
+// GetUnreadCount returns the number of unread notifications
+// @Summary Get unread notification count
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]int "Unread count"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "NotificationHandler.GetUnreadCount")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	unread, err := h.service.CountUnread(ctx, userID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "count unread notifications error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": unread})
+}
+
+// MarkRead marks one notification as read
+// @Summary Mark notification as read
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]string "Marked as read"
+// @Failure 400 {object} map[string]string "Invalid notification ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Router /api/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "NotificationHandler.MarkRead")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid notification id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	err = h.service.MarkRead(ctx, userID, id)
+	switch {
+	case errors.Is(err, services.NotificationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		h.logger.ErrorContext(ctx, "mark notification read error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notification as read"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+	}
+}
+
+// MarkAllRead marks every notification of the current user as read
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]string "Marked as read"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "NotificationHandler.MarkAllRead")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	if err := h.service.MarkAllRead(ctx, userID); err != nil {
+		h.logger.ErrorContext(ctx, "mark all notifications read error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notifications as read"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "all notifications marked as read"})
+}
+
+// GetPreferences returns which notification types the user receives
+// @Summary Get notification preferences
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]bool "Enabled flag per notification type"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "NotificationHandler.GetPreferences")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.service.GetPreferences(ctx, userID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get notification preferences error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdatePreferences switches notification types on or off
+// @Summary Update notification preferences
+// @Description Types missing from the body keep their current setting
+// @Tags notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body NotificationPreferencesRequest true "Enabled flag per notification type"
+// @Success 200 {object} map[string]bool "Updated preferences"
+// @Failure 400 {object} map[string]string "Invalid notification type"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "NotificationHandler.UpdatePreferences")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req map[string]bool
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preferences, err := h.service.UpdatePreferences(ctx, userID, req)
+	switch {
+	case errors.Is(err, services.InvalidNotificationType):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		h.logger.ErrorContext(ctx, "update notification preferences error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update preferences"})
+	default:
+		c.JSON(http.StatusOK, preferences)
+	}
+}
@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/domain/playlist"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistHandler struct {
+	service *services.PlaylistService
+	logger  *pkg.CustomLogger
+}
+
+func NewPlaylistHandler(service *services.PlaylistService, logger *pkg.CustomLogger) *PlaylistHandler {
+	return &PlaylistHandler{service: service, logger: logger}
+}
+
+// CreatePlaylist creates a new playlist
+// @Summary Create playlist
+// @Description Create a playlist owned by the current user
+// @Tags playlists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body CreatePlaylistRequest true "Playlist data"
+// @Success 201 {object} playlist.PlaylistDTO "Created playlist"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/playlists [post]
+func (h *PlaylistHandler) CreatePlaylist(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.CreatePlaylist")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Visibility  string `json:"visibility"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.service.CreatePlaylist(ctx, userID, req.Title, req.Description, req.Visibility)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetMyPlaylists lists playlists owned by or shared with the current user
+// @Summary Get my playlists
+// @Description Get playlists the current user owns or collaborates on
+// @Tags playlists
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} playlist.PlaylistDTO "Playlists"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/playlists [get]
+func (h *PlaylistHandler) GetMyPlaylists(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.GetMyPlaylists")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	playlists, err := h.service.GetUserPlaylists(ctx, userID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist.PlaylistsToDTO(playlists))
+}
+
+// GetPlaylist returns a playlist with its tracks
+// @Summary Get playlist
+// @Description Get a playlist with ordered tracks. Private playlists are visible to owner and collaborators only
+// @Tags playlists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} playlist.PlaylistDTO "Playlist"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Router /api/playlists/{id} [get]
+func (h *PlaylistHandler) GetPlaylist(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.GetPlaylist")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	dto, err := h.service.GetPlaylist(ctx, userID, id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto)
+}
+
+// UpdatePlaylist updates playlist info
+// @Summary Update playlist
+// @Description Update title, description and visibility. Owner only
+// @Tags playlists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param request body UpdatePlaylistRequest true "Playlist data"
+// @Success 200 {object} map[string]string "Playlist updated"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Router /api/playlists/{id} [patch]
+func (h *PlaylistHandler) UpdatePlaylist(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.UpdatePlaylist")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Visibility  string `json:"visibility"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdatePlaylist(ctx, userID, id, req.Title, req.Description, req.Visibility); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "playlist updated"})
+}
+
+// DeletePlaylist deletes a playlist
+// @Summary Delete playlist
+// @Description Delete a playlist. Owner only
+// @Tags playlists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} map[string]string "Playlist deleted"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Router /api/playlists/{id} [delete]
+func (h *PlaylistHandler) DeletePlaylist(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.DeletePlaylist")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePlaylist(ctx, userID, id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "playlist deleted"})
+}
+
+// AddCollaborator grants a user permission to edit tracks
+// @Summary Add collaborator
+// @Description Allow a user to add, remove and reorder tracks. Owner only
+// @Tags playlists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param username path string true "Collaborator username"
+// @Success 200 {object} map[string]string "Collaborator added"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Playlist or user not found"
+// @Failure 409 {object} map[string]string "User is the owner"
+// @Router /api/playlists/{id}/collaborators/{username} [put]
+func (h *PlaylistHandler) AddCollaborator(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.AddCollaborator")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.AddCollaborator(ctx, userID, id, c.Param("username")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "collaborator added"})
+}
+
+// RemoveCollaborator revokes a user's permission to edit tracks
+// @Summary Remove collaborator
+// @Description Revoke collaborator access. Owner only
+// @Tags playlists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param username path string true "Collaborator username"
+// @Success 200 {object} map[string]string "Collaborator removed"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Playlist or user not found"
+// @Router /api/playlists/{id}/collaborators/{username} [delete]
+func (h *PlaylistHandler) RemoveCollaborator(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.RemoveCollaborator")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveCollaborator(ctx, userID, id, c.Param("username")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "collaborator removed"})
+}
+
+// AddTrack appends a sound to a playlist
+// @Summary Add track
+// @Description Append a sound to the end of the playlist. Owner or collaborator
+// @Tags playlists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param request body AddTrackRequest true "Sound to add"
+// @Success 201 {object} map[string]int "Track added"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Playlist or sound not found"
+// @Router /api/playlists/{id}/tracks [post]
+func (h *PlaylistHandler) AddTrack(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.AddTrack")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		SoundID int `json:"sound_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trackID, err := h.service.AddTrack(ctx, userID, id, req.SoundID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"track_id": trackID})
+}
+
+// RemoveTrack removes a track from a playlist
+// @Summary Remove track
+// @Description Remove a track from the playlist. Owner or collaborator
+// @Tags playlists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param trackId path int true "Track ID"
+// @Success 200 {object} map[string]string "Track removed"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Router /api/playlists/{id}/tracks/{trackId} [delete]
+func (h *PlaylistHandler) RemoveTrack(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.RemoveTrack")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	trackID, ok := h.pathID(c, "trackId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveTrack(ctx, userID, id, trackID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "track removed"})
+}
+
+// MoveTrack reorders a track within a playlist
+// @Summary Move track
+// @Description Move a track right after another track, or to the top when after_track_id is 0. Owner or collaborator
+// @Tags playlists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param trackId path int true "Track ID"
+// @Param request body MoveTrackRequest true "New position"
+// @Success 200 {object} map[string]string "Track moved"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Playlist or track not found"
+// @Router /api/playlists/{id}/tracks/{trackId} [patch]
+func (h *PlaylistHandler) MoveTrack(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.MoveTrack")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	trackID, ok := h.pathID(c, "trackId")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AfterTrackID int `json:"after_track_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.MoveTrack(ctx, userID, id, trackID, req.AfterTrackID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "track moved"})
+}
+
+// ExportPlaylist exports a playlist as M3U8 or XSPF
+// @Summary Export playlist
+// @Description Download the playlist in M3U8 or XSPF format
+// @Tags playlists
+// @Security BearerAuth
+// @Produce application/vnd.apple.mpegurl,application/xspf+xml
+// @Param id path int true "Playlist ID"
+// @Param format query string true "Export format" Enums(m3u8, xspf)
+// @Success 200 {file} file "Playlist file"
+// @Failure 400 {object} map[string]string "Invalid format"
+// @Failure 404 {object} map[string]string "Playlist not found"
+// @Router /api/playlists/{id}/export [get]
+func (h *PlaylistHandler) ExportPlaylist(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "PlaylistHandler.ExportPlaylist")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	id, ok := h.pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", services.ExportFormatM3U8)
+
+	body, contentType, err := h.service.Export(ctx, userID, id, format)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=playlist-"+strconv.Itoa(id)+"."+format)
+	c.Data(http.StatusOK, contentType, body)
+}
+
+func (h *PlaylistHandler) pathID(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		h.logger.WarnContext(c.Request.Context(), "invalid path id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h *PlaylistHandler) writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.PlaylistNotFound), errors.Is(err, services.TrackNotFound),
+		errors.Is(err, services.SoundNotFound), errors.Is(err, services.UserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.PlaylistForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.PlaylistOwnerCollaborator):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.ErrorContext(c.Request.Context(), "playlist request failed", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
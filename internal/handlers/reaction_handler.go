@@ -39,14 +39,14 @@ func (h *ReactionHandler) SetReactionSound(c *gin.Context) {
 
 	userIDRaw, exists := c.Get("user_id")
 	if !exists {
-		h.logger.Error("invalid user_id in context", errors.New("user_id not found")).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	userID, ok := userIDRaw.(int)
 	if !ok {
-		h.logger.Error("invalid user_id type", errors.New("type assertion failed")).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
@@ -54,7 +54,7 @@ func (h *ReactionHandler) SetReactionSound(c *gin.Context) {
 	soundIDStr := c.Param("id")
 	soundID, err := strconv.Atoi(soundIDStr)
 	if err != nil {
-		h.logger.Error("invalid soind id", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "invalid soind id", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -64,13 +64,13 @@ func (h *ReactionHandler) SetReactionSound(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("invalid request body", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "invalid request body", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	if req.ReactionType != "like" && req.ReactionType != "dislike" {
-		h.logger.Error("invalid reaction type", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "invalid reaction type", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "reaction type must be like or dislike"})
 		return
 	}
@@ -111,14 +111,14 @@ func (h *ReactionHandler) GetReactionSound(c *gin.Context) {
 
 	userIDRaw, exists := c.Get("user_id")
 	if !exists {
-		h.logger.Error("invalid user_id in context", errors.New("user_id not found"))
+		h.logger.ErrorContext(ctx, "invalid user_id in context", errors.New("user_id not found"))
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	userID, ok := userIDRaw.(int)
 	if !ok {
-		h.logger.Error("invalid user_id type", errors.New("type assertion failed"))
+		h.logger.ErrorContext(ctx, "invalid user_id type", errors.New("type assertion failed"))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 		return
 	}
@@ -126,14 +126,14 @@ func (h *ReactionHandler) GetReactionSound(c *gin.Context) {
 	soundIDStr := c.Param("id")
 	soundID, err := strconv.Atoi(soundIDStr)
 	if err != nil {
-		h.logger.Error("invalid sound id", err)
+		h.logger.ErrorContext(ctx, "invalid sound id", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sound ID"})
 		return
 	}
 
 	reactions, err := h.service.GetSoundReactions(ctx, userID, soundID)
 	if err != nil {
-		h.logger.Error("failed to get reactions", err)
+		h.logger.ErrorContext(ctx, "failed to get reactions", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reactions"})
 		return
 	}
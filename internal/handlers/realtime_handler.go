@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"soundtube/internal/domain/feature"
+	"soundtube/internal/domain/realtime"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = wsPongWait * 9 / 10
+	wsMaxMessageSize = 4096
+)
+
+// commentsFeature gates the comment routes; comment events follow the same flag.
+const commentsFeature = "comments"
+
+type RealtimeHandler struct {
+	hub       *services.RealtimeHub
+	features  *services.FeatureService
+	upgrader  websocket.Upgrader
+	heartbeat time.Duration
+	logger    *pkg.CustomLogger
+}
+
+func NewRealtimeHandler(hub *services.RealtimeHub, features *services.FeatureService, heartbeat time.Duration, logger *pkg.CustomLogger) *RealtimeHandler {
+	return &RealtimeHandler{
+		hub:       hub,
+		features:  features,
+		heartbeat: heartbeat,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			Subprotocols:    []string{"bearer"},
+		},
+		logger: logger,
+	}
+}
+
+type realtimeCommand struct {
+	Action   string `json:"action"`
+	SoundIDs []int  `json:"sound_ids"`
+}
+
+// Connect opens a live update stream
+// @Summary Live updates over WebSocket
+// @Description Upgrade to a WebSocket. Send {"action":"subscribe","sound_ids":[1,2]} (or "unsubscribe") to choose sounds; reaction count changes and new comments on them are pushed as JSON events. Browsers may pass the token as the "bearer, <token>" subprotocol
+// @Tags realtime
+// @Security BearerAuth
+// @Success 101 "Switching protocols"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /api/ws [get]
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.WarnContext(ctx, "websocket upgrade failed", err)
+		return
+	}
+
+	client := h.hub.Connect(userID)
+	defer h.hub.Disconnect(client)
+
+	subject := feature.Subject{UserID: userID, Role: c.GetString("role")}
+	go h.writePump(conn, client, subject)
+	h.readPump(conn, client)
+}
+
+// StreamSoundEvents streams live updates of one sound
+// @Summary Live sound updates over Server-Sent Events
+// @Description Stream reaction count changes, new comments (while the comments feature is on for everyone) and processing status changes of a sound. Reconnecting clients resume from the Last-Event-ID header (or last_event_id query) using a short retained history
+// @Tags realtime
+// @Produce text/event-stream
+// @Param id path int true "Sound ID"
+// @Param Last-Event-ID header string false "ID of the last received event"
+// @Param last_event_id query string false "Same as the Last-Event-ID header"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} map[string]string "Invalid sound ID or Last-Event-ID"
+// @Router /api/sounds/{id}/events [get]
+func (h *RealtimeHandler) StreamSoundEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	soundID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid sound id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sound ID"})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	// Subscribe before reading the history so nothing published in between is lost;
+	// duplicates are skipped by ID below.
+	client := h.hub.Connect(0)
+	defer h.hub.Disconnect(client)
+
+	if err := h.hub.Subscribe(client, []int{soundID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to subscribe"})
+		return
+	}
+
+	backlog, err := h.hub.Replay(ctx, soundID, lastEventID)
+	if err != nil {
+		if errors.Is(err, services.InvalidLastEventID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load event history"})
+		return
+	}
+
+	// The stream outlives the server write timeout.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.WarnContext(ctx, "failed to clear write deadline", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// The stream is public, so it is evaluated for an anonymous subject.
+	subject := feature.Subject{}
+	last, _ := realtime.ParseEventID(lastEventID)
+	for _, event := range backlog {
+		if id, err := realtime.ParseEventID(event.ID); err == nil {
+			last = id
+		}
+		if h.visible(event.Type, subject) {
+			h.writeSSE(c, event)
+		}
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-client.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case msg := <-client.Messages():
+			var event realtime.Event
+			if err := json.Unmarshal(msg, &event); err != nil {
+				h.logger.WarnContext(ctx, "invalid event message", err)
+				continue
+			}
+
+			if id, err := realtime.ParseEventID(event.ID); err == nil {
+				if !id.After(last) {
+					continue
+				}
+				last = id
+			}
+
+			if !h.visible(event.Type, subject) {
+				continue
+			}
+
+			h.writeSSE(c, &event)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// visible hides comment events from subjects the comments flag is off for, just as the
+// comment routes answer 404 for them.
+func (h *RealtimeHandler) visible(eventType string, subject feature.Subject) bool {
+	if eventType != realtime.EventComment {
+		return true
+	}
+	return h.features.Enabled(commentsFeature, subject)
+}
+
+func (h *RealtimeHandler) writeSSE(c *gin.Context, event *realtime.Event) {
+	c.Render(-1, sse.Event{Id: event.ID, Event: event.Type, Data: event})
+}
+
+func (h *RealtimeHandler) readPump(conn *websocket.Conn, client *services.RealtimeClient) {
+	conn.SetReadLimit(wsMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var cmd realtimeCommand
+		if err := conn.ReadJSON(&cmd); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				h.reply(client, gin.H{"error": "invalid message"})
+				continue
+			}
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				h.logger.Warn("websocket read failed", err)
+			}
+			return
+		}
+
+		switch cmd.Action {
+		case "subscribe":
+			if err := h.hub.Subscribe(client, cmd.SoundIDs); err != nil {
+				h.reply(client, gin.H{"error": err.Error()})
+				continue
+			}
+		case "unsubscribe":
+			h.hub.Unsubscribe(client, cmd.SoundIDs)
+		default:
+			h.reply(client, gin.H{"error": "unknown action"})
+			continue
+		}
+
+		h.reply(client, gin.H{"type": cmd.Action + "d", "sound_ids": cmd.SoundIDs})
+	}
+}
+
+// writePump is the only writer of conn. It stops when the hub closes the client,
+// which also happens when the client is too slow to drain its queue.
+func (h *RealtimeHandler) writePump(conn *websocket.Conn, client *services.RealtimeClient, subject feature.Subject) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-client.Messages():
+			var event struct {
+				Type string `json:"type"`
+			}
+			if json.Unmarshal(msg, &event) == nil && !h.visible(event.Type, subject) {
+				continue
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-client.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(wsWriteWait))
+			return
+		}
+	}
+}
+
+func (h *RealtimeHandler) reply(client *services.RealtimeClient, msg gin.H) {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		h.logger.Error("failed to encode websocket reply", err)
+		return
+	}
+
+	client.Deliver(raw)
+}
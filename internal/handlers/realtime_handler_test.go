@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"log/slog"
+	"soundtube/internal/domain/feature"
+	"soundtube/internal/domain/realtime"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"testing"
+)
+
+func TestRealtimeHandlerVisible(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      config.FeatureFlag
+		eventType string
+		subject   feature.Subject
+		want      bool
+	}{
+		{name: "comments for everyone", flag: config.FeatureFlag{Enabled: true, Percentage: 100}, eventType: realtime.EventComment, want: true},
+		{name: "comments switched off", flag: config.FeatureFlag{Percentage: 100}, eventType: realtime.EventComment, want: false},
+		{name: "partial rollout hides comments from anonymous streams", flag: config.FeatureFlag{Enabled: true, Percentage: 0, Users: []int{7}},
+			eventType: realtime.EventComment, want: false},
+		{name: "partial rollout shows comments to listed users", flag: config.FeatureFlag{Enabled: true, Percentage: 0, Users: []int{7}},
+			eventType: realtime.EventComment, subject: feature.Subject{UserID: 7}, want: true},
+		{name: "other events ignore the flag", flag: config.FeatureFlag{Percentage: 100}, eventType: realtime.EventReactions, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := pkg.NewLogger(slog.New(slog.DiscardHandler), false)
+			cfg := &config.Features{Flags: map[string]config.FeatureFlag{commentsFeature: tt.flag}}
+			features, err := services.NewFeatureService(nil, nil, cfg, logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			h := NewRealtimeHandler(nil, features, 0, logger)
+			if got := h.visible(tt.eventType, tt.subject); got != tt.want {
+				t.Fatalf("visible(%q) = %v, want %v", tt.eventType, got, tt.want)
+			}
+		})
+	}
+}
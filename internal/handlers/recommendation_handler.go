@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/domain/sound"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	service *services.RecommendationService
+	logger  *pkg.CustomLogger
+}
+
+func NewRecommendationHandler(service *services.RecommendationService, logger *pkg.CustomLogger) *RecommendationHandler {
+	return &RecommendationHandler{service: service, logger: logger}
+}
+
+// GetSimilar returns sounds similar to the given one
+// @Summary Get similar sounds
+// @Description Get sounds that the same listeners liked or played, falling back to popular sounds of the same genre
+// @Tags recommendations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Sound ID"
+// @Param limit query int false "Number of sounds (max 100)"
+// @Success 200 {array} sound.SoundDTO "Similar sounds"
+// @Failure 400 {object} map[string]string "Invalid sound ID"
+// @Failure 404 {object} map[string]string "Sound not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/sounds/{id}/similar [get]
+func (h *RecommendationHandler) GetSimilar(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "RecommendationHandler.GetSimilar")
+	defer span.End()
+
+	soundID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid sound id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sound ID"})
+		return
+	}
+
+	limit, _, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sounds, err := h.service.GetSimilar(ctx, soundID, limit)
+	if errors.Is(err, services.SoundNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get similar sounds error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get similar sounds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sound.SoundsToDTO(sounds))
+}
+
+// GetRecommendations returns personal recommendations
+// @Summary Get recommendations
+// @Description Get sounds picked from the current user's likes and listening history, falling back to popular sounds of favorite genres
+// @Tags recommendations
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Number of sounds (max 100)"
+// @Success 200 {array} sound.SoundDTO "Recommended sounds"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/me/recommendations [get]
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "RecommendationHandler.GetRecommendations")
+	defer span.End()
+
+	userID, ok := currentUserID(ctx, c, h.logger)
+	if !ok {
+		return
+	}
+
+	limit, _, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sounds, err := h.service.GetRecommendations(ctx, userID, limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get recommendations error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recommendations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sound.SoundsToDTO(sounds))
+}
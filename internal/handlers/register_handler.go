@@ -39,12 +39,12 @@ func (h *RegisterHandler) Register(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn(JsonInputFormat, err).WithTrace(ctx)
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	h.logger.Info(req.Username, req.Password, req.Email)
+	h.logger.InfoContext(ctx, "registration requested", "username", req.Username)
 
 	span.SetAttributes(
 		attribute.String("user.username", req.Username),
@@ -54,7 +54,7 @@ func (h *RegisterHandler) Register(c *gin.Context) {
 
 	err := h.service.Register(ctx, req.Username, req.Email, req.Password)
 	if err != nil {
-		h.logger.Error("register service error", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "register service error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSuggestLimit = 8
+	maxSuggestLimit     = 20
+)
+
+type SearchHandler struct {
+	service *services.SearchService
+	logger  *pkg.CustomLogger
+}
+
+func NewSearchHandler(service *services.SearchService, logger *pkg.CustomLogger) *SearchHandler {
+	return &SearchHandler{service: service, logger: logger}
+}
+
+// Search performs full-text search over sounds, artists and albums
+// @Summary Search
+// @Description Ranked full-text search with typo tolerance, highlighted matches and per-type facets
+// @Tags search
+// @Produce json
+// @Param q query string true "Search query"
+// @Param type query string false "Comma separated result types: sound, artist, album"
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} search.ResultsDTO "Search results"
+// @Failure 400 {object} map[string]string "Invalid query"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "SearchHandler.Search")
+	defer span.End()
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid pagination params", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var types []string
+	if raw := c.Query("type"); raw != "" {
+		types = strings.Split(raw, ",")
+	}
+
+	results, err := h.service.Search(ctx, c.Query("q"), types, limit, offset)
+	if errors.Is(err, services.InvalidSearchQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "search error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// Suggest returns autocomplete suggestions for a prefix
+// @Summary Autocomplete
+// @Description Get sound, artist and album titles starting with the typed words
+// @Tags search
+// @Produce json
+// @Param q query string true "Typed prefix"
+// @Param limit query int false "Number of suggestions (max 20)"
+// @Success 200 {array} search.Suggestion "Suggestions"
+// @Failure 400 {object} map[string]string "Invalid limit"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "SearchHandler.Suggest")
+	defer span.End()
+
+	limit := defaultSuggestLimit
+	if raw := c.Query("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = min(value, maxSuggestLimit)
+	}
+
+	suggestions, err := h.service.Suggest(ctx, c.Query("q"), limit)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "suggest error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get suggestions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
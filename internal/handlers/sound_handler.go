@@ -3,10 +3,12 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"net/url"
 	"soundtube/internal/domain/sound"
 	"soundtube/internal/services"
 	"soundtube/pkg"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
@@ -21,29 +23,108 @@ func NewSoundHandler(service *services.SoundService, logger *pkg.CustomLogger) *
 	return &SoundHandler{service: service, logger: logger}
 }
 
-// GetSounds retrieves all sounds
-// @Summary Get all sounds
-// @Description Get a list of all available sounds
+// GetSounds retrieves a page of sounds
+// @Summary Get sounds
+// @Description Get sounds page by page with optional filters and sorting. The next page is requested with the returned cursor, also sent in the Link header
 // @Tags sounds
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {array} sound.SoundDTO "List of sounds"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (max 100)"
+// @Param sort query string false "Sort order" Enums(newest, most_liked, most_played)
+// @Param genre query string false "Genre"
+// @Param author query string false "Author username"
+// @Param uploaded_from query string false "Uploaded on or after date (YYYY-MM-DD)"
+// @Param uploaded_to query string false "Uploaded on or before date (YYYY-MM-DD)"
+// @Param min_duration query int false "Minimum duration in seconds"
+// @Param max_duration query int false "Maximum duration in seconds"
+// @Param format query string false "File format, e.g. mp3"
+// @Success 200 {object} object "Page of sounds with next_cursor"
+// @Failure 400 {object} map[string]string "Invalid filters or cursor"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/sounds [get]
 func (h *SoundHandler) GetSounds(c *gin.Context) {
 	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "SoundHandler.GetSounds")
 	defer span.End()
-	sounds, err := h.service.GetSounds(ctx)
+
+	query, err := parseSoundQuery(c)
 	if err != nil {
-		h.logger.Error("get sound error", err)
-		c.JSON(http.StatusInternalServerError, err)
+		h.logger.WarnContext(ctx, "invalid sound query", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.FindSounds(ctx, query, c.Query("author"))
+	if errors.Is(err, services.InvalidSoundQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get sound error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sounds"})
 		return
 	}
 
-	soundDTOs := sound.SoundsToDTO(sounds)
+	var next *string
+	if page.Next != nil {
+		cursor := page.Next.Encode()
+		next = &cursor
 
-	h.logger.Info("Getted " + strconv.Itoa(len(soundDTOs)) + " from storage").WithTrace(ctx)
-	c.JSON(http.StatusOK, soundDTOs)
+		params := c.Request.URL.Query()
+		params.Set("cursor", cursor)
+		nextURL := url.URL{Path: c.Request.URL.Path, RawQuery: params.Encode()}
+		c.Header("Link", "<"+nextURL.String()+`>; rel="next"`)
+	}
+
+	h.logger.InfoContext(ctx, "sounds loaded", "count", len(page.Sounds))
+	c.JSON(http.StatusOK, gin.H{
+		"sounds":      sound.SoundsToDTO(page.Sounds),
+		"next_cursor": next,
+	})
+}
+
+func parseSoundQuery(c *gin.Context) (*sound.Query, error) {
+	limit, _, err := parsePagination(c)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &sound.Query{
+		Genre:  c.Query("genre"),
+		Format: c.Query("format"),
+		Sort:   c.Query("sort"),
+		Limit:  limit,
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		if query.After, err = sound.DecodeCursor(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	if raw := c.Query("uploaded_from"); raw != "" {
+		if query.UploadedFrom, err = time.Parse(time.DateOnly, raw); err != nil {
+			return nil, errors.New("uploaded_from must be in YYYY-MM-DD format")
+		}
+	}
+
+	if raw := c.Query("uploaded_to"); raw != "" {
+		day, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, errors.New("uploaded_to must be in YYYY-MM-DD format")
+		}
+		query.UploadedTo = day.AddDate(0, 0, 1)
+	}
+
+	for param, target := range map[string]*int{"min_duration": &query.MinDuration, "max_duration": &query.MaxDuration} {
+		if raw := c.Query(param); raw != "" {
+			if *target, err = strconv.Atoi(raw); err != nil {
+				return nil, errors.New(param + " must be a number")
+			}
+		}
+	}
+
+	return query, nil
 }
 
 // CreateSound creates a new sound record
@@ -63,34 +144,30 @@ func (h *SoundHandler) CreateSound(c *gin.Context) {
 	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "SoundHandler.CreateSound")
 	defer span.End()
 
-	h.logger.Info("Context keys:").WithTrace(ctx)
-	for _, key := range c.Keys {
-		val, _ := c.Get(key)
-		h.logger.Info("Key: %s, Value: %v, Type: %T", key, val, val).WithTrace(ctx)
-	}
-
 	userIDValid, exists := c.Get("user_id")
 	if !exists {
-		h.logger.Error("user_id not found in context", errors.New("user not found")).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "user_id not found in context", errors.New("user not found"))
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
 		return
 	}
 
 	userID, ok := userIDValid.(int)
 	if !ok {
-		h.logger.Error("user_id is not integer", errors.New("invalid type")).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "user_id is not integer", errors.New("invalid type"))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID type"})
 		return
 	}
 
 	var req struct {
-		Name  string `json:"name"`
-		Album string `json:"album"`
-		Genre string `json:"genre"`
+		Name        string `json:"name"`
+		Album       string `json:"album"`
+		AlbumID     int    `json:"album_id"`
+		TrackNumber int    `json:"track_number"`
+		Genre       string `json:"genre"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn(JsonInputFormat, err).WithTrace(ctx)
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
@@ -99,11 +176,17 @@ func (h *SoundHandler) CreateSound(c *gin.Context) {
 		attribute.String("sound.name", req.Name),
 		attribute.String("sound.album", req.Album),
 		attribute.String("sound.genre", req.Genre),
+		attribute.Int("sound.album_id", req.AlbumID),
 	)
 
-	err := h.service.CreateSound(ctx, req.Name, req.Album, req.Genre, userID)
+	err := h.service.CreateSound(ctx, req.Name, req.Genre, req.AlbumID, req.Album, req.TrackNumber, userID)
+	if errors.Is(err, services.AlbumNotFound) {
+		h.logger.WarnContext(ctx, "album not found", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
-		h.logger.Error("get sound error", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "get sound error", err)
 		c.JSON(http.StatusInternalServerError, err)
 		return
 	}
@@ -152,14 +235,14 @@ func (h *SoundHandler) DeleteSound(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn(JsonInputFormat, err).WithTrace(ctx)
+		h.logger.WarnContext(ctx, JsonInputFormat, err)
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
 
 	err := h.service.DeleteSound(ctx, req.Name)
 	if err != nil {
-		h.logger.Error("get sound error", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "get sound error", err)
 		c.JSON(http.StatusInternalServerError, err)
 		return
 	}
@@ -7,17 +7,21 @@ import (
 	"path/filepath"
 	"soundtube/internal/services"
 	"soundtube/pkg"
+	"soundtube/pkg/metrics"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UploadHandler struct {
-	service *services.SoundService
-	logger  *pkg.CustomLogger
+	service   *services.SoundService
+	metrics   *metrics.Metrics
+	staticDir string
+	logger    *pkg.CustomLogger
 }
 
-func NewUploadHandler(service *services.SoundService, logger *pkg.CustomLogger) *UploadHandler {
-	return &UploadHandler{service: service, logger: logger}
+func NewUploadHandler(service *services.SoundService, metrics *metrics.Metrics, staticDir string, logger *pkg.CustomLogger) *UploadHandler {
+	return &UploadHandler{service: service, metrics: metrics, staticDir: staticDir, logger: logger}
 }
 
 // UploadSoundFile handles audio file upload
@@ -37,30 +41,26 @@ func (h *UploadHandler) UploadSoundFile(c *gin.Context) {
 	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "UploadHandler.UploadSoundFile")
 	defer span.End()
 
-	wd, _ := os.Getwd()
-	projectRoot := filepath.Join(wd, "..", "..")
-	h.logger.Info("Project root directory: " + projectRoot).WithTrace(ctx)
+	start := time.Now()
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		h.logger.Error("failed to get file from form", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "failed to get file from form", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
 		return
 	}
 
 	name := c.PostForm("name")
 	if name == "" {
-		h.logger.Error("sound name is required", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "sound name is required", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "sound name is required"})
 		return
 	}
 
-	uploadDir := "static/uploads"
-	fullUploadDir := filepath.Join(projectRoot, uploadDir)
-	h.logger.Info("Full upload directory: " + fullUploadDir).WithTrace(ctx)
+	fullUploadDir := filepath.Join(h.staticDir, "uploads")
 
 	if err := ensureUploadDir(fullUploadDir); err != nil {
-		h.logger.Error("failed to create upload directory", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "failed to create upload directory", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload directory"})
 		return
 	}
@@ -72,35 +72,36 @@ func (h *UploadHandler) UploadSoundFile(c *gin.Context) {
 
 	dbFilePath := filepath.Join("uploads", fileName)
 
-	h.logger.Info("Saving file to: " + savePath).WithTrace(ctx)
-	h.logger.Info("DB file path: " + dbFilePath).WithTrace(ctx)
+	h.logger.DebugContext(ctx, "saving uploaded file", "save_path", savePath, "db_path", dbFilePath)
 
 	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		h.logger.Error("failed to save file", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "failed to save file", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save file"})
 		return
 	}
 
 	if _, err := os.Stat(savePath); os.IsNotExist(err) {
-		h.logger.Error("file was not created at: "+savePath, err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "uploaded file was not created", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "file was not created"})
 		return
 	}
 
 	fileInfo, _ := os.Stat(savePath)
-	h.logger.Info("File successfully saved. Size: %d bytes", fileInfo.Size()).WithTrace(ctx)
+	h.logger.DebugContext(ctx, "uploaded file saved", "size", fileInfo.Size())
 
 	if err := h.service.UpdateSoundFile(ctx, name, fileName, dbFilePath, file.Size); err != nil {
-		h.logger.Error("failed to update sound file info", err).WithTrace(ctx)
+		h.logger.ErrorContext(ctx, "failed to update sound file info", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update sound record"})
 		return
 	}
 
-	h.logger.Info("file uploaded successfully",
+	h.metrics.ObserveUpload(file.Size, time.Since(start).Seconds())
+
+	h.logger.InfoContext(ctx, "file uploaded successfully",
 		"filename", fileName,
 		"size", file.Size,
 		"db_path", dbFilePath,
-		"save_path", savePath).WithTrace(ctx)
+		"save_path", savePath)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":   "file uploaded successfully",
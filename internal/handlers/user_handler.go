@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"soundtube/internal/domain/sound"
+	"soundtube/internal/services"
+	"soundtube/pkg"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type UserHandler struct {
+	service *services.ProfileService
+	logger  *pkg.CustomLogger
+}
+
+func NewUserHandler(service *services.ProfileService, logger *pkg.CustomLogger) *UserHandler {
+	return &UserHandler{service: service, logger: logger}
+}
+
+// GetProfile returns public profile of a user
+// @Summary Get user profile
+// @Description Get public profile info with upload, follower and reaction totals
+// @Tags users
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {object} auth.ProfileDTO "User profile"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/users/{username} [get]
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "UserHandler.GetProfile")
+	defer span.End()
+
+	profile, err := h.service.GetProfile(ctx, c.Param("username"))
+	if errors.Is(err, services.UserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get profile error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile.ToDTO())
+}
+
+// GetUserSounds returns sounds uploaded by a user
+// @Summary Get user sounds
+// @Description Get a page of sounds uploaded by a user, newest first
+// @Tags users
+// @Produce json
+// @Param username path string true "Username"
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} object "Page of sounds"
+// @Failure 400 {object} map[string]string "Invalid pagination params"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/users/{username}/sounds [get]
+func (h *UserHandler) GetUserSounds(c *gin.Context) {
+	ctx, span := h.logger.GetTracer().Start(c.Request.Context(), "UserHandler.GetUserSounds")
+	defer span.End()
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid pagination params", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sounds, total, err := h.service.GetUserSounds(ctx, c.Param("username"), limit, offset)
+	if errors.Is(err, services.UserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get user sounds error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sounds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sounds": sound.SoundsToDTO(sounds),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func parsePagination(c *gin.Context) (int, int, error) {
+	limit, offset := defaultPageLimit, 0
+
+	if raw := c.Query("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			return 0, 0, errors.New("limit must be a positive number")
+		}
+		limit = min(value, maxPageLimit)
+	}
+
+	if raw := c.Query("offset"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return 0, 0, errors.New("offset must be a non-negative number")
+		}
+		offset = value
+	}
+
+	return limit, offset, nil
+}
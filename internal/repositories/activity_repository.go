@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/feed"
+	"soundtube/pkg"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ActivityRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/feed/001_create_activity_table_up.sql
+var createActivityTable string
+
+const selectActivities = `SELECT a.id, a.actor_id, COALESCE(u.user_name, ''), a.activity_type, a.sound_id, a.created_at
+	FROM activities a
+	LEFT JOIN users u ON u.id = a.actor_id`
+
+func NewActivityRepository(db *sql.DB, logger *pkg.CustomLogger) (*ActivityRepository, error) {
+	repository := ActivityRepository{db: db, logger: logger}
+
+	_, err := db.Exec(createActivityTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+func (r *ActivityRepository) Create(ctx context.Context, activity *feed.Activity) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "ActivityRepository.Create")
+	defer span.End()
+
+	var id int
+	err := r.db.QueryRowContext(ctx, `INSERT INTO activities (actor_id, activity_type, sound_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+		RETURNING id`,
+		activity.ActorID(), activity.Type(), activity.SoundID(), activity.CreatedAt()).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *ActivityRepository) DeleteRepost(ctx context.Context, actorID, soundID int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "ActivityRepository.DeleteRepost")
+	defer span.End()
+
+	query := "DELETE FROM activities WHERE actor_id = $1 AND sound_id = $2 AND activity_type = 'repost'"
+	_, err := r.db.ExecContext(ctx, query, actorID, soundID)
+	return err
+}
+
+func (r *ActivityRepository) GetByIDs(ctx context.Context, ids []int) ([]*feed.Activity, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "ActivityRepository.GetByIDs")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return []*feed.Activity{}, nil
+	}
+
+	return r.queryActivities(ctx, selectActivities+` WHERE a.id = ANY($1)`, pq.Array(ids))
+}
+
+func (r *ActivityRepository) GetByActors(ctx context.Context, actorIDs []int, before time.Time, limit int) ([]*feed.Activity, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "ActivityRepository.GetByActors")
+	defer span.End()
+
+	if len(actorIDs) == 0 {
+		return []*feed.Activity{}, nil
+	}
+
+	return r.queryActivities(ctx, selectActivities+` WHERE a.actor_id = ANY($1) AND a.created_at < $2
+		ORDER BY a.created_at DESC LIMIT $3`, pq.Array(actorIDs), before, limit)
+}
+
+func (r *ActivityRepository) queryActivities(ctx context.Context, query string, args ...any) ([]*feed.Activity, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := []*feed.Activity{}
+	for rows.Next() {
+		var id, actorID, soundID int
+		var actorName, activityType string
+		var createdAt time.Time
+		if err := rows.Scan(&id, &actorID, &actorName, &activityType, &soundID, &createdAt); err != nil {
+			return nil, err
+		}
+		activities = append(activities, feed.RebuildActivityFromStorage(id, actorID, actorName, activityType, soundID, createdAt))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/album"
+	"soundtube/pkg"
+)
+
+type AlbumRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/album/001_create_album_table_up.sql
+var createAlbumTable string
+
+//go:embed migrations/album/002_migrate_sound_album_up.sql
+var migrateSoundAlbum string
+
+const selectAlbums = `SELECT a.id, a.artist_id, COALESCE(u.user_name, ''), a.title,
+		COALESCE(TO_CHAR(a.release_date, 'YYYY-MM-DD'), ''), COALESCE(a.cover_path, ''), a.created_at
+	FROM albums a
+	LEFT JOIN users u ON u.id = a.artist_id`
+
+func NewAlbumRepository(db *sql.DB, logger *pkg.CustomLogger) (*AlbumRepository, error) {
+	repository := AlbumRepository{db: db, logger: logger}
+
+	if _, err := db.Exec(createAlbumTable); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(migrateSoundAlbum); err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+func scanAlbum(row rowScanner) (*album.Album, error) {
+	var id, artistID int
+	var artistName, title, releaseDate, coverPath, createdAt string
+
+	if err := row.Scan(&id, &artistID, &artistName, &title, &releaseDate, &coverPath, &createdAt); err != nil {
+		return nil, err
+	}
+
+	return album.RebuildAlbumFromStorage(id, artistID, artistName, title, releaseDate, coverPath, createdAt), nil
+}
+
+func (r *AlbumRepository) GetAlbumByID(ctx context.Context, id int) (*album.Album, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "AlbumRepository.GetAlbumByID")
+	defer span.End()
+
+	a, err := scanAlbum(r.db.QueryRowContext(ctx, selectAlbums+` WHERE a.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (r *AlbumRepository) GetAlbumByTitle(ctx context.Context, artistID int, title string) (*album.Album, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "AlbumRepository.GetAlbumByTitle")
+	defer span.End()
+
+	a, err := scanAlbum(r.db.QueryRowContext(ctx, selectAlbums+` WHERE a.artist_id = $1 AND LOWER(a.title) = LOWER($2)`, artistID, title))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (r *AlbumRepository) GetAlbumsByArtist(ctx context.Context, artistID int) ([]*album.Album, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "AlbumRepository.GetAlbumsByArtist")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, selectAlbums+` WHERE a.artist_id = $1 ORDER BY a.release_date DESC NULLS LAST, a.id DESC`, artistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	albums := []*album.Album{}
+	for rows.Next() {
+		a, err := scanAlbum(rows)
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return albums, nil
+}
+
+func (r *AlbumRepository) CreateAlbum(ctx context.Context, a *album.Album) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "AlbumRepository.CreateAlbum")
+	defer span.End()
+
+	var id int
+	err := r.db.QueryRowContext(ctx, `INSERT INTO albums (artist_id, title, release_date)
+		VALUES ($1, $2, NULLIF($3, '')::DATE)
+		RETURNING id`,
+		a.ArtistID(), a.Title(), a.ReleaseDate()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *AlbumRepository) UpdateAlbum(ctx context.Context, id int, a *album.Album) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "AlbumRepository.UpdateAlbum")
+	defer span.End()
+
+	query := `UPDATE albums SET title = $1, release_date = NULLIF($2, '')::DATE WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, a.Title(), a.ReleaseDate(), id)
+	return err
+}
+
+func (r *AlbumRepository) UpdateAlbumCover(ctx context.Context, id int, coverPath string) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "AlbumRepository.UpdateAlbumCover")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE albums SET cover_path = $1 WHERE id = $2", coverPath, id)
+	return err
+}
+
+func (r *AlbumRepository) DeleteAlbum(ctx context.Context, id int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "AlbumRepository.DeleteAlbum")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM albums WHERE id = $1", id)
+	return err
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/chart"
+	"soundtube/pkg"
+	"time"
+)
+
+type ChartRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/chart/001_create_chart_indexes_up.sql
+var createChartIndexes string
+
+func NewChartRepository(db *sql.DB, logger *pkg.CustomLogger) (*ChartRepository, error) {
+	repository := ChartRepository{db: db, logger: logger}
+
+	_, err := db.Exec(createChartIndexes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+// GetSoundStats returns counters for sounds that were uploaded, played or reacted to within the window.
+func (r *ChartRepository) GetSoundStats(ctx context.Context, window time.Duration) ([]*chart.SoundStats, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "ChartRepository.GetSoundStats")
+	defer span.End()
+
+	query := `WITH recent_plays AS (
+			SELECT sound_id, COUNT(*) AS plays
+			FROM plays
+			WHERE played_at >= NOW() - make_interval(secs => $1)
+			GROUP BY sound_id
+		)
+		SELECT s.id, LOWER(COALESCE(s.sound_genre, '')), COALESCE(r.total_likes, 0), COALESCE(r.total_dislikes, 0),
+			COALESCE(p.plays, 0), s.upload_date
+		FROM sounds s
+		LEFT JOIN sound_reactions r ON r.sound_id = s.id
+		LEFT JOIN recent_plays p ON p.sound_id = s.id
+		WHERE s.upload_date >= NOW() - make_interval(secs => $1)
+			OR p.plays IS NOT NULL
+			OR EXISTS (
+				SELECT 1 FROM sound_participants sp
+				WHERE sp.sound_id = s.id AND sp.created_at >= NOW() - make_interval(secs => $1)
+			)`
+
+	rows, err := r.db.QueryContext(ctx, query, window.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*chart.SoundStats
+	for rows.Next() {
+		var s chart.SoundStats
+		if err := rows.Scan(&s.SoundID, &s.Genre, &s.Likes, &s.Dislikes, &s.WindowPlays, &s.UploadedAt); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &s)
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/comment"
+	"soundtube/pkg"
+	"time"
+)
+
+type CommentRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/comment/001_create_comment_table_up.sql
+var createCommentTable string
+
+const selectComments = `SELECT c.id, c.sound_id, c.author_id, COALESCE(u.user_name, ''),
+		COALESCE(c.parent_id, 0), c.content, c.created_at, c.updated_at
+	FROM comments c
+	LEFT JOIN users u ON u.id = c.author_id`
+
+func NewCommentRepository(db *sql.DB, logger *pkg.CustomLogger) (*CommentRepository, error) {
+	repository := CommentRepository{db: db, logger: logger}
+
+	if _, err := db.Exec(createCommentTable); err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+func scanComment(row rowScanner) (*comment.Comment, error) {
+	var id, soundID, authorID, parentID int
+	var authorName, content string
+	var createdAt, updatedAt time.Time
+
+	if err := row.Scan(&id, &soundID, &authorID, &authorName, &parentID, &content, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	return comment.RebuildCommentFromStorage(id, soundID, authorID, authorName, parentID, content, createdAt, updatedAt), nil
+}
+
+func scanComments(rows *sql.Rows) ([]*comment.Comment, error) {
+	defer rows.Close()
+
+	comments := []*comment.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *CommentRepository) GetCommentByID(ctx context.Context, id int) (*comment.Comment, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "CommentRepository.GetCommentByID")
+	defer span.End()
+
+	c, err := scanComment(r.db.QueryRowContext(ctx, selectComments+` WHERE c.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *CommentRepository) GetCommentsBySound(ctx context.Context, soundID, limit, offset int) ([]*comment.Comment, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "CommentRepository.GetCommentsBySound")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, selectComments+` WHERE c.sound_id = $1
+		ORDER BY c.created_at, c.id
+		LIMIT $2 OFFSET $3`, soundID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanComments(rows)
+}
+
+func (r *CommentRepository) GetCommentsByAuthor(ctx context.Context, authorID int) ([]*comment.Comment, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "CommentRepository.GetCommentsByAuthor")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, selectComments+` WHERE c.author_id = $1 ORDER BY c.created_at, c.id`, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanComments(rows)
+}
+
+func (r *CommentRepository) CreateComment(ctx context.Context, c *comment.Comment) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "CommentRepository.CreateComment")
+	defer span.End()
+
+	var id int
+	err := r.db.QueryRowContext(ctx, `INSERT INTO comments (sound_id, author_id, parent_id, content, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6)
+		RETURNING id`,
+		c.SoundID(), c.AuthorID(), c.ParentID(), c.Content(), c.CreatedAt(), c.UpdatedAt()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *CommentRepository) UpdateComment(ctx context.Context, c *comment.Comment) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "CommentRepository.UpdateComment")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE comments SET content = $1, updated_at = $2 WHERE id = $3", c.Content(), c.UpdatedAt(), c.ID())
+	return err
+}
+
+func (r *CommentRepository) DeleteComment(ctx context.Context, id int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "CommentRepository.DeleteComment")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM comments WHERE id = $1", id)
+	return err
+}
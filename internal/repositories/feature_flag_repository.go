@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/feature"
+	"soundtube/pkg"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type FeatureFlagRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/feature/001_create_feature_flag_table_up.sql
+var createFeatureFlagTable string
+
+func NewFeatureFlagRepository(db *sql.DB, logger *pkg.CustomLogger) (*FeatureFlagRepository, error) {
+	repository := FeatureFlagRepository{db: db, logger: logger}
+
+	_, err := db.Exec(createFeatureFlagTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+func (r *FeatureFlagRepository) GetFlags(ctx context.Context) ([]*feature.Flag, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FeatureFlagRepository.GetFlags")
+	defer span.End()
+
+	query := `SELECT name, description, enabled, percentage, user_ids, roles, updated_at
+		FROM feature_flags ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flags []*feature.Flag
+	for rows.Next() {
+		var name, description string
+		var enabled bool
+		var percentage int
+		var userIDs pq.Int64Array
+		var roles pq.StringArray
+		var updatedAt time.Time
+
+		if err := rows.Scan(&name, &description, &enabled, &percentage, &userIDs, &roles, &updatedAt); err != nil {
+			return nil, err
+		}
+
+		users := make([]int, len(userIDs))
+		for i, id := range userIDs {
+			users[i] = int(id)
+		}
+
+		flags = append(flags, feature.RebuildFlagFromStorage(name, description, enabled, percentage, users, roles, updatedAt))
+	}
+
+	return flags, rows.Err()
+}
+
+func (r *FeatureFlagRepository) SaveFlag(ctx context.Context, flag *feature.Flag) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FeatureFlagRepository.SaveFlag")
+	defer span.End()
+
+	query := `INSERT INTO feature_flags (name, description, enabled, percentage, user_ids, roles, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description, enabled = EXCLUDED.enabled,
+			percentage = EXCLUDED.percentage, user_ids = EXCLUDED.user_ids, roles = EXCLUDED.roles, updated_at = EXCLUDED.updated_at`
+	_, err := r.db.ExecContext(ctx, query, flag.Name(), flag.Description(), flag.Enabled(), flag.Percentage(),
+		pq.Array(flag.Users()), pq.Array(flag.Roles()), flag.UpdatedAt())
+	return err
+}
+
+func (r *FeatureFlagRepository) DeleteFlag(ctx context.Context, name string) (bool, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FeatureFlagRepository.DeleteFlag")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM feature_flags WHERE name = $1`, name)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"soundtube/internal/domain/mail"
+	"soundtube/pkg"
+	"soundtube/pkg/health"
+	"time"
+)
+
+// FileMailer drops every message as an .eml file into a directory instead of sending
+// it, for development.
+type FileMailer struct {
+	dir    string
+	from   string
+	logger *pkg.CustomLogger
+}
+
+func NewFileMailer(dir, from string, logger *pkg.CustomLogger) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{dir: dir, from: from, logger: logger}, nil
+}
+
+// HealthCheck checks that messages can still be written to the drop directory.
+func (m *FileMailer) HealthCheck(ctx context.Context) error {
+	return health.WritableDir(m.dir)(ctx)
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg *mail.Message) error {
+	_, span := m.logger.GetTracer().Start(ctx, "FileMailer.Send")
+	defer span.End()
+
+	name := fmt.Sprintf("%s-%d.eml", time.Now().UTC().Format("20060102T150405.000000000"), msg.ID())
+
+	file, err := os.Create(filepath.Join(m.dir, name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := composeMail(m.from, msg).WriteTo(file); err != nil {
+		return err
+	}
+
+	m.logger.Info("mail written to file", "to", msg.To(), "file", name)
+	return file.Close()
+}
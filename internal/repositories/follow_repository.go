@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/follow"
+	"soundtube/pkg"
+)
+
+type FollowRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/follow/001_create_follow_table_up.sql
+var createFollowTable string
+
+func NewFollowRepository(db *sql.DB, logger *pkg.CustomLogger) (*FollowRepository, error) {
+	repository := FollowRepository{db: db, logger: logger}
+
+	_, err := db.Exec(createFollowTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+func (r *FollowRepository) Follow(ctx context.Context, f *follow.Follow) (bool, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FollowRepository.Follow")
+	defer span.End()
+
+	query := `INSERT INTO follows (follower_id, followee_id)
+		VALUES ($1, $2)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING`
+	result, err := r.db.ExecContext(ctx, query, f.FollowerID(), f.FolloweeID())
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return inserted > 0, nil
+}
+
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FollowRepository.Unfollow")
+	defer span.End()
+
+	query := "DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2"
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	return err
+}
+
+func (r *FollowRepository) GetFollowers(ctx context.Context, userID, limit, offset int) ([]*follow.UserSummary, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FollowRepository.GetFollowers")
+	defer span.End()
+
+	query := `SELECT u.id, u.user_name
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	return r.queryUsers(ctx, query, userID, limit, offset)
+}
+
+func (r *FollowRepository) GetFollowing(ctx context.Context, userID, limit, offset int) ([]*follow.UserSummary, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FollowRepository.GetFollowing")
+	defer span.End()
+
+	query := `SELECT u.id, u.user_name
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	return r.queryUsers(ctx, query, userID, limit, offset)
+}
+
+func (r *FollowRepository) GetFollowerIDs(ctx context.Context, userID int) ([]int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FollowRepository.GetFollowerIDs")
+	defer span.End()
+
+	return r.queryIDs(ctx, "SELECT follower_id FROM follows WHERE followee_id = $1", userID)
+}
+
+func (r *FollowRepository) CountFollowers(ctx context.Context, userID int) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FollowRepository.CountFollowers")
+	defer span.End()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM follows WHERE followee_id = $1", userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetLargeFollowingIDs returns accounts followed by the user that have more followers than threshold.
+// Their activities are not fanned out on write and have to be merged into the feed on read.
+func (r *FollowRepository) GetLargeFollowingIDs(ctx context.Context, userID, threshold int) ([]int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "FollowRepository.GetLargeFollowingIDs")
+	defer span.End()
+
+	query := `SELECT f.followee_id
+		FROM follows f
+		WHERE f.follower_id = $1
+		AND (SELECT COUNT(*) FROM follows c WHERE c.followee_id = f.followee_id) > $2`
+
+	return r.queryIDs(ctx, query, userID, threshold)
+}
+
+func (r *FollowRepository) queryUsers(ctx context.Context, query string, args ...any) ([]*follow.UserSummary, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*follow.UserSummary{}
+	for rows.Next() {
+		var user follow.UserSummary
+		if err := rows.Scan(&user.ID, &user.Username); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *FollowRepository) queryIDs(ctx context.Context, query string, args ...any) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"soundtube/internal/domain/mail"
+	"soundtube/pkg"
+	"time"
+)
+
+type MailOutboxRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/mail/001_create_outbox_table_up.sql
+var createMailOutboxTable string
+
+func NewMailOutboxRepository(db *sql.DB, logger *pkg.CustomLogger) (*MailOutboxRepository, error) {
+	repository := MailOutboxRepository{db: db, logger: logger}
+
+	if _, err := db.Exec(createMailOutboxTable); err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// enqueueMail writes msg to the outbox through db, which may be a transaction of the
+// change the message belongs to.
+func enqueueMail(ctx context.Context, db execer, msg *mail.Message) error {
+	headers, err := json.Marshal(msg.Headers())
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `INSERT INTO mail_outbox (recipient, subject, html_body, text_body, headers)
+		VALUES ($1, $2, $3, $4, $5)`,
+		msg.To(), msg.Subject(), msg.HTML(), msg.Text(), headers)
+	return err
+}
+
+func (r *MailOutboxRepository) Enqueue(ctx context.Context, msg *mail.Message) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "MailOutboxRepository.Enqueue")
+	defer span.End()
+
+	return enqueueMail(ctx, r.db, msg)
+}
+
+func (r *MailOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*mail.Message, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "MailOutboxRepository.ClaimDue")
+	defer span.End()
+
+	query := `UPDATE mail_outbox SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM mail_outbox
+			WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, recipient, subject, html_body, text_body, headers, attempts`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*mail.Message
+	for rows.Next() {
+		var id, attempts int
+		var to, subject, html, text string
+		var rawHeaders []byte
+
+		if err := rows.Scan(&id, &to, &subject, &html, &text, &rawHeaders, &attempts); err != nil {
+			return nil, err
+		}
+
+		var headers map[string][]string
+		if err := json.Unmarshal(rawHeaders, &headers); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, mail.RebuildMessageFromStorage(id, to, subject, html, text, headers, attempts))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *MailOutboxRepository) MarkSent(ctx context.Context, id int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "MailOutboxRepository.MarkSent")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE mail_outbox SET status = 'sent', sent_at = CURRENT_TIMESTAMP, attempts = attempts + 1
+		WHERE id = $1`, id)
+	return err
+}
+
+func (r *MailOutboxRepository) MarkFailed(ctx context.Context, id int, reason string, retryIn time.Duration) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "MailOutboxRepository.MarkFailed")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE mail_outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3)
+		WHERE id = $1`, id, reason, retryIn.Seconds())
+	return err
+}
+
+func (r *MailOutboxRepository) MarkDead(ctx context.Context, id int, reason string) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "MailOutboxRepository.MarkDead")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE mail_outbox SET status = 'dead', attempts = attempts + 1, last_error = $2
+		WHERE id = $1`, id, reason)
+	return err
+}
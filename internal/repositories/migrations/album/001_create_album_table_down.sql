@@ -0,0 +1,3 @@
+ALTER TABLE sounds DROP COLUMN IF EXISTS track_number;
+ALTER TABLE sounds DROP COLUMN IF EXISTS album_id;
+DROP TABLE IF EXISTS albums;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS albums(
+    id SERIAL PRIMARY KEY,
+    artist_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    release_date DATE,
+    cover_path VARCHAR(500),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_albums_artist_title ON albums(artist_id, LOWER(title));
+
+ALTER TABLE sounds ADD COLUMN IF NOT EXISTS album_id INTEGER REFERENCES albums(id) ON DELETE SET NULL;
+ALTER TABLE sounds ADD COLUMN IF NOT EXISTS track_number INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_sounds_album_id ON sounds(album_id, track_number);
@@ -0,0 +1,3 @@
+ALTER TABLE sounds ADD COLUMN IF NOT EXISTS sound_album VARCHAR(255);
+
+UPDATE sounds s SET sound_album = a.title FROM albums a WHERE s.album_id = a.id;
@@ -0,0 +1,37 @@
+-- Runs once: the legacy sounds.sound_album column is dropped after the
+-- backfill, so later startups skip the block entirely.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'sounds' AND column_name = 'sound_album'
+    ) THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO albums (artist_id, title)
+    SELECT DISTINCT ON (author_id, LOWER(TRIM(sound_album))) author_id, TRIM(sound_album)
+    FROM sounds
+    WHERE album_id IS NULL AND author_id IS NOT NULL AND TRIM(COALESCE(sound_album, '')) <> ''
+    ORDER BY author_id, LOWER(TRIM(sound_album)), id
+    ON CONFLICT DO NOTHING;
+
+    UPDATE sounds s
+    SET album_id = a.id
+    FROM albums a
+    WHERE s.album_id IS NULL
+        AND a.artist_id = s.author_id
+        AND LOWER(a.title) = LOWER(TRIM(s.sound_album));
+
+    UPDATE sounds s
+    SET track_number = numbered.position
+    FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY album_id ORDER BY upload_date, id) AS position
+        FROM sounds
+        WHERE album_id IS NOT NULL
+    ) numbered
+    WHERE s.id = numbered.id AND s.track_number IS NULL;
+
+    DROP TRIGGER IF EXISTS trg_sounds_search_vector ON sounds;
+    ALTER TABLE sounds DROP COLUMN sound_album;
+END $$;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sound_participants_created_at;
+DROP INDEX IF EXISTS idx_plays_played_at;
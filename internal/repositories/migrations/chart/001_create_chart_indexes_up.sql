@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_plays_played_at ON plays(played_at);
+CREATE INDEX IF NOT EXISTS idx_sound_participants_created_at ON sound_participants(created_at);
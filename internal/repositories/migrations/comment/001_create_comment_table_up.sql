@@ -1,10 +1,13 @@
-CREATE TABLE IN NO EXISTS comments(
+CREATE TABLE IF NOT EXISTS comments(
     id SERIAL PRIMARY KEY,
-    author_id INTEGER NOT NULL,
+    sound_id INTEGER NOT NULL REFERENCES sounds(id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
     content TEXT NOT NULL,
-    is_response BOOLEAN DEFAULT FALSE,
-    response_target INTEGER DEFAULT -1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE INDEX IF NOT EXISTS idx_comments_id ON comments(id);
-CREATE INDEX IF NOT EXISTS idx_comments_author_id ON comments(author_id); 
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_comments_sound_created ON comments(sound_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_author_id ON comments(author_id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments(parent_id);
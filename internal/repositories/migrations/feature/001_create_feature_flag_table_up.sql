@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS feature_flags(
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    percentage SMALLINT NOT NULL DEFAULT 0 CHECK (percentage BETWEEN 0 AND 100),
+    user_ids INTEGER[] NOT NULL DEFAULT '{}',
+    roles TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS activities(
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    activity_type VARCHAR(20) NOT NULL CHECK (activity_type IN ('sound', 'repost', 'comment')),
+    sound_id INTEGER REFERENCES sounds(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_activities_actor_created ON activities(actor_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_activities_repost ON activities(actor_id, sound_id) WHERE activity_type = 'repost';
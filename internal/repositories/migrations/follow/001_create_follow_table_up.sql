@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS follows(
+    follower_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    followee_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_follows_followee_id ON follows(followee_id);
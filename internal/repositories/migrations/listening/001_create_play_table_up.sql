@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS plays(
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    sound_id INTEGER REFERENCES sounds(id) ON DELETE CASCADE,
+    session_id VARCHAR(64) NOT NULL,
+    listened_seconds INTEGER NOT NULL DEFAULT 0,
+    played_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, session_id, sound_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_plays_user_played_at ON plays(user_id, played_at DESC);
+CREATE INDEX IF NOT EXISTS idx_plays_sound_id ON plays(sound_id);
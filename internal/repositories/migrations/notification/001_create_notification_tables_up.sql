@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS notifications(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notification_type VARCHAR(20) NOT NULL,
+    group_key VARCHAR(64),
+    last_actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    actor_count INTEGER NOT NULL DEFAULT 0,
+    sound_id INTEGER REFERENCES sounds(id) ON DELETE CASCADE,
+    comment_id INTEGER,
+    detail TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One open (unread) notification per group; events for it are merged into that row.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_open_group ON notifications(user_id, group_key) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_user_updated ON notifications(user_id, updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS notification_actors(
+    notification_id INTEGER REFERENCES notifications(id) ON DELETE CASCADE,
+    actor_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (notification_id, actor_id)
+);
+
+CREATE TABLE IF NOT EXISTS notification_preferences(
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    notification_type VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, notification_type)
+);
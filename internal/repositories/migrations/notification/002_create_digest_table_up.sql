@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS email_digests(
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(10),
+    locale VARCHAR(8),
+    last_sent_at TIMESTAMP
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_email_digests_next_digest_at;
+
+ALTER TABLE email_digests DROP COLUMN IF EXISTS failures;
+ALTER TABLE email_digests DROP COLUMN IF EXISTS next_digest_at;
@@ -0,0 +1,4 @@
+ALTER TABLE email_digests ADD COLUMN IF NOT EXISTS next_digest_at TIMESTAMP;
+ALTER TABLE email_digests ADD COLUMN IF NOT EXISTS failures INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_email_digests_next_digest_at ON email_digests(next_digest_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS playlist_tracks;
+DROP TABLE IF EXISTS playlist_collaborators;
+DROP TABLE IF EXISTS playlists;
\ No newline at end of file
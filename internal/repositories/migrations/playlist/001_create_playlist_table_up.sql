@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS playlists(
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT DEFAULT '',
+    visibility VARCHAR(10) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'unlisted', 'private')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS playlist_collaborators(
+    playlist_id INTEGER REFERENCES playlists(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (playlist_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS playlist_tracks(
+    id SERIAL PRIMARY KEY,
+    playlist_id INTEGER REFERENCES playlists(id) ON DELETE CASCADE,
+    sound_id INTEGER REFERENCES sounds(id) ON DELETE CASCADE,
+    rank VARCHAR(255) COLLATE "C" NOT NULL,
+    added_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (playlist_id, rank)
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlists_owner_id ON playlists(owner_id);
+CREATE INDEX IF NOT EXISTS idx_playlist_collaborators_user_id ON playlist_collaborators(user_id);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_sound_reactions_like_count ON sound_reactions;
+DROP FUNCTION IF EXISTS sound_reactions_sync_like_count();
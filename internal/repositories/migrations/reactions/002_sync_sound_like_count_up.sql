@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION sound_reactions_sync_like_count() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE sounds SET like_count = COALESCE(NEW.total_likes, 0)
+    WHERE id = NEW.sound_id AND like_count <> COALESCE(NEW.total_likes, 0);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_sound_reactions_like_count ON sound_reactions;
+CREATE TRIGGER trg_sound_reactions_like_count
+    AFTER INSERT OR UPDATE OF total_likes ON sound_reactions
+    FOR EACH ROW EXECUTE FUNCTION sound_reactions_sync_like_count();
+
+UPDATE sounds s SET like_count = COALESCE(r.total_likes, 0)
+FROM sound_reactions r
+WHERE r.sound_id = s.id AND s.like_count <> COALESCE(r.total_likes, 0);
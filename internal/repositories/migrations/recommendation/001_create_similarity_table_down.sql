@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_plays_user_sound;
+DROP TABLE IF EXISTS sound_similarities;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS sound_similarities(
+    sound_id INTEGER REFERENCES sounds(id) ON DELETE CASCADE,
+    similar_sound_id INTEGER REFERENCES sounds(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (sound_id, similar_sound_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sound_similarities_score ON sound_similarities(sound_id, score DESC);
+CREATE INDEX IF NOT EXISTS idx_plays_user_sound ON plays(user_id, sound_id);
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS idx_albums_title_trgm;
+DROP INDEX IF EXISTS idx_users_name_trgm;
+DROP INDEX IF EXISTS idx_sounds_name_trgm;
+
+DROP INDEX IF EXISTS idx_albums_search_vector;
+DROP INDEX IF EXISTS idx_users_search_vector;
+DROP INDEX IF EXISTS idx_sounds_search_vector;
+
+DROP TRIGGER IF EXISTS trg_users_search_refresh ON users;
+DROP FUNCTION IF EXISTS users_search_refresh_sounds();
+
+DROP TRIGGER IF EXISTS trg_albums_search_refresh ON albums;
+DROP FUNCTION IF EXISTS albums_search_refresh_sounds();
+
+DROP TRIGGER IF EXISTS trg_sounds_search_vector ON sounds;
+DROP FUNCTION IF EXISTS sounds_search_vector_update();
+
+ALTER TABLE albums DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE sounds DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,65 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE sounds ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (setweight(to_tsvector('simple', COALESCE(user_name, '')), 'A')) STORED;
+
+ALTER TABLE albums ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (setweight(to_tsvector('simple', COALESCE(title, '')), 'A')) STORED;
+
+CREATE OR REPLACE FUNCTION sounds_search_vector_update() RETURNS TRIGGER AS $$
+DECLARE
+    album_title TEXT;
+    author_name TEXT;
+BEGIN
+    SELECT title INTO album_title FROM albums WHERE id = NEW.album_id;
+    SELECT user_name INTO author_name FROM users WHERE id = NEW.author_id;
+
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', COALESCE(NEW.sound_name, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(album_title, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(author_name, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.sound_genre, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_sounds_search_vector ON sounds;
+CREATE TRIGGER trg_sounds_search_vector
+    BEFORE INSERT OR UPDATE OF sound_name, sound_genre, album_id, author_id ON sounds
+    FOR EACH ROW EXECUTE FUNCTION sounds_search_vector_update();
+
+CREATE OR REPLACE FUNCTION albums_search_refresh_sounds() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE sounds SET album_id = album_id WHERE album_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_albums_search_refresh ON albums;
+CREATE TRIGGER trg_albums_search_refresh
+    AFTER UPDATE OF title ON albums
+    FOR EACH ROW EXECUTE FUNCTION albums_search_refresh_sounds();
+
+CREATE OR REPLACE FUNCTION users_search_refresh_sounds() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE sounds SET author_id = author_id WHERE author_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_users_search_refresh ON users;
+CREATE TRIGGER trg_users_search_refresh
+    AFTER UPDATE OF user_name ON users
+    FOR EACH ROW EXECUTE FUNCTION users_search_refresh_sounds();
+
+UPDATE sounds SET sound_name = sound_name WHERE search_vector IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sounds_search_vector ON sounds USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_albums_search_vector ON albums USING GIN(search_vector);
+
+CREATE INDEX IF NOT EXISTS idx_sounds_name_trgm ON sounds USING GIN(sound_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING GIN(user_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_albums_title_trgm ON albums USING GIN(title gin_trgm_ops);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS login_sessions;
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_sounds_file_format;
+DROP INDEX IF EXISTS idx_sounds_most_played;
+DROP INDEX IF EXISTS idx_sounds_most_liked;
+DROP INDEX IF EXISTS idx_sounds_newest;
+
+ALTER TABLE sounds DROP COLUMN IF EXISTS play_count;
+ALTER TABLE sounds DROP COLUMN IF EXISTS like_count;
@@ -0,0 +1,10 @@
+ALTER TABLE sounds ADD COLUMN IF NOT EXISTS like_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sounds ADD COLUMN IF NOT EXISTS play_count BIGINT NOT NULL DEFAULT 0;
+
+UPDATE sounds SET file_format = LOWER(SUBSTRING(file_name FROM '\.([^.]+)$'))
+WHERE COALESCE(file_format, '') = '' AND file_name LIKE '%.%';
+
+CREATE INDEX IF NOT EXISTS idx_sounds_newest ON sounds(upload_date DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_sounds_most_liked ON sounds(like_count DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_sounds_most_played ON sounds(play_count DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_sounds_file_format ON sounds(file_format);
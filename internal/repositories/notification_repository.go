@@ -0,0 +1,359 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/notification"
+	"soundtube/pkg"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type NotificationRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/notification/001_create_notification_tables_up.sql
+var createNotificationTables string
+
+//go:embed migrations/notification/002_create_digest_table_up.sql
+var createDigestTable string
+
+//go:embed migrations/notification/003_add_digest_schedule_up.sql
+var addDigestSchedule string
+
+func NewNotificationRepository(db *sql.DB, logger *pkg.CustomLogger) (*NotificationRepository, error) {
+	repository := NotificationRepository{db: db, logger: logger}
+
+	if _, err := db.Exec(createNotificationTables); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(createDigestTable); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(addDigestSchedule); err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+// CreateNotification merges n into the recipient's open notification of the same group,
+// or opens a new one. An actor is only counted once per notification.
+func (r *NotificationRepository) CreateNotification(ctx context.Context, n *notification.Notification) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.CreateNotification")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `INSERT INTO notifications (user_id, notification_type, group_key, last_actor_id, sound_id, comment_id, detail)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), NULLIF($5, 0), NULLIF($6, 0), $7)
+		ON CONFLICT (user_id, group_key) WHERE read_at IS NULL
+		DO UPDATE SET group_key = EXCLUDED.group_key
+		RETURNING id`,
+		n.RecipientID(), n.Type(), n.GroupKey(), n.ActorID(), n.SoundID(), n.CommentID(), n.Detail()).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	if n.ActorID() != 0 {
+		result, err := tx.ExecContext(ctx, `INSERT INTO notification_actors (notification_id, actor_id)
+			VALUES ($1, $2) ON CONFLICT DO NOTHING`, id, n.ActorID())
+		if err != nil {
+			return err
+		}
+
+		added, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if added > 0 {
+			_, err = tx.ExecContext(ctx, `UPDATE notifications
+				SET actor_count = actor_count + 1, last_actor_id = $2, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1`, id, n.ActorID())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *NotificationRepository) GetNotifications(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]*notification.Notification, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.GetNotifications")
+	defer span.End()
+
+	query := `SELECT n.id, n.user_id, COALESCE(n.last_actor_id, 0), COALESCE(u.user_name, ''), n.actor_count, n.notification_type,
+			COALESCE(n.sound_id, 0), COALESCE(s.sound_name, ''), COALESCE(n.comment_id, 0), n.detail, n.read_at IS NOT NULL, n.updated_at
+		FROM notifications n
+		LEFT JOIN users u ON u.id = n.last_actor_id
+		LEFT JOIN sounds s ON s.id = n.sound_id
+		WHERE n.user_id = $1 AND (NOT $2 OR n.read_at IS NULL)
+		ORDER BY n.updated_at DESC, n.id DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*notification.Notification{}
+	for rows.Next() {
+		var id, recipientID, actorID, actorCount, soundID, commentID int
+		var actorName, notificationType, soundName, detail string
+		var read bool
+		var updatedAt time.Time
+
+		if err := rows.Scan(&id, &recipientID, &actorID, &actorName, &actorCount, &notificationType,
+			&soundID, &soundName, &commentID, &detail, &read, &updatedAt); err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification.RebuildNotificationFromStorage(id, recipientID, actorID, actorName, actorCount,
+			notificationType, soundID, soundName, commentID, detail, read, updatedAt))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.CountUnread")
+	defer span.End()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL", userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id int) (bool, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.MarkRead")
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.MarkAllRead")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL", userID)
+	return err
+}
+
+// GetPreferences returns only the types the user has changed; the rest are enabled.
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID int) (map[string]bool, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.GetPreferences")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT notification_type, enabled FROM notification_preferences WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	preferences := make(map[string]bool)
+	for rows.Next() {
+		var notificationType string
+		var enabled bool
+		if err := rows.Scan(&notificationType, &enabled); err != nil {
+			return nil, err
+		}
+		preferences[notificationType] = enabled
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (r *NotificationRepository) SetPreferences(ctx context.Context, userID int, preferences map[string]bool) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.SetPreferences")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for notificationType, enabled := range preferences {
+		_, err := tx.ExecContext(ctx, `INSERT INTO notification_preferences (user_id, notification_type, enabled)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, notification_type) DO UPDATE SET enabled = EXCLUDED.enabled`,
+			userID, notificationType, enabled)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *NotificationRepository) IsNotificationEnabled(ctx context.Context, userID int, notificationType string) (bool, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.IsNotificationEnabled")
+	defer span.End()
+
+	var enabled bool
+	err := r.db.QueryRowContext(ctx, `SELECT enabled FROM notification_preferences WHERE user_id = $1 AND notification_type = $2`,
+		userID, notificationType).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return enabled, nil
+}
+
+func (r *NotificationRepository) GetDigestSettings(ctx context.Context, userID int) (*notification.DigestSettings, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.GetDigestSettings")
+	defer span.End()
+
+	var frequency, locale string
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(frequency, ''), COALESCE(locale, '') FROM email_digests WHERE user_id = $1`,
+		userID).Scan(&frequency, &locale)
+	if err == sql.ErrNoRows {
+		return notification.RebuildDigestSettingsFromStorage(userID, "", ""), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return notification.RebuildDigestSettingsFromStorage(userID, frequency, locale), nil
+}
+
+func (r *NotificationRepository) SaveDigestSettings(ctx context.Context, settings *notification.DigestSettings) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.SaveDigestSettings")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO email_digests (user_id, frequency, locale)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''))
+		ON CONFLICT (user_id) DO UPDATE SET frequency = EXCLUDED.frequency, locale = EXCLUDED.locale`,
+		settings.UserID(), settings.Frequency(), settings.Locale())
+	return err
+}
+
+// ClaimDueDigests locks the due users with SKIP LOCKED and pushes their next_digest_at
+// past the lease in the same transaction, so concurrent instances never claim the same
+// digest. A digest without next_digest_at is due one period after the last one was sent.
+func (r *NotificationRepository) ClaimDueDigests(ctx context.Context, defaultFrequency string, limit int, lease time.Duration) ([]*notification.DigestRecipient, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.ClaimDueDigests")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT u.id, u.user_email, u.user_name, COALESCE(e.frequency, $1), COALESCE(e.locale, ''),
+			COALESCE(e.last_sent_at, u.created_at), COALESCE(e.failures, 0)
+		FROM users u
+		LEFT JOIN email_digests e ON e.user_id = u.id
+		WHERE u.is_verified AND NOT u.is_banned
+			AND COALESCE(e.frequency, $1) <> 'off'
+			AND COALESCE(e.next_digest_at, COALESCE(e.last_sent_at, u.created_at) +
+				CASE COALESCE(e.frequency, $1) WHEN 'daily' THEN INTERVAL '1 day' ELSE INTERVAL '7 days' END) <= CURRENT_TIMESTAMP
+			AND EXISTS (SELECT 1 FROM notifications n WHERE n.user_id = u.id AND n.read_at IS NULL
+				AND n.updated_at > COALESCE(e.last_sent_at, u.created_at))
+		ORDER BY u.id
+		LIMIT $2
+		FOR UPDATE OF u SKIP LOCKED`
+
+	rows, err := tx.QueryContext(ctx, query, defaultFrequency, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []*notification.DigestRecipient
+	var ids []int
+	for rows.Next() {
+		var recipient notification.DigestRecipient
+		if err := rows.Scan(&recipient.UserID, &recipient.Email, &recipient.Username, &recipient.Frequency, &recipient.Locale,
+			&recipient.Since, &recipient.Failures); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, &recipient)
+		ids = append(ids, recipient.UserID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO email_digests (user_id, next_digest_at)
+		SELECT id, CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond' FROM UNNEST($1::INTEGER[]) AS id
+		ON CONFLICT (user_id) DO UPDATE SET next_digest_at = EXCLUDED.next_digest_at`, pq.Array(ids), lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return recipients, nil
+}
+
+// MarkDigestSent records the digest and clears the schedule, so the next one is due a
+// full period after this one whatever the frequency is by then.
+func (r *NotificationRepository) MarkDigestSent(ctx context.Context, userID int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.MarkDigestSent")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO email_digests (user_id, last_sent_at)
+		VALUES ($1, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE SET last_sent_at = EXCLUDED.last_sent_at, next_digest_at = NULL, failures = 0`, userID)
+	return err
+}
+
+func (r *NotificationRepository) MarkDigestFailed(ctx context.Context, userID int, retryAfter time.Duration) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "NotificationRepository.MarkDigestFailed")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO email_digests (user_id, next_digest_at, failures)
+		VALUES ($1, CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond', 1)
+		ON CONFLICT (user_id) DO UPDATE SET next_digest_at = EXCLUDED.next_digest_at, failures = email_digests.failures + 1`,
+		userID, retryAfter.Milliseconds())
+	return err
+}
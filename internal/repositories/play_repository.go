@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/listening"
+	"soundtube/pkg"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PlayRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/listening/001_create_play_table_up.sql
+var createPlayTable string
+
+const playTimeLayout = "2006-01-02 15:04:05.999999Z07:00"
+
+func NewPlayRepository(db *sql.DB, logger *pkg.CustomLogger) (*PlayRepository, error) {
+	repository := PlayRepository{db: db, logger: logger}
+
+	_, err := db.Exec(createPlayTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+// SavePlays inserts a batch of plays and bumps the play counters of the sounds in the
+// same statement. Plays already stored for the session, or for deleted sounds, are skipped.
+func (r *PlayRepository) SavePlays(ctx context.Context, plays []*listening.Play) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlayRepository.SavePlays")
+	defer span.End()
+
+	if len(plays) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, len(plays))
+	soundIDs := make([]int64, len(plays))
+	sessionIDs := make([]string, len(plays))
+	seconds := make([]int64, len(plays))
+	playedAt := make([]string, len(plays))
+	for i, p := range plays {
+		userIDs[i] = int64(p.UserID)
+		soundIDs[i] = int64(p.SoundID)
+		sessionIDs[i] = p.SessionID
+		seconds[i] = int64(p.Seconds)
+		playedAt[i] = p.PlayedAt.Format(playTimeLayout)
+	}
+
+	query := `WITH inserted AS (
+			INSERT INTO plays (user_id, sound_id, session_id, listened_seconds, played_at)
+			SELECT p.user_id, p.sound_id, p.session_id, p.listened_seconds, p.played_at
+			FROM UNNEST($1::integer[], $2::integer[], $3::text[], $4::integer[], $5::timestamptz[])
+				AS p(user_id, sound_id, session_id, listened_seconds, played_at)
+			WHERE EXISTS (SELECT 1 FROM sounds s WHERE s.id = p.sound_id)
+			ON CONFLICT (user_id, session_id, sound_id) DO NOTHING
+			RETURNING sound_id
+		)
+		UPDATE sounds s SET play_count = s.play_count + c.plays
+		FROM (SELECT sound_id, COUNT(*) AS plays FROM inserted GROUP BY sound_id) c
+		WHERE s.id = c.sound_id`
+
+	_, err := r.db.ExecContext(ctx, query, pq.Array(userIDs), pq.Array(soundIDs), pq.Array(sessionIDs), pq.Array(seconds), pq.Array(playedAt))
+	return err
+}
+
+func (r *PlayRepository) GetHistory(ctx context.Context, userID, limit, offset int) ([]*listening.HistoryEntry, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlayRepository.GetHistory")
+	defer span.End()
+
+	query := soundColumns + `, p.played_at
+		FROM plays p
+		JOIN sounds s ON s.id = p.sound_id
+		LEFT JOIN users u ON u.id = s.author_id
+		LEFT JOIN albums a ON a.id = s.album_id
+		WHERE p.user_id = $1
+		ORDER BY p.played_at DESC, p.id DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*listening.HistoryEntry{}
+	for rows.Next() {
+		var playedAt time.Time
+		s, err := scanSound(keyedRow{row: rows, key: &playedAt})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &listening.HistoryEntry{Sound: s, PlayedAt: playedAt})
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,311 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/playlist"
+	"soundtube/pkg"
+
+	"github.com/lib/pq"
+)
+
+type PlaylistRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/playlist/001_create_playlist_table_up.sql
+var createPlaylistTable string
+
+const selectPlaylists = `SELECT p.id, p.owner_id, COALESCE(u.user_name, ''), p.title, COALESCE(p.description, ''), p.visibility, p.created_at,
+		ARRAY(SELECT c.user_id FROM playlist_collaborators c WHERE c.playlist_id = p.id ORDER BY c.user_id)
+	FROM playlists p
+	LEFT JOIN users u ON u.id = p.owner_id`
+
+func NewPlaylistRepository(db *sql.DB, logger *pkg.CustomLogger) (*PlaylistRepository, error) {
+	repository := PlaylistRepository{db: db, logger: logger}
+
+	_, err := db.Exec(createPlaylistTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+func scanPlaylist(row rowScanner) (*playlist.Playlist, error) {
+	var id, ownerID int
+	var ownerName, title, description, visibility, createdAt string
+	var collaborators pq.Int64Array
+
+	if err := row.Scan(&id, &ownerID, &ownerName, &title, &description, &visibility, &createdAt, &collaborators); err != nil {
+		return nil, err
+	}
+
+	collaboratorIDs := make([]int, len(collaborators))
+	for i, c := range collaborators {
+		collaboratorIDs[i] = int(c)
+	}
+
+	return playlist.RebuildPlaylistFromStorage(id, ownerID, ownerName, title, description, visibility, collaboratorIDs, createdAt), nil
+}
+
+func (r *PlaylistRepository) GetPlaylistByID(ctx context.Context, id int) (*playlist.Playlist, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.GetPlaylistByID")
+	defer span.End()
+
+	p, err := scanPlaylist(r.db.QueryRowContext(ctx, selectPlaylists+` WHERE p.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (r *PlaylistRepository) GetPlaylistsByUser(ctx context.Context, userID int) ([]*playlist.Playlist, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.GetPlaylistsByUser")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, selectPlaylists+` WHERE p.owner_id = $1
+		OR EXISTS (SELECT 1 FROM playlist_collaborators c WHERE c.playlist_id = p.id AND c.user_id = $1)
+		ORDER BY p.created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	playlists := []*playlist.Playlist{}
+	for rows.Next() {
+		p, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+func (r *PlaylistRepository) CreatePlaylist(ctx context.Context, p *playlist.Playlist) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.CreatePlaylist")
+	defer span.End()
+
+	var id int
+	err := r.db.QueryRowContext(ctx, `INSERT INTO playlists (owner_id, title, description, visibility)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		p.OwnerID(), p.Title(), p.Description(), p.Visibility()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *PlaylistRepository) UpdatePlaylist(ctx context.Context, id int, p *playlist.Playlist) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.UpdatePlaylist")
+	defer span.End()
+
+	query := `UPDATE playlists SET title = $1, description = $2, visibility = $3 WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, query, p.Title(), p.Description(), p.Visibility(), id)
+	return err
+}
+
+func (r *PlaylistRepository) DeletePlaylist(ctx context.Context, id int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.DeletePlaylist")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM playlists WHERE id = $1", id)
+	return err
+}
+
+func (r *PlaylistRepository) AddCollaborator(ctx context.Context, playlistID, userID int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.AddCollaborator")
+	defer span.End()
+
+	query := `INSERT INTO playlist_collaborators (playlist_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (playlist_id, user_id) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, playlistID, userID)
+	return err
+}
+
+func (r *PlaylistRepository) RemoveCollaborator(ctx context.Context, playlistID, userID int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.RemoveCollaborator")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM playlist_collaborators WHERE playlist_id = $1 AND user_id = $2", playlistID, userID)
+	return err
+}
+
+func (r *PlaylistRepository) GetTracks(ctx context.Context, playlistID int) ([]*playlist.Track, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.GetTracks")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, sound_id, rank, COALESCE(added_by, 0)
+		FROM playlist_tracks
+		WHERE playlist_id = $1
+		ORDER BY rank`, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tracks := []*playlist.Track{}
+	for rows.Next() {
+		var id, soundID, addedBy int
+		var rank string
+		if err := rows.Scan(&id, &soundID, &rank, &addedBy); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, playlist.RebuildTrackFromStorage(id, playlistID, soundID, rank, addedBy))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tracks, nil
+}
+
+func (r *PlaylistRepository) GetTrack(ctx context.Context, playlistID, trackID int) (*playlist.Track, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.GetTrack")
+	defer span.End()
+
+	var soundID, addedBy int
+	var rank string
+	err := r.db.QueryRowContext(ctx, `SELECT sound_id, rank, COALESCE(added_by, 0)
+		FROM playlist_tracks
+		WHERE playlist_id = $1 AND id = $2`, playlistID, trackID).Scan(&soundID, &rank, &addedBy)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return playlist.RebuildTrackFromStorage(trackID, playlistID, soundID, rank, addedBy), nil
+}
+
+// AppendTrack adds a sound after the last track. The playlist row is locked while the
+// rank is chosen, so concurrent appends queue up instead of picking the same rank.
+func (r *PlaylistRepository) AppendTrack(ctx context.Context, playlistID, soundID, addedBy int) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.AppendTrack")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	if err = tx.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = $1 FOR UPDATE", playlistID).Scan(&locked); err != nil {
+		return 0, err
+	}
+
+	var lastRank string
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(rank), '') FROM playlist_tracks WHERE playlist_id = $1`, playlistID).Scan(&lastRank)
+	if err != nil {
+		return 0, err
+	}
+
+	rank, err := playlist.RankBetween(lastRank, "")
+	if err != nil {
+		return 0, err
+	}
+
+	track, err := playlist.NewTrack(playlistID, soundID, addedBy, rank)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx, `INSERT INTO playlist_tracks (playlist_id, sound_id, rank, added_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		track.PlaylistID(), track.SoundID(), track.Rank(), track.AddedBy()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *PlaylistRepository) RemoveTrack(ctx context.Context, playlistID, trackID int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.RemoveTrack")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM playlist_tracks WHERE playlist_id = $1 AND id = $2", playlistID, trackID)
+	return err
+}
+
+// MoveTrack gives the track a rank right after afterTrackID, or at the top when
+// afterTrackID is 0. Like AppendTrack it holds the playlist row lock while reading the
+// neighbours, so concurrent moves cannot pick the same rank. It reports false when
+// either track is not in the playlist.
+func (r *PlaylistRepository) MoveTrack(ctx context.Context, playlistID, trackID, afterTrackID int) (bool, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "PlaylistRepository.MoveTrack")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	if err = tx.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = $1 FOR UPDATE", playlistID).Scan(&locked); err != nil {
+		return false, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, sound_id, rank, COALESCE(added_by, 0)
+		FROM playlist_tracks
+		WHERE playlist_id = $1
+		ORDER BY rank`, playlistID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	tracks := []*playlist.Track{}
+	for rows.Next() {
+		var id, soundID, addedBy int
+		var rank string
+		if err := rows.Scan(&id, &soundID, &rank, &addedBy); err != nil {
+			return false, err
+		}
+		tracks = append(tracks, playlist.RebuildTrackFromStorage(id, playlistID, soundID, rank, addedBy))
+	}
+
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	rank, found, err := playlist.MoveRank(tracks, trackID, afterTrackID)
+	if err != nil || !found {
+		return false, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE playlist_tracks SET rank = $1 WHERE playlist_id = $2 AND id = $3", rank, playlistID, trackID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
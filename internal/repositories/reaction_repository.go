@@ -24,6 +24,9 @@ type ReactionStatus struct {
 //go:embed migrations/reactions/001_create_sound_reaction_table_up.sql
 var createReactionTable string
 
+//go:embed migrations/reactions/002_sync_sound_like_count_up.sql
+var syncSoundLikeCount string
+
 func NewReactionRepository(db *sql.DB, logger *pkg.CustomLogger) (*SoundReactionRepository, error) {
 	repository := SoundReactionRepository{db: db, logger: logger}
 
@@ -33,6 +36,10 @@ func NewReactionRepository(db *sql.DB, logger *pkg.CustomLogger) (*SoundReaction
 		return nil, err
 	}
 
+	if _, err = db.Exec(syncSoundLikeCount); err != nil {
+		return nil, err
+	}
+
 	return &repository, nil
 }
 
@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"soundtube/internal/domain/recommendation"
+	"soundtube/pkg"
+
+	"github.com/lib/pq"
+)
+
+type RecommendationRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/recommendation/001_create_similarity_table_up.sql
+var createSimilarityTable string
+
+// interactionsQuery builds the weighted user×sound matrix from reactions and plays,
+// optionally narrowed by a condition on user_id.
+func interactionsQuery(filter string) string {
+	return fmt.Sprintf(`SELECT user_id, sound_id, SUM(weight) AS weight FROM (
+			SELECT user_id, sound_id, CASE react_type WHEN 'like' THEN %[1]v ELSE %[2]v END AS weight
+			FROM sound_participants %[4]s
+			UNION ALL
+			SELECT DISTINCT user_id, sound_id, %[3]v
+			FROM plays %[4]s
+		) w
+		GROUP BY user_id, sound_id`,
+		recommendation.LikeWeight, recommendation.DislikeWeight, recommendation.PlayWeight, filter)
+}
+
+func NewRecommendationRepository(db *sql.DB, logger *pkg.CustomLogger) (*RecommendationRepository, error) {
+	repository := RecommendationRepository{db: db, logger: logger}
+
+	_, err := db.Exec(createSimilarityTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+// RebuildSimilarities recomputes shrunk cosine similarity between all sounds sharing
+// at least one listener and keeps the topK neighbors of each sound.
+func (r *RecommendationRepository) RebuildSimilarities(ctx context.Context, topK int) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "RecommendationRepository.RebuildSimilarities")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM sound_similarities"); err != nil {
+		return 0, err
+	}
+
+	query := `WITH interactions AS (` + interactionsQuery("") + `),
+		norms AS (
+			SELECT sound_id, SQRT(SUM(weight * weight)) AS norm
+			FROM interactions
+			GROUP BY sound_id
+		),
+		pairs AS (
+			SELECT a.sound_id, b.sound_id AS similar_sound_id, SUM(a.weight * b.weight) AS dot, COUNT(*) AS support
+			FROM interactions a
+			JOIN interactions b ON b.user_id = a.user_id AND b.sound_id <> a.sound_id
+			GROUP BY a.sound_id, b.sound_id
+		),
+		ranked AS (
+			SELECT p.sound_id, p.similar_sound_id,
+				p.dot / (na.norm * nb.norm) * p.support / (p.support + $2) AS score
+			FROM pairs p
+			JOIN norms na ON na.sound_id = p.sound_id
+			JOIN norms nb ON nb.sound_id = p.similar_sound_id
+			WHERE p.dot > 0 AND na.norm > 0 AND nb.norm > 0
+		)
+		INSERT INTO sound_similarities (sound_id, similar_sound_id, score)
+		SELECT sound_id, similar_sound_id, score FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY sound_id ORDER BY score DESC, similar_sound_id) AS rn
+			FROM ranked
+		) t
+		WHERE rn <= $1`
+
+	result, err := tx.ExecContext(ctx, query, topK, recommendation.Shrinkage)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return int(rows), nil
+}
+
+func (r *RecommendationRepository) GetSimilarIDs(ctx context.Context, soundID, limit int) ([]int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "RecommendationRepository.GetSimilarIDs")
+	defer span.End()
+
+	query := `SELECT similar_sound_id FROM sound_similarities
+		WHERE sound_id = $1
+		ORDER BY score DESC, similar_sound_id
+		LIMIT $2`
+
+	return r.queryIDs(ctx, query, soundID, limit)
+}
+
+// GetRecommendedIDs scores neighbors of everything the user reacted to or played by the
+// user's own weight for the source sound, skipping known sounds and the user's uploads.
+func (r *RecommendationRepository) GetRecommendedIDs(ctx context.Context, userID, limit int) ([]int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "RecommendationRepository.GetRecommendedIDs")
+	defer span.End()
+
+	query := `WITH mine AS (` + interactionsQuery("WHERE user_id = $1") + `)
+		SELECT ss.similar_sound_id
+		FROM mine m
+		JOIN sound_similarities ss ON ss.sound_id = m.sound_id
+		JOIN sounds s ON s.id = ss.similar_sound_id
+		WHERE ss.similar_sound_id NOT IN (SELECT sound_id FROM mine)
+			AND s.author_id IS DISTINCT FROM $1
+		GROUP BY ss.similar_sound_id
+		HAVING SUM(ss.score * m.weight) > 0
+		ORDER BY SUM(ss.score * m.weight) DESC, ss.similar_sound_id
+		LIMIT $2`
+
+	return r.queryIDs(ctx, query, userID, limit)
+}
+
+// GetUserGenres returns the genres the user likes or listens to most, most frequent first.
+func (r *RecommendationRepository) GetUserGenres(ctx context.Context, userID int) ([]string, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "RecommendationRepository.GetUserGenres")
+	defer span.End()
+
+	query := `WITH mine AS (` + interactionsQuery("WHERE user_id = $1") + `)
+		SELECT LOWER(s.sound_genre)
+		FROM mine m
+		JOIN sounds s ON s.id = m.sound_id
+		WHERE m.weight > 0 AND COALESCE(s.sound_genre, '') <> ''
+		GROUP BY LOWER(s.sound_genre)
+		ORDER BY SUM(m.weight) DESC
+		LIMIT 5`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var genres []string
+	for rows.Next() {
+		var genre string
+		if err := rows.Scan(&genre); err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+
+	return genres, rows.Err()
+}
+
+func (r *RecommendationRepository) GetPopularIDs(ctx context.Context, q *recommendation.PopularQuery) ([]int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "RecommendationRepository.GetPopularIDs")
+	defer span.End()
+
+	query := `SELECT s.id FROM sounds s
+		WHERE (COALESCE(CARDINALITY($1::text[]), 0) = 0 OR LOWER(s.sound_genre) = ANY($1::text[]))
+			AND NOT (s.id = ANY(COALESCE($2::integer[], '{}')))
+			AND ($3::integer = 0 OR (
+				s.author_id IS DISTINCT FROM $3
+				AND NOT EXISTS (SELECT 1 FROM sound_participants sp WHERE sp.user_id = $3 AND sp.sound_id = s.id)
+				AND NOT EXISTS (SELECT 1 FROM plays p WHERE p.user_id = $3 AND p.sound_id = s.id)
+			))
+		ORDER BY s.like_count DESC, s.play_count DESC, s.id DESC
+		LIMIT $4`
+
+	return r.queryIDs(ctx, query, pq.Array(q.Genres), pq.Array(q.ExcludeIDs), q.ExcludeUserID, q.Limit)
+}
+
+func (r *RecommendationRepository) queryIDs(ctx context.Context, query string, args ...any) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"soundtube/internal/domain/chart"
+	"soundtube/pkg"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type RedisChartStore struct {
+	logger *pkg.CustomLogger
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisChartStore keeps each chart for ttl, so charts of genres that stopped trending
+// disappear on their own once the job no longer rewrites them.
+func NewRedisChartStore(client *redis.Client, ttl time.Duration, logger *pkg.CustomLogger) *RedisChartStore {
+	return &RedisChartStore{client: client, ttl: ttl, logger: logger}
+}
+
+// Replace swaps the whole chart at once so readers never see a half written ranking.
+func (s *RedisChartStore) Replace(ctx context.Context, window, genre string, entries []chart.Entry) error {
+	_, span := s.logger.GetTracer().Start(ctx, "RedisChartStore.Replace")
+	defer span.End()
+
+	key := formatChartKey(window, genre)
+	if len(entries) == 0 {
+		return tracedRedis(ctx, s.client).Del(key).Err()
+	}
+
+	members := make([]redis.Z, len(entries))
+	for i, e := range entries {
+		members[i] = redis.Z{Score: e.Score, Member: e.SoundID}
+	}
+
+	tmp := key + ":next"
+	pipe := tracedRedis(ctx, s.client).TxPipeline()
+	pipe.Del(tmp)
+	pipe.ZAdd(tmp, members...)
+	pipe.Rename(tmp, key)
+	pipe.Expire(key, s.ttl)
+
+	_, err := pipe.Exec()
+	return err
+}
+
+func (s *RedisChartStore) Top(ctx context.Context, window, genre string, limit, offset int) ([]int, error) {
+	_, span := s.logger.GetTracer().Start(ctx, "RedisChartStore.Top")
+	defer span.End()
+
+	members, err := tracedRedis(ctx, s.client).ZRevRange(formatChartKey(window, genre), int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.Atoi(m)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func formatChartKey(window, genre string) string {
+	return "chart:" + window + ":" + genre
+}
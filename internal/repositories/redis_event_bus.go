@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"soundtube/internal/domain/realtime"
+	"soundtube/pkg"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const soundEventsChannel = "events:sounds"
+
+type RedisEventBus struct {
+	logger *pkg.CustomLogger
+	client *redis.Client
+
+	historyLength int64
+	historyTTL    time.Duration
+
+	mu     sync.Mutex
+	pubsub *redis.PubSub
+}
+
+func NewRedisEventBus(client *redis.Client, historyLength int64, historyTTL time.Duration, logger *pkg.CustomLogger) *RedisEventBus {
+	return &RedisEventBus{client: client, historyLength: historyLength, historyTTL: historyTTL, logger: logger}
+}
+
+func soundHistoryKey(soundID int) string {
+	return fmt.Sprintf("events:sound:%d", soundID)
+}
+
+// publishScript appends an event to a sound's capped stream and broadcasts it in one
+// atomic step, so an event is never in the history without having been published or
+// the other way round. The stream assigns the ID, which is spliced in front of the
+// encoded event (ARGV[3] is an object encoded without an id) before it is published.
+var publishScript = redis.NewScript(`
+local id = redis.call("XADD", KEYS[1], "MAXLEN", "~", ARGV[1], "*", "event", ARGV[3])
+redis.call("PEXPIRE", KEYS[1], ARGV[2])
+redis.call("PUBLISH", ARGV[4], '{"id":"' .. id .. '",' .. string.sub(ARGV[3], 2))
+return id
+`)
+
+// Publish appends the event to the sound's capped stream, which assigns its ID, and
+// broadcasts it to every instance.
+func (b *RedisEventBus) Publish(ctx context.Context, event *realtime.Event) error {
+	_, span := b.logger.GetTracer().Start(ctx, "RedisEventBus.Publish")
+	defer span.End()
+
+	event.ID = ""
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	id, err := publishScript.Run(tracedRedis(ctx, b.client), []string{soundHistoryKey(event.SoundID)},
+		b.historyLength, b.historyTTL.Milliseconds(), payload, soundEventsChannel).String()
+	if err != nil {
+		return err
+	}
+
+	event.ID = id
+	return nil
+}
+
+// Since returns the retained events of a sound published after lastID, oldest first.
+func (b *RedisEventBus) Since(ctx context.Context, soundID int, lastID string) ([]*realtime.Event, error) {
+	_, span := b.logger.GetTracer().Start(ctx, "RedisEventBus.Since")
+	defer span.End()
+
+	messages, err := tracedRedis(ctx, b.client).XRangeN(soundHistoryKey(soundID), lastID, "+", b.historyLength+1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*realtime.Event, 0, len(messages))
+	for _, msg := range messages {
+		if msg.ID == lastID {
+			continue
+		}
+
+		payload, ok := msg.Values["event"].(string)
+		if !ok {
+			continue
+		}
+
+		var event realtime.Event
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			b.logger.Warn("invalid event payload", err)
+			continue
+		}
+		event.ID = msg.ID
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+// Listen passes every event published on the channel to handle until Close is called.
+func (b *RedisEventBus) Listen(handle func(*realtime.Event)) error {
+	pubsub := b.client.Subscribe(soundEventsChannel)
+	if _, err := pubsub.Receive(); err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	b.mu.Lock()
+	b.pubsub = pubsub
+	b.mu.Unlock()
+
+	for msg := range pubsub.Channel() {
+		var event realtime.Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			b.logger.Warn("invalid event payload", err)
+			continue
+		}
+		handle(&event)
+	}
+
+	return nil
+}
+
+func (b *RedisEventBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pubsub == nil {
+		return nil
+	}
+	return b.pubsub.Close()
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"soundtube/internal/domain/feed"
+	"soundtube/pkg"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type RedisFeedStore struct {
+	logger    *pkg.CustomLogger
+	client    *redis.Client
+	maxLength int64
+	ttl       time.Duration
+}
+
+func NewRedisFeedStore(client *redis.Client, maxLength int, ttl time.Duration, logger *pkg.CustomLogger) *RedisFeedStore {
+	return &RedisFeedStore{client: client, maxLength: int64(maxLength), ttl: ttl, logger: logger}
+}
+
+func (s *RedisFeedStore) Push(ctx context.Context, userIDs []int, activityID int, at time.Time) error {
+	_, span := s.logger.GetTracer().Start(ctx, "RedisFeedStore.Push")
+	defer span.End()
+
+	member := redis.Z{Score: float64(at.UnixMilli()), Member: activityID}
+
+	pipe := tracedRedis(ctx, s.client).Pipeline()
+	for _, userID := range userIDs {
+		key := formatFeedKey(userID)
+		pipe.ZAdd(key, member)
+		pipe.ZRemRangeByRank(key, 0, -(s.maxLength + 1))
+		pipe.Expire(key, s.ttl)
+	}
+
+	_, err := pipe.Exec()
+	return err
+}
+
+func (s *RedisFeedStore) Range(ctx context.Context, userID int, before time.Time, limit int) ([]int, error) {
+	_, span := s.logger.GetTracer().Start(ctx, "RedisFeedStore.Range")
+	defer span.End()
+
+	members, err := tracedRedis(ctx, s.client).ZRevRangeByScore(formatFeedKey(userID), redis.ZRangeBy{
+		Max:   "(" + strconv.FormatInt(before.UnixMilli(), 10),
+		Min:   "-inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.Atoi(member)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Add puts existing activities into a user's feed, e.g. when they follow someone.
+func (s *RedisFeedStore) Add(ctx context.Context, userID int, activities []*feed.Activity) error {
+	_, span := s.logger.GetTracer().Start(ctx, "RedisFeedStore.Add")
+	defer span.End()
+
+	if len(activities) == 0 {
+		return nil
+	}
+
+	members := make([]redis.Z, 0, len(activities))
+	for _, activity := range activities {
+		members = append(members, redis.Z{Score: float64(activity.CreatedAt().UnixMilli()), Member: activity.ID()})
+	}
+
+	key := formatFeedKey(userID)
+	pipe := tracedRedis(ctx, s.client).Pipeline()
+	pipe.ZAdd(key, members...)
+	pipe.ZRemRangeByRank(key, 0, -(s.maxLength + 1))
+	pipe.Expire(key, s.ttl)
+
+	_, err := pipe.Exec()
+	return err
+}
+
+func (s *RedisFeedStore) Remove(ctx context.Context, userID int, activityIDs []int) error {
+	_, span := s.logger.GetTracer().Start(ctx, "RedisFeedStore.Remove")
+	defer span.End()
+
+	if len(activityIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, 0, len(activityIDs))
+	for _, id := range activityIDs {
+		members = append(members, id)
+	}
+
+	return tracedRedis(ctx, s.client).ZRem(formatFeedKey(userID), members...).Err()
+}
+
+func formatFeedKey(userID int) string {
+	return "feed:" + strconv.Itoa(userID)
+}
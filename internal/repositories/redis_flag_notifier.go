@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"soundtube/pkg"
+	"sync"
+
+	"github.com/go-redis/redis"
+)
+
+const featureFlagsChannel = "events:feature_flags"
+
+// RedisFlagNotifier broadcasts the names of changed feature flags to every instance.
+type RedisFlagNotifier struct {
+	logger *pkg.CustomLogger
+	client *redis.Client
+
+	mu     sync.Mutex
+	pubsub *redis.PubSub
+}
+
+func NewRedisFlagNotifier(client *redis.Client, logger *pkg.CustomLogger) *RedisFlagNotifier {
+	return &RedisFlagNotifier{client: client, logger: logger}
+}
+
+func (n *RedisFlagNotifier) Publish(ctx context.Context, name string) error {
+	_, span := n.logger.GetTracer().Start(ctx, "RedisFlagNotifier.Publish")
+	defer span.End()
+
+	return tracedRedis(ctx, n.client).Publish(featureFlagsChannel, name).Err()
+}
+
+// Listen passes every published flag name to handle until Close is called.
+func (n *RedisFlagNotifier) Listen(handle func(name string)) error {
+	pubsub := n.client.Subscribe(featureFlagsChannel)
+	if _, err := pubsub.Receive(); err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	n.mu.Lock()
+	n.pubsub = pubsub
+	n.mu.Unlock()
+
+	for msg := range pubsub.Channel() {
+		handle(msg.Payload)
+	}
+
+	return nil
+}
+
+func (n *RedisFlagNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.pubsub == nil {
+		return nil
+	}
+	return n.pubsub.Close()
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"soundtube/pkg"
+	"soundtube/scripts"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// RedisLock is a single-key lease shared by every instance. It is taken with
+// SET NX PX, so it expires on its own if the holder dies.
+type RedisLock struct {
+	logger *pkg.CustomLogger
+	client *redis.Client
+	key    string
+}
+
+func NewRedisLock(client *redis.Client, key string, logger *pkg.CustomLogger) *RedisLock {
+	return &RedisLock{client: client, key: key, logger: logger}
+}
+
+// releaseScript deletes the lock only while it still holds the caller's token, so a
+// lease that expired and was taken by another instance is left alone.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Acquire takes the lock for ttl and returns the token needed to release it early.
+// ok is false when another instance holds the lock.
+func (l *RedisLock) Acquire(ctx context.Context, ttl time.Duration) (string, bool, error) {
+	_, span := l.logger.GetTracer().Start(ctx, "RedisLock.Acquire")
+	defer span.End()
+
+	token := scripts.GenerateUUID()
+	ok, err := tracedRedis(ctx, l.client).SetNX(l.key, token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	return token, true, nil
+}
+
+func (l *RedisLock) Release(ctx context.Context, token string) error {
+	_, span := l.logger.GetTracer().Start(ctx, "RedisLock.Release")
+	defer span.End()
+
+	return releaseScript.Run(tracedRedis(ctx, l.client), []string{l.key}, token).Err()
+}
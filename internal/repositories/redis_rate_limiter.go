@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"soundtube/internal/domain/ratelimit"
+	"soundtube/pkg"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// gcraScript implements the generic cell rate algorithm. The key holds the theoretical
+// arrival time (TAT) in milliseconds; a request is let through while the TAT stays
+// within one window of now. Redis TIME is used so every instance shares one clock.
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+
+local emission = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + emission
+local allow_at = new_tat - window
+if allow_at > now then
+	return {0, 0, math.ceil(allow_at - now), math.ceil(tat - now)}
+end
+
+redis.call("SET", KEYS[1], tostring(new_tat), "PX", math.ceil(new_tat - now))
+return {1, math.floor((window - (new_tat - now)) / emission), 0, math.ceil(new_tat - now)}
+`)
+
+type RedisRateLimiter struct {
+	client *redis.Client
+	logger *pkg.CustomLogger
+}
+
+func NewRedisRateLimiter(client *redis.Client, logger *pkg.CustomLogger) *RedisRateLimiter {
+	return &RedisRateLimiter{client: client, logger: logger}
+}
+
+func (l *RedisRateLimiter) Allow(ctx context.Context, policy ratelimit.Policy, key string) (*ratelimit.Result, error) {
+	ctx, span := l.logger.GetTracer().Start(ctx, "RedisRateLimiter.Allow")
+	defer span.End()
+
+	emission := float64(policy.EmissionInterval()) / float64(time.Millisecond)
+	window := policy.Window().Milliseconds()
+
+	reply, err := gcraScript.Run(tracedRedis(ctx, l.client), []string{formatRateLimitKey(policy, key)}, emission, window).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+
+	ints := make([]int64, len(values))
+	for i, value := range values {
+		if ints[i], ok = value.(int64); !ok {
+			return nil, fmt.Errorf("unexpected rate limit reply %v", reply)
+		}
+	}
+
+	return &ratelimit.Result{
+		Allowed:    ints[0] == 1,
+		Limit:      policy.Limit(),
+		Remaining:  int(ints[1]),
+		RetryAfter: time.Duration(ints[2]) * time.Millisecond,
+		ResetAfter: time.Duration(ints[3]) * time.Millisecond,
+	}, nil
+}
+
+func formatRateLimitKey(policy ratelimit.Policy, key string) string {
+	return fmt.Sprintf("ratelimit:%s:%s", policy.Name(), key)
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"soundtube/pkg"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type RedisSessionTracker struct {
+	logger *pkg.CustomLogger
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedisSessionTracker(client *redis.Client, ttl time.Duration, logger *pkg.CustomLogger) *RedisSessionTracker {
+	return &RedisSessionTracker{client: client, ttl: ttl, logger: logger}
+}
+
+// Start stores the server time of the session's start event. A repeated start resets it.
+func (t *RedisSessionTracker) Start(ctx context.Context, userID int, sessionID string, soundID int, at time.Time) error {
+	_, span := t.logger.GetTracer().Start(ctx, "RedisSessionTracker.Start")
+	defer span.End()
+
+	key := fmt.Sprintf("listen:start:%d:%s:%d", userID, sessionID, soundID)
+	return tracedRedis(ctx, t.client).Set(key, at.UnixMilli(), t.ttl).Err()
+}
+
+func (t *RedisSessionTracker) StartedAt(ctx context.Context, userID int, sessionID string, soundID int) (time.Time, bool, error) {
+	_, span := t.logger.GetTracer().Start(ctx, "RedisSessionTracker.StartedAt")
+	defer span.End()
+
+	key := fmt.Sprintf("listen:start:%d:%s:%d", userID, sessionID, soundID)
+	millis, err := tracedRedis(ctx, t.client).Get(key).Int64()
+	if err == redis.Nil {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return time.UnixMilli(millis), true, nil
+}
+
+// MarkCounted returns true only for the first call per user, session and sound.
+func (t *RedisSessionTracker) MarkCounted(ctx context.Context, userID int, sessionID string, soundID int) (bool, error) {
+	_, span := t.logger.GetTracer().Start(ctx, "RedisSessionTracker.MarkCounted")
+	defer span.End()
+
+	key := fmt.Sprintf("listen:%d:%s:%d", userID, sessionID, soundID)
+	return tracedRedis(ctx, t.client).SetNX(key, 1, t.ttl).Result()
+}
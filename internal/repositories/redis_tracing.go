@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redisTracerName = "soundtube/redis"
+
+// tracedRedis returns a copy of client whose commands are recorded as child spans of ctx.
+// go-redis v6 hooks never see a context, so the wrapping has to happen per call on a
+// context-bound clone; the shared client itself stays untouched.
+func tracedRedis(ctx context.Context, client *redis.Client) *redis.Client {
+	traced := client.WithContext(ctx)
+	tracer := otel.Tracer(redisTracerName)
+
+	traced.WrapProcess(func(next func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			_, span := tracer.Start(ctx, "redis."+cmd.Name(), trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperation(cmd.Name())))
+			defer span.End()
+
+			err := next(cmd)
+			recordRedisError(span, err)
+			return err
+		}
+	})
+
+	traced.WrapProcessPipeline(func(next func(cmds []redis.Cmder) error) func(cmds []redis.Cmder) error {
+		return func(cmds []redis.Cmder) error {
+			names := make([]string, 0, len(cmds))
+			for _, cmd := range cmds {
+				names = append(names, cmd.Name())
+			}
+
+			_, span := tracer.Start(ctx, "redis.pipeline", trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(semconv.DBSystemRedis, attribute.String("db.redis.commands", strings.Join(names, " "))))
+			defer span.End()
+
+			err := next(cmds)
+			recordRedisError(span, err)
+			return err
+		}
+	})
+
+	return traced
+}
+
+func recordRedisError(span trace.Span, err error) {
+	if err == nil || err == redis.Nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redis"
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecordRedisError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "success", err: nil, wantStatus: codes.Unset},
+		{name: "cache miss is not an error", err: redis.Nil, wantStatus: codes.Unset},
+		{name: "connection error", err: errors.New("connection refused"), wantStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder))
+
+			_, span := provider.Tracer("test").Start(context.Background(), "redis.get")
+			recordRedisError(span, tt.err)
+			span.End()
+
+			if got := recorder.Ended()[0].Status().Code; got != tt.wantStatus {
+				t.Fatalf("status = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -6,17 +6,33 @@ import (
 	"fmt"
 	"soundtube/pkg"
 	"soundtube/pkg/config"
+	"soundtube/pkg/metrics"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 )
 
 type RepositoryAdapter struct {
 	db *sql.DB
 	*UserRepository
+	*SessionRepository
 	*SoundRepository
+	*AlbumRepository
+	*CommentRepository
 	*SoundReactionRepository
 	*SoundPartisipantsRepository
+	*FollowRepository
+	*ActivityRepository
+	*PlaylistRepository
+	*SearchRepository
+	*PlayRepository
+	*ChartRepository
+	*RecommendationRepository
+	*NotificationRepository
+	*MailOutboxRepository
+	*FeatureFlagRepository
 }
 
 func NewRepositoryAdapter(dbCfg *config.Database, connCfg *config.DatabaseConnections, logger *pkg.CustomLogger) (*RepositoryAdapter, error) {
@@ -24,16 +40,19 @@ func NewRepositoryAdapter(dbCfg *config.Database, connCfg *config.DatabaseConnec
 	var adapter = RepositoryAdapter{}
 	var err error
 	conn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", dbCfg.Host, dbCfg.Port, dbCfg.User, dbCfg.Password, dbCfg.DBName)
-	adapter.db, err = sql.Open("postgres", conn)
+	adapter.db, err = otelsql.Open("postgres", conn,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
-		logger.Error("repository initialization completed", err).WithTrace(ctx)
+		logger.ErrorContext(ctx, "repository initialization completed", err)
 		return nil, err
 	}
 
 	adapter.db.SetMaxOpenConns(connCfg.MaxOpenConns)
 	adapter.db.SetMaxIdleConns(connCfg.MaxIdleConns)
-	adapter.db.SetConnMaxIdleTime(time.Duration(connCfg.ConnMaxIdleTime) * time.Minute)
-	adapter.db.SetConnMaxLifetime(time.Duration(connCfg.ConnMaxLifetime) * time.Minute)
+	adapter.db.SetConnMaxIdleTime(connCfg.ConnMaxIdleTime)
+	adapter.db.SetConnMaxLifetime(connCfg.ConnMaxLifetime)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
@@ -42,23 +61,88 @@ func NewRepositoryAdapter(dbCfg *config.Database, connCfg *config.DatabaseConnec
 		return nil, err
 	}
 
+	if adapter.MailOutboxRepository, err = NewMailOutboxRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "mail outbox repository failed", err)
+		return nil, err
+	}
+
 	if adapter.UserRepository, err = NewUserRepository(adapter.db, logger); err != nil {
-		logger.Error("user repository failed", err).WithTrace(ctx)
+		logger.ErrorContext(ctx, "user repository failed", err)
+		return nil, err
+	}
+
+	if adapter.SessionRepository, err = NewSessionRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "session repository failed", err)
 		return nil, err
 	}
 
 	if adapter.SoundRepository, err = NewSoundRepository(adapter.db, logger); err != nil {
-		logger.Error("sound repository failed", err).WithTrace(ctx)
+		logger.ErrorContext(ctx, "sound repository failed", err)
+		return nil, err
+	}
+
+	if adapter.AlbumRepository, err = NewAlbumRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "album repository failed", err)
+		return nil, err
+	}
+
+	if adapter.CommentRepository, err = NewCommentRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "comment repository failed", err)
 		return nil, err
 	}
 
 	if adapter.SoundReactionRepository, err = NewReactionRepository(adapter.db, logger); err != nil {
-		logger.Error("reaction repository failed", err).WithTrace(ctx)
+		logger.ErrorContext(ctx, "reaction repository failed", err)
 		return nil, err
 	}
 
 	if adapter.SoundPartisipantsRepository, err = NewSoundPartisipantsRepository(adapter.db, logger); err != nil {
-		logger.Error("reaction repository failed", err).WithTrace(ctx)
+		logger.ErrorContext(ctx, "reaction repository failed", err)
+		return nil, err
+	}
+
+	if adapter.FollowRepository, err = NewFollowRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "follow repository failed", err)
+		return nil, err
+	}
+
+	if adapter.ActivityRepository, err = NewActivityRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "activity repository failed", err)
+		return nil, err
+	}
+
+	if adapter.PlaylistRepository, err = NewPlaylistRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "playlist repository failed", err)
+		return nil, err
+	}
+
+	if adapter.SearchRepository, err = NewSearchRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "search repository failed", err)
+		return nil, err
+	}
+
+	if adapter.PlayRepository, err = NewPlayRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "play repository failed", err)
+		return nil, err
+	}
+
+	if adapter.ChartRepository, err = NewChartRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "chart repository failed", err)
+		return nil, err
+	}
+
+	if adapter.RecommendationRepository, err = NewRecommendationRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "recommendation repository failed", err)
+		return nil, err
+	}
+
+	if adapter.NotificationRepository, err = NewNotificationRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "notification repository failed", err)
+		return nil, err
+	}
+
+	if adapter.FeatureFlagRepository, err = NewFeatureFlagRepository(adapter.db, logger); err != nil {
+		logger.ErrorContext(ctx, "feature flag repository failed", err)
 		return nil, err
 	}
 
@@ -74,6 +158,11 @@ func (r *RepositoryAdapter) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// RegisterMetrics exports the connection pool stats of the shared database handle.
+func (r *RepositoryAdapter) RegisterMetrics(m *metrics.Metrics, dbName string) {
+	m.RegisterDB(r.db, dbName)
+}
+
 func (r *RepositoryAdapter) Close() error {
 	if err := r.db.Close(); err != nil {
 		return err
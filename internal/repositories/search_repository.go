@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/search"
+	"soundtube/pkg"
+
+	"github.com/lib/pq"
+)
+
+type SearchRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/search/001_create_search_index_up.sql
+var createSearchIndex string
+
+const headlineOptions = "StartSel=" + search.HighlightStart + ", StopSel=" + search.HighlightStop + ", HighlightAll=true"
+
+// searchMatches expects the raw query text in $1 and ts_headline options in $2.
+const searchMatches = `SELECT 'sound' AS type, s.id, s.sound_name AS title, COALESCE(u.user_name, '') AS subtitle,
+		ts_headline('simple', s.sound_name || ' · ' || COALESCE(a.title, '') || ' · ' || COALESCE(s.sound_genre, ''),
+			websearch_to_tsquery('simple', $1), $2) AS headline,
+		ts_rank(s.search_vector, websearch_to_tsquery('simple', $1)) + word_similarity($1, s.sound_name) AS rank
+	FROM sounds s
+	LEFT JOIN users u ON u.id = s.author_id
+	LEFT JOIN albums a ON a.id = s.album_id
+	WHERE s.search_vector @@ websearch_to_tsquery('simple', $1) OR $1 <% s.sound_name
+	UNION ALL
+	SELECT 'artist', u.id, u.user_name, '',
+		ts_headline('simple', u.user_name, websearch_to_tsquery('simple', $1), $2),
+		ts_rank(u.search_vector, websearch_to_tsquery('simple', $1)) + word_similarity($1, u.user_name)
+	FROM users u
+	WHERE NOT COALESCE(u.is_banned, FALSE)
+		AND (u.search_vector @@ websearch_to_tsquery('simple', $1) OR $1 <% u.user_name)
+	UNION ALL
+	SELECT 'album', a.id, a.title, COALESCE(u.user_name, ''),
+		ts_headline('simple', a.title, websearch_to_tsquery('simple', $1), $2),
+		ts_rank(a.search_vector, websearch_to_tsquery('simple', $1)) + word_similarity($1, a.title)
+	FROM albums a
+	LEFT JOIN users u ON u.id = a.artist_id
+	WHERE a.search_vector @@ websearch_to_tsquery('simple', $1) OR $1 <% a.title`
+
+func NewSearchRepository(db *sql.DB, logger *pkg.CustomLogger) (*SearchRepository, error) {
+	repository := SearchRepository{db: db, logger: logger}
+
+	_, err := db.Exec(createSearchIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+func (r *SearchRepository) Search(ctx context.Context, q *search.Query) ([]*search.Hit, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SearchRepository.Search")
+	defer span.End()
+
+	query := `SELECT type, id, title, subtitle, headline, rank
+		FROM (` + searchMatches + `) r
+		WHERE r.type = ANY($3)
+		ORDER BY r.rank DESC, r.id DESC
+		LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.QueryContext(ctx, query, q.Text(), headlineOptions, pq.Array(q.Types()), q.Limit(), q.Offset())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*search.Hit
+	for rows.Next() {
+		var h search.Hit
+		if err := rows.Scan(&h.Type, &h.ID, &h.Title, &h.Subtitle, &h.Highlight, &h.Rank); err != nil {
+			return nil, err
+		}
+		hits = append(hits, &h)
+	}
+
+	return hits, rows.Err()
+}
+
+func (r *SearchRepository) CountByType(ctx context.Context, q *search.Query) (map[string]int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SearchRepository.CountByType")
+	defer span.End()
+
+	query := `SELECT type, COUNT(*) FROM (` + searchMatches + `) r GROUP BY type`
+
+	rows, err := r.db.QueryContext(ctx, query, q.Text(), headlineOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facets := make(map[string]int)
+	for rows.Next() {
+		var resultType string
+		var count int
+		if err := rows.Scan(&resultType, &count); err != nil {
+			return nil, err
+		}
+		facets[resultType] = count
+	}
+
+	return facets, rows.Err()
+}
+
+func (r *SearchRepository) Suggest(ctx context.Context, prefix string, limit int) ([]*search.Suggestion, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SearchRepository.Suggest")
+	defer span.End()
+
+	query := `SELECT type, id, text FROM (
+			SELECT 'sound' AS type, s.id, s.sound_name AS text, ts_rank(s.search_vector, to_tsquery('simple', $1)) AS rank
+			FROM sounds s
+			WHERE s.search_vector @@ to_tsquery('simple', $1)
+			UNION ALL
+			SELECT 'artist', u.id, u.user_name, ts_rank(u.search_vector, to_tsquery('simple', $1))
+			FROM users u
+			WHERE NOT COALESCE(u.is_banned, FALSE) AND u.search_vector @@ to_tsquery('simple', $1)
+			UNION ALL
+			SELECT 'album', a.id, a.title, ts_rank(a.search_vector, to_tsquery('simple', $1))
+			FROM albums a
+			WHERE a.search_vector @@ to_tsquery('simple', $1)
+		) r
+		ORDER BY r.rank DESC, LENGTH(r.text), r.id
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []*search.Suggestion
+	for rows.Next() {
+		var s search.Suggestion
+		if err := rows.Scan(&s.Type, &s.ID, &s.Text); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, &s)
+	}
+
+	return suggestions, rows.Err()
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"soundtube/internal/domain/auth"
+	"soundtube/pkg"
+	"time"
+)
+
+type SessionRepository struct {
+	db     *sql.DB
+	logger *pkg.CustomLogger
+}
+
+//go:embed migrations/session/001_create_session_table_up.sql
+var createSessionTable string
+
+func NewSessionRepository(db *sql.DB, logger *pkg.CustomLogger) (*SessionRepository, error) {
+	repository := SessionRepository{db: db, logger: logger}
+
+	if _, err := db.Exec(createSessionTable); err != nil {
+		return nil, err
+	}
+
+	return &repository, nil
+}
+
+func (r *SessionRepository) CreateSession(ctx context.Context, session *auth.Session) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SessionRepository.CreateSession")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO login_sessions (id, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)`,
+		session.ID(), session.UserID(), session.CreatedAt(), session.ExpiresAt())
+	return err
+}
+
+func (r *SessionRepository) EndSession(ctx context.Context, id string, at time.Time) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SessionRepository.EndSession")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE login_sessions SET ended_at = $1 WHERE id = $2 AND ended_at IS NULL", at, id)
+	return err
+}
+
+func (r *SessionRepository) GetSessionsByUser(ctx context.Context, userID int) ([]*auth.Session, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SessionRepository.GetSessionsByUser")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, user_id, created_at, expires_at, ended_at
+		FROM login_sessions
+		WHERE user_id = $1
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*auth.Session{}
+	for rows.Next() {
+		var id string
+		var uid int
+		var createdAt, expiresAt time.Time
+		var endedAt sql.NullTime
+		if err := rows.Scan(&id, &uid, &createdAt, &expiresAt, &endedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, auth.RebuildSessionFromStorage(id, uid, createdAt, expiresAt, endedAt.Time))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"soundtube/internal/domain/mail"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"strconv"
+
+	"gopkg.in/gomail.v2"
+)
+
+type SMTPMailer struct {
+	dialer *gomail.Dialer
+	from   string
+	logger *pkg.CustomLogger
+}
+
+func NewSMTPMailer(cfg *config.Email, logger *pkg.CustomLogger) *SMTPMailer {
+	var port, _ = strconv.Atoi(cfg.SMTPort)
+
+	return &SMTPMailer{
+		dialer: gomail.NewDialer(cfg.SMTHost, port, cfg.Username, cfg.Password),
+		from:   cfg.From,
+		logger: logger,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg *mail.Message) error {
+	_, span := m.logger.GetTracer().Start(ctx, "SMTPMailer.Send")
+	defer span.End()
+
+	return m.dialer.DialAndSend(composeMail(m.from, msg))
+}
+
+// HealthCheck connects to the SMTP server and waits for its greeting, without logging in.
+func (m *SMTPMailer) HealthCheck(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.dialer.Host, strconv.Itoa(m.dialer.Port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.dialer.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	return client.Quit()
+}
+
+func composeMail(from string, msg *mail.Message) *gomail.Message {
+	message := gomail.NewMessage()
+	message.SetHeader("From", from)
+	message.SetHeader("To", msg.To())
+	message.SetHeader("Subject", msg.Subject())
+	message.SetHeaders(msg.Headers())
+
+	switch {
+	case msg.HTML() == "":
+		message.SetBody("text/plain", msg.Text())
+	case msg.Text() == "":
+		message.SetBody("text/html", msg.HTML())
+	default:
+		message.SetBody("text/plain", msg.Text())
+		message.AddAlternative("text/html", msg.HTML())
+	}
+
+	return message
+}
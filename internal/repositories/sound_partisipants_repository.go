@@ -19,6 +19,7 @@ type SoundPartisipantsResponse struct {
 	UserID    int
 	ReactID   int
 	ReactType string
+	CreatedAt string
 }
 
 //go:embed migrations/reactions/001_create_soupd_partisipants_table_up.sql
@@ -77,6 +78,43 @@ func (r *SoundPartisipantsRepository) Remove(ctx context.Context, userID, soundI
 	return err
 }
 
+func (r *SoundPartisipantsRepository) GetByUser(ctx context.Context, userID int) ([]*SoundPartisipantsResponse, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundPartisipantsRepository.GetByUser")
+	defer span.End()
+
+	query := `SELECT sound_id, react_type, created_at
+	FROM sound_participants
+	WHERE user_id = $1
+	ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []*SoundPartisipantsResponse
+	for rows.Next() {
+		var soundID int
+		var reactType, createdAt string
+		if err := rows.Scan(&soundID, &reactType, &createdAt); err != nil {
+			return nil, err
+		}
+		participants = append(participants, &SoundPartisipantsResponse{
+			SoundID:   soundID,
+			UserID:    userID,
+			ReactType: reactType,
+			CreatedAt: createdAt,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
 func (r *SoundPartisipantsRepository) GetUserReactonBatch(ctx context.Context, userID int, soundIDs []int) (map[int]string, error) {
 	ctx, span := r.logger.GetTracer().Start(ctx, "SoundPartisipantsRepository.GetUserReactonBatch")
 	defer span.End()
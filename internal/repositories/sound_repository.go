@@ -3,10 +3,15 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"path/filepath"
 	"soundtube/internal/domain/sound"
 	"soundtube/pkg"
+	"strings"
 
 	_ "embed"
+
+	"github.com/lib/pq"
 )
 
 type SoundRepository struct {
@@ -17,6 +22,9 @@ type SoundRepository struct {
 //go:embed migrations/sound/001_create_sound_table_up.sql
 var createSoundTable string
 
+//go:embed migrations/sound/002_add_listing_columns_up.sql
+var addSoundListingColumns string
+
 func NewSoundRepository(db *sql.DB, logger *pkg.CustomLogger) (*SoundRepository, error) {
 	soundRepository := SoundRepository{
 		db:     db,
@@ -28,16 +36,62 @@ func NewSoundRepository(db *sql.DB, logger *pkg.CustomLogger) (*SoundRepository,
 		return nil, err
 	}
 
+	if _, err = soundRepository.db.Exec(addSoundListingColumns); err != nil {
+		return nil, err
+	}
+
 	return &soundRepository, nil
 }
 
-func (r *SoundRepository) GetSounds(ctx context.Context) ([]*sound.Sound, error) {
-	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetSounds")
-	defer span.End()
+const soundColumns = `SELECT s.id, s.author_id, COALESCE(u.user_name, ''), s.sound_name, COALESCE(a.title, ''), COALESCE(s.sound_genre, ''),
+		COALESCE(s.duration, 0), COALESCE(s.file_name, ''), COALESCE(s.file_size, 0), COALESCE(s.file_format, ''), s.upload_date, COALESCE(s.file_path, ''),
+		COALESCE(s.album_id, 0), COALESCE(s.track_number, 0), s.play_count`
+
+const soundJoins = `
+	FROM sounds s
+	LEFT JOIN users u ON u.id = s.author_id
+	LEFT JOIN albums a ON a.id = s.album_id`
 
-	rows, err := r.db.QueryContext(ctx, `SELECT 
-		id, author_id, sound_name, sound_album, sound_genre, duration, file_name, file_size, file_format, upload_date, file_path
-		FROM sounds`)
+const selectSounds = soundColumns + soundJoins
+
+// soundSortKeys maps listing sorts to their indexed column and the type used to
+// cast the cursor key back from text.
+var soundSortKeys = map[string]struct{ column, cast string }{
+	sound.SortNewest:     {"s.upload_date", "timestamp"},
+	sound.SortMostLiked:  {"s.like_count", "integer"},
+	sound.SortMostPlayed: {"s.play_count", "bigint"},
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// keyedRow scans one extra trailing column into key.
+type keyedRow struct {
+	row rowScanner
+	key any
+}
+
+func (k keyedRow) Scan(dest ...any) error {
+	return k.row.Scan(append(dest, k.key)...)
+}
+
+func scanSound(row rowScanner) (*sound.Sound, error) {
+	var id, authorID, duration, fileSize, albumID, trackNumber int
+	var playCount int64
+	var authorName, soundName, soundAlbum, soundGenre, fileName, fileFormat, uploadDate, filePath string
+
+	err := row.Scan(&id, &authorID, &authorName, &soundName, &soundAlbum, &soundGenre,
+		&duration, &fileName, &fileSize, &fileFormat, &uploadDate, &filePath, &albumID, &trackNumber, &playCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return sound.RebuildSoundFromStorage(id, authorID, duration, soundName, soundAlbum, soundGenre, fileName, filePath, fileSize, fileFormat, uploadDate, authorName, albumID, trackNumber, playCount), nil
+}
+
+func (r *SoundRepository) querySounds(ctx context.Context, query string, args ...any) ([]*sound.Sound, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -45,13 +99,10 @@ func (r *SoundRepository) GetSounds(ctx context.Context) ([]*sound.Sound, error)
 
 	var sounds []*sound.Sound
 	for rows.Next() {
-		var id, authorId, duration, fileSize int
-		var soundName, soundAlbum, soundGenre, fileName, fileFormat, uploadDate, filePath string
-		err = rows.Scan(&id, &authorId, &soundName, &soundAlbum, &soundGenre, &duration, &fileName, &fileSize, &fileFormat, &uploadDate, &filePath)
+		sound, err := scanSound(rows)
 		if err != nil {
 			return nil, err
 		}
-		sound := sound.RebuildSoundFromStorage(id, authorId, duration, soundName, soundAlbum, soundGenre, fileName, filePath, fileSize, fileFormat, uploadDate)
 		sounds = append(sounds, sound)
 	}
 
@@ -62,20 +113,138 @@ func (r *SoundRepository) GetSounds(ctx context.Context) ([]*sound.Sound, error)
 	return sounds, nil
 }
 
-func (r *SoundRepository) GetSoundByName(ctx context.Context, name string) (*sound.Sound, error) {
-	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetSoundByName")
+// FindSounds returns one page of the listing using keyset pagination on the sort column and id.
+func (r *SoundRepository) FindSounds(ctx context.Context, q *sound.Query) (*sound.Page, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.FindSounds")
+	defer span.End()
+
+	sortKey := soundSortKeys[q.Sort]
+
+	var conditions []string
+	var args []any
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if q.Genre != "" {
+		where("LOWER(s.sound_genre) = LOWER($%d)", q.Genre)
+	}
+	if q.AuthorID != 0 {
+		where("s.author_id = $%d", q.AuthorID)
+	}
+	if !q.UploadedFrom.IsZero() {
+		where("s.upload_date >= $%d", q.UploadedFrom)
+	}
+	if !q.UploadedTo.IsZero() {
+		where("s.upload_date < $%d", q.UploadedTo)
+	}
+	if q.MinDuration > 0 {
+		where("s.duration >= $%d", q.MinDuration)
+	}
+	if q.MaxDuration > 0 {
+		where("s.duration <= $%d", q.MaxDuration)
+	}
+	if q.Format != "" {
+		where("s.file_format = $%d", q.Format)
+	}
+	if q.After != nil {
+		where("("+sortKey.column+", s.id) < ($%d::"+sortKey.cast+", $%d)", q.After.Key, q.After.ID)
+	}
+
+	query := soundColumns + ", " + sortKey.column + "::text" + soundJoins
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, q.Limit+1)
+	query += fmt.Sprintf(" ORDER BY %s DESC, s.id DESC LIMIT $%d", sortKey.column, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &sound.Page{Sounds: []*sound.Sound{}}
+	var keys []string
+	for rows.Next() {
+		var key string
+		s, err := scanSound(keyedRow{row: rows, key: &key})
+		if err != nil {
+			return nil, err
+		}
+		page.Sounds = append(page.Sounds, s)
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Sounds) > q.Limit {
+		page.Sounds = page.Sounds[:q.Limit]
+		last := page.Sounds[q.Limit-1]
+		page.Next = &sound.Cursor{Sort: q.Sort, Key: keys[q.Limit-1], ID: last.ID()}
+	}
+
+	return page, nil
+}
+
+func (r *SoundRepository) GetSoundsByAuthor(ctx context.Context, authorID int) ([]*sound.Sound, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetSoundsByAuthor")
+	defer span.End()
+
+	return r.querySounds(ctx, selectSounds+` WHERE s.author_id = $1 ORDER BY s.id`, authorID)
+}
+
+func (r *SoundRepository) GetSoundsPageByAuthor(ctx context.Context, authorID, limit, offset int) ([]*sound.Sound, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetSoundsPageByAuthor")
+	defer span.End()
+
+	return r.querySounds(ctx, selectSounds+` WHERE s.author_id = $1 ORDER BY s.upload_date DESC, s.id DESC LIMIT $2 OFFSET $3`, authorID, limit, offset)
+}
+
+func (r *SoundRepository) GetSoundsByIDs(ctx context.Context, ids []int) ([]*sound.Sound, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetSoundsByIDs")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return []*sound.Sound{}, nil
+	}
+
+	return r.querySounds(ctx, selectSounds+` WHERE s.id = ANY($1)`, pq.Array(ids))
+}
+
+func (r *SoundRepository) GetSoundsByAlbum(ctx context.Context, albumID int) ([]*sound.Sound, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetSoundsByAlbum")
 	defer span.End()
 
-	query := `SELECT id, author_id, sound_name, sound_album, sound_genre, duration, file_name, file_size, file_format, upload_date, file_path
-        FROM sounds WHERE sound_name = $1`
+	return r.querySounds(ctx, selectSounds+` WHERE s.album_id = $1 ORDER BY s.track_number, s.id`, albumID)
+}
 
-	var id, authorID, duration, fileSize int
-	var soundName, soundAlbum, soundGenre, fileName, fileFormat, uploadDate, filePath string
+func (r *SoundRepository) GetNextTrackNumber(ctx context.Context, albumID int) (int, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetNextTrackNumber")
+	defer span.End()
 
-	err := r.db.QueryRowContext(ctx, query, name).Scan(
-		&id, &authorID, &soundName, &soundAlbum, &soundGenre,
-		&duration, &fileName, &fileSize, &fileFormat, &uploadDate, &filePath,
-	)
+	var next int
+	err := r.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(track_number), 0) + 1 FROM sounds WHERE album_id = $1", albumID).Scan(&next)
+	if err != nil {
+		return 0, err
+	}
+
+	return next, nil
+}
+
+func (r *SoundRepository) GetSoundByName(ctx context.Context, name string) (*sound.Sound, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetSoundByName")
+	defer span.End()
+
+	sound, err := scanSound(r.db.QueryRowContext(ctx, selectSounds+` WHERE s.sound_name = $1`, name))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -83,7 +252,6 @@ func (r *SoundRepository) GetSoundByName(ctx context.Context, name string) (*sou
 		return nil, err
 	}
 
-	sound := sound.RebuildSoundFromStorage(id, authorID, duration, soundName, soundAlbum, soundGenre, fileName, filePath, fileSize, fileFormat, uploadDate)
 	return sound, nil
 }
 
@@ -91,16 +259,7 @@ func (r *SoundRepository) GetSoundByID(ctx context.Context, id int) (*sound.Soun
 	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.GetSoundByID")
 	defer span.End()
 
-	query := `SELECT id, author_id, sound_name, sound_album, sound_genre, duration, file_name, file_size, file_format, upload_date, file_path
-        FROM sounds WHERE id = $1`
-
-	var authorID, duration, fileSize int
-	var soundName, soundAlbum, soundGenre, fileName, fileFormat, uploadDate, filePath string
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&id, &authorID, &soundName, &soundAlbum, &soundGenre,
-		&duration, &fileName, &fileSize, &fileFormat, &uploadDate, &filePath,
-	)
+	sound, err := scanSound(r.db.QueryRowContext(ctx, selectSounds+` WHERE s.id = $1`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -108,33 +267,34 @@ func (r *SoundRepository) GetSoundByID(ctx context.Context, id int) (*sound.Soun
 		return nil, err
 	}
 
-	sound := sound.RebuildSoundFromStorage(id, authorID, duration, soundName, soundAlbum, soundGenre, fileName, filePath, fileSize, fileFormat, uploadDate)
 	return sound, nil
 }
 
-func (r *SoundRepository) CreateSound(ctx context.Context, sound *sound.Sound) error {
+func (r *SoundRepository) CreateSound(ctx context.Context, sound *sound.Sound) (int, error) {
 	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.CreateSound")
 	defer span.End()
 
 	tx, err := r.db.BeginTx(ctx, nil)
-	defer tx.Rollback()
 	if err != nil {
-		return err
+		return 0, err
 	}
+	defer tx.Rollback()
 
-	query := `INSERT INTO sounds (author_id, sound_name, sound_album, sound_genre, duration, file_name, file_size, file_format)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	query := `INSERT INTO sounds (author_id, sound_name, album_id, track_number, sound_genre, duration, file_name, file_size, file_format)
+		VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, 0), $5, $6, $7, $8, $9)
+		RETURNING id`
 
-	_, err = tx.ExecContext(ctx, query, sound.AuthorID(), sound.Name(), sound.Ablum(), sound.Genre(), sound.Duration(), sound.FileName(), sound.FileSize(), sound.FileFormat())
+	var id int
+	err = tx.QueryRowContext(ctx, query, sound.AuthorID(), sound.Name(), sound.AlbumID(), sound.TrackNumber(), sound.Genre(), sound.Duration(), sound.FileName(), sound.FileSize(), sound.FileFormat()).Scan(&id)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return id, nil
 }
 
 func (r *SoundRepository) DeleteSound(ctx context.Context, sound *sound.Sound) error {
@@ -154,9 +314,10 @@ func (r *SoundRepository) UpdateSoundFile(ctx context.Context, name, fileName, f
 	}
 	defer tx.Rollback()
 
-	query := `UPDATE sounds SET file_name = $1, file_path = $2, file_size = $3 WHERE sound_name = $4`
+	query := `UPDATE sounds SET file_name = $1, file_path = $2, file_size = $3, file_format = $4 WHERE sound_name = $5`
 
-	_, err = tx.ExecContext(ctx, query, fileName, filePath, fileSize, name)
+	fileFormat := strings.ToLower(strings.TrimPrefix(filepath.Ext(fileName), "."))
+	_, err = tx.ExecContext(ctx, query, fileName, filePath, fileSize, fileFormat, name)
 	if err != nil {
 		return err
 	}
@@ -167,3 +328,13 @@ func (r *SoundRepository) UpdateSoundFile(ctx context.Context, name, fileName, f
 
 	return nil
 }
+
+func (r *SoundRepository) SetSoundAlbum(ctx context.Context, soundID, albumID, trackNumber int) error {
+	ctx, span := r.logger.GetTracer().Start(ctx, "SoundRepository.SetSoundAlbum")
+	defer span.End()
+
+	query := `UPDATE sounds SET album_id = NULLIF($1, 0), track_number = NULLIF($2, 0) WHERE id = $3`
+
+	_, err := r.db.ExecContext(ctx, query, albumID, trackNumber, soundID)
+	return err
+}
@@ -26,11 +26,11 @@ func (t *TokenBlacklist) Add(ctx context.Context, token string, expiration time.
 		attribute.String("token", token),
 	)
 
-	return t.client.Set(formatTokenForList(token), "1", expiration).Err()
+	return tracedRedis(ctx, t.client).Set(formatTokenForList(token), "1", expiration).Err()
 }
 
 func (t *TokenBlacklist) Exist(ctx context.Context, token string) (bool, error) {
-	exists, err := t.client.Exists(formatTokenForList(token)).Result()
+	exists, err := tracedRedis(ctx, t.client).Exists(formatTokenForList(token)).Result()
 	if err != nil {
 		return false, err
 	}
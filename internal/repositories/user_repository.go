@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	_ "embed"
 	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/mail"
 	"soundtube/pkg"
 )
 
@@ -16,6 +17,9 @@ type UserRepository struct {
 //go:embed migrations/user/001_create_user_table_up.sql
 var createUserTable string
 
+//go:embed migrations/user/002_add_role_column_up.sql
+var addUserRoleColumn string
+
 func NewUserRepository(db *sql.DB, logger *pkg.CustomLogger) (*UserRepository, error) {
 	var userRepository = UserRepository{
 		db:     db,
@@ -27,6 +31,10 @@ func NewUserRepository(db *sql.DB, logger *pkg.CustomLogger) (*UserRepository, e
 		return nil, err
 	}
 
+	if _, err = db.Exec(addUserRoleColumn); err != nil {
+		return nil, err
+	}
+
 	return &userRepository, nil
 }
 
@@ -34,42 +42,42 @@ func (r *UserRepository) GetUserByName(ctx context.Context, name string) (*auth.
 	ctx, span := r.logger.GetTracer().Start(ctx, "UserRepository.GetUserByName")
 	defer span.End()
 
-	query := `SELECT id, user_password, user_email, is_verified, is_banned, verify_token
+	query := `SELECT id, user_password, user_email, user_role, is_verified, is_banned, verify_token
 				FROM users WHERE user_name = $1`
 	row := r.db.QueryRowContext(ctx, query, name)
 
 	var id int
-	var password, email, verifyToken string
+	var password, email, role, verifyToken string
 	var isVerified, isBanned bool
-	err := row.Scan(&id, &password, &email, &isVerified, &isBanned, &verifyToken)
+	err := row.Scan(&id, &password, &email, &role, &isVerified, &isBanned, &verifyToken)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	user := auth.RebuildUserFromStorage(id, name, email, password, isVerified, isBanned, verifyToken)
+	user := auth.RebuildUserFromStorage(id, name, email, password, role, isVerified, isBanned, verifyToken)
 	return user, nil
 }
 
 func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*auth.User, error) {
-	ctx, span := r.logger.GetTracer().Start(ctx, "UserRepository.GetUserByName")
+	ctx, span := r.logger.GetTracer().Start(ctx, "UserRepository.GetUserByID")
 	defer span.End()
 
-	query := `SELECT user_name, user_password, user_email, is_verified, is_banned, verify_token
+	query := `SELECT user_name, user_password, user_email, user_role, is_verified, is_banned, verify_token
 				FROM users WHERE id = $1`
 	row := r.db.QueryRowContext(ctx, query, id)
 
-	var name, password, email, verifyToken string
+	var name, password, email, role, verifyToken string
 	var isVerified, isBanned bool
-	err := row.Scan(&id, &password, &email, &isVerified, &isBanned, &verifyToken)
+	err := row.Scan(&name, &password, &email, &role, &isVerified, &isBanned, &verifyToken)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	user := auth.RebuildUserFromStorage(id, name, email, password, isVerified, isBanned, verifyToken)
+	user := auth.RebuildUserFromStorage(id, name, email, password, role, isVerified, isBanned, verifyToken)
 	return user, nil
 }
 
@@ -77,25 +85,52 @@ func (r *UserRepository) GetUserByToken(ctx context.Context, token string) (*aut
 	ctx, span := r.logger.GetTracer().Start(ctx, "UserRepository.GetUserByName")
 	defer span.End()
 
-	query := `SELECT id, user_name, user_password, user_email, is_verified, is_banned
+	query := `SELECT id, user_name, user_password, user_email, user_role, is_verified, is_banned
 				FROM users WHERE verify_token = $1`
 	row := r.db.QueryRowContext(ctx, query, token)
 
 	var id int
-	var name, password, email string
+	var name, password, email, role string
 	var isVerified, isBanned bool
-	err := row.Scan(&id, &name, &password, &email, &isVerified, &isBanned)
+	err := row.Scan(&id, &name, &password, &email, &role, &isVerified, &isBanned)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	user := auth.RebuildUserFromStorage(id, name, email, password, isVerified, isBanned, token)
+	user := auth.RebuildUserFromStorage(id, name, email, password, role, isVerified, isBanned, token)
 	return user, nil
 }
 
-func (r *UserRepository) CreateUser(ctx context.Context, user *auth.User) error {
+func (r *UserRepository) GetProfileByName(ctx context.Context, username string) (*auth.Profile, error) {
+	ctx, span := r.logger.GetTracer().Start(ctx, "UserRepository.GetProfileByName")
+	defer span.End()
+
+	query := `SELECT u.id, u.user_name, u.created_at,
+			(SELECT COUNT(*) FROM sounds s WHERE s.author_id = u.id),
+			(SELECT COUNT(*) FROM follows f WHERE f.followee_id = u.id),
+			COALESCE((SELECT SUM(r.total_likes) FROM sound_reactions r JOIN sounds s ON s.id = r.sound_id WHERE s.author_id = u.id), 0),
+			COALESCE((SELECT SUM(r.total_dislikes) FROM sound_reactions r JOIN sounds s ON s.id = r.sound_id WHERE s.author_id = u.id), 0)
+		FROM users u
+		WHERE u.user_name = $1 AND u.is_banned = FALSE
+		ORDER BY u.id
+		LIMIT 1`
+
+	var id, uploads, followers, likes, dislikes int
+	var name, joinedAt string
+	err := r.db.QueryRowContext(ctx, query, username).Scan(&id, &name, &joinedAt, &uploads, &followers, &likes, &dislikes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return auth.RebuildProfileFromStorage(id, name, joinedAt, uploads, followers, likes, dislikes), nil
+}
+
+func (r *UserRepository) CreateUser(ctx context.Context, user *auth.User, outbox ...*mail.Message) error {
 	ctx, span := r.logger.GetTracer().Start(ctx, "UserRepository.GetUserByName")
 	defer span.End()
 
@@ -105,13 +140,19 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *auth.User) error
 		return err
 	}
 
-	query := `INSERT INTO users (user_name, user_email, user_password, is_verified, is_banned, verify_token)
-		VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = tx.ExecContext(ctx, query, user.Username(), user.Email(), user.Password(), user.IsVerified(), user.IsBanned(), user.VerifyToken())
+	query := `INSERT INTO users (user_name, user_email, user_password, user_role, is_verified, is_banned, verify_token)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = tx.ExecContext(ctx, query, user.Username(), user.Email(), user.Password(), user.Role(), user.IsVerified(), user.IsBanned(), user.VerifyToken())
 	if err != nil {
 		return err
 	}
 
+	for _, msg := range outbox {
+		if err = enqueueMail(ctx, tx, msg); err != nil {
+			return err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return err
 	}
@@ -126,7 +167,7 @@ func (r *UserRepository) DeleteUser(ctx context.Context, id int) error {
 	query := "DELETE FROM users WHERE id = $1"
 	_, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
-		r.logger.Error("delete user failed", err).WithTrace(ctx)
+		r.logger.ErrorContext(ctx, "delete user failed", err)
 		return err
 	}
 
@@ -141,7 +182,7 @@ func (r *UserRepository) MarkUserAsVerified(ctx context.Context, id int) error {
 
 	_, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
-		r.logger.Error("Verify update failed", err).WithTrace(ctx)
+		r.logger.ErrorContext(ctx, "Verify update failed", err)
 		return err
 	}
 
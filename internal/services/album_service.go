@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"soundtube/internal/domain/album"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type AlbumService struct {
+	repository album.IAlbumRepository
+	sounds     sound.ISoundRepository
+	users      auth.IUserRepositoryReader
+	logger     *pkg.CustomLogger
+}
+
+func NewAlbumService(repository album.IAlbumRepository, sounds sound.ISoundRepository, users auth.IUserRepositoryReader, logger *pkg.CustomLogger) *AlbumService {
+	return &AlbumService{repository: repository, sounds: sounds, users: users, logger: logger}
+}
+
+func (s *AlbumService) CreateAlbum(ctx context.Context, artistID int, title, releaseDate string) (int, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "AlbumService.CreateAlbum")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("album.title", title),
+	)
+
+	a, err := album.NewAlbum(artistID, title, releaseDate)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid album params", err)
+		return 0, err
+	}
+
+	existing, err := s.repository.GetAlbumByTitle(ctx, artistID, title)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return 0, err
+	}
+
+	if existing != nil {
+		return existing.ID(), nil
+	}
+
+	id, err := s.repository.CreateAlbum(ctx, a)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetAlbum returns the album with its tracklist ordered by track number.
+func (s *AlbumService) GetAlbum(ctx context.Context, id int) (*album.AlbumDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "AlbumService.GetAlbum")
+	defer span.End()
+
+	a, err := s.getAlbum(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.sounds.GetSoundsByAlbum(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	dto := a.ToDTO()
+	dto.Tracks = sound.SoundsToDTO(tracks)
+	return dto, nil
+}
+
+func (s *AlbumService) GetArtistAlbums(ctx context.Context, username string) ([]*album.Album, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "AlbumService.GetArtistAlbums")
+	defer span.End()
+
+	artist, err := s.users.GetUserByName(ctx, username)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	if artist == nil || artist.IsBanned() {
+		return nil, UserNotFound
+	}
+
+	albums, err := s.repository.GetAlbumsByArtist(ctx, artist.ID())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return albums, nil
+}
+
+func (s *AlbumService) UpdateAlbum(ctx context.Context, userID, id int, title, releaseDate string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "AlbumService.UpdateAlbum")
+	defer span.End()
+
+	a, err := s.getOwnedAlbum(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err = a.Update(title, releaseDate); err != nil {
+		s.logger.WarnContext(ctx, "invalid album params", err)
+		return err
+	}
+
+	if err = s.repository.UpdateAlbum(ctx, id, a); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AlbumService) UpdateCover(ctx context.Context, userID, id int, coverPath string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "AlbumService.UpdateCover")
+	defer span.End()
+
+	if _, err := s.getOwnedAlbum(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := s.repository.UpdateAlbumCover(ctx, id, coverPath); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAlbum removes the album. Its sounds are kept and simply detached from it.
+func (s *AlbumService) DeleteAlbum(ctx context.Context, userID, id int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "AlbumService.DeleteAlbum")
+	defer span.End()
+
+	if _, err := s.getOwnedAlbum(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := s.repository.DeleteAlbum(ctx, id); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetTrack attaches one of the artist's sounds to the album at the given position,
+// or appends it when trackNumber is 0.
+func (s *AlbumService) SetTrack(ctx context.Context, userID, id, soundID, trackNumber int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "AlbumService.SetTrack")
+	defer span.End()
+
+	if trackNumber < 0 {
+		return album.ErrInvalidTrackNumber
+	}
+
+	if _, err := s.getOwnedAlbum(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if _, err := s.getOwnedSound(ctx, userID, soundID); err != nil {
+		return err
+	}
+
+	if trackNumber == 0 {
+		var err error
+		if trackNumber, err = s.sounds.GetNextTrackNumber(ctx, id); err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			return err
+		}
+	}
+
+	if err := s.sounds.SetSoundAlbum(ctx, soundID, id, trackNumber); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AlbumService) RemoveTrack(ctx context.Context, userID, id, soundID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "AlbumService.RemoveTrack")
+	defer span.End()
+
+	if _, err := s.getOwnedAlbum(ctx, userID, id); err != nil {
+		return err
+	}
+
+	snd, err := s.getOwnedSound(ctx, userID, soundID)
+	if err != nil {
+		return err
+	}
+
+	if snd.AlbumID() != id {
+		return SoundNotFound
+	}
+
+	if err = s.sounds.SetSoundAlbum(ctx, soundID, 0, 0); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AlbumService) getAlbum(ctx context.Context, id int) (*album.Album, error) {
+	a, err := s.repository.GetAlbumByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	if a == nil {
+		return nil, AlbumNotFound
+	}
+
+	return a, nil
+}
+
+func (s *AlbumService) getOwnedAlbum(ctx context.Context, userID, id int) (*album.Album, error) {
+	a, err := s.getAlbum(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.IsOwnedBy(userID) {
+		s.logger.WarnContext(ctx, "album modification denied", AlbumForbidden)
+		return nil, AlbumForbidden
+	}
+
+	return a, nil
+}
+
+func (s *AlbumService) getOwnedSound(ctx context.Context, userID, soundID int) (*sound.Sound, error) {
+	snd, err := s.sounds.GetSoundByID(ctx, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	if snd == nil {
+		return nil, SoundNotFound
+	}
+
+	if snd.AuthorID() != userID {
+		s.logger.WarnContext(ctx, "sound belongs to another artist", AlbumForbidden)
+		return nil, AlbumForbidden
+	}
+
+	return snd, nil
+}
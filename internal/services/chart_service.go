@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"soundtube/internal/domain/chart"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ChartService struct {
+	stats  chart.IChartStatsRepository
+	store  chart.IChartStore
+	sounds sound.ISoundRepositoryReader
+	logger *pkg.CustomLogger
+
+	interval time.Duration
+	size     int
+
+	done chan struct{}
+}
+
+func NewChartService(stats chart.IChartStatsRepository, store chart.IChartStore, sounds sound.ISoundRepositoryReader,
+	cfg *config.Charts, logger *pkg.CustomLogger) *ChartService {
+	return &ChartService{
+		stats:    stats,
+		store:    store,
+		sounds:   sounds,
+		logger:   logger,
+		interval: cfg.RefreshInterval,
+		size:     cfg.Size,
+		done:     make(chan struct{}),
+	}
+}
+
+// Run recomputes the charts right away and then on every refresh interval until Stop is called.
+func (s *ChartService) Run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.refresh(context.Background())
+
+	for {
+		select {
+		case <-ticker.C:
+			s.refresh(context.Background())
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *ChartService) Stop() {
+	close(s.done)
+}
+
+func (s *ChartService) refresh(ctx context.Context) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ChartService.refresh")
+	defer span.End()
+
+	now := time.Now()
+	for window, duration := range chart.Windows {
+		stats, err := s.stats.GetSoundStats(ctx, duration)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			continue
+		}
+
+		charts := map[string][]chart.Entry{chart.AllGenres: {}}
+		for _, st := range stats {
+			entry := chart.Entry{SoundID: st.SoundID, Score: st.HotScore(now, duration)}
+			charts[chart.AllGenres] = append(charts[chart.AllGenres], entry)
+			if st.Genre != "" {
+				charts[st.Genre] = append(charts[st.Genre], entry)
+			}
+		}
+
+		for genre, entries := range charts {
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Score > entries[j].Score })
+			if len(entries) > s.size {
+				entries = entries[:s.size]
+			}
+
+			if err := s.store.Replace(ctx, window, genre, entries); err != nil {
+				s.logger.ErrorContext(ctx, "redis error", err)
+			}
+		}
+
+		s.logger.InfoContext(ctx, "charts refreshed", "window", window, "genres", len(charts))
+	}
+}
+
+// GetTrending returns a page of the chart for the genre and window, hottest first.
+func (s *ChartService) GetTrending(ctx context.Context, genre, window string, limit, offset int) ([]*sound.Sound, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ChartService.GetTrending")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("chart.genre", genre),
+		attribute.String("chart.window", window),
+	)
+
+	window, _, err := chart.ParseWindow(window)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid chart window", err)
+		return nil, fmt.Errorf("%w: %v", InvalidChartQuery, err)
+	}
+
+	ids, err := s.store.Top(ctx, window, chart.NormalizeGenre(genre), limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "redis error", err)
+		return nil, err
+	}
+
+	sounds, err := s.sounds.GetSoundsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return orderSoundsByIDs(sounds, ids), nil
+}
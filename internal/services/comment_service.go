@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/comment"
+	"soundtube/internal/domain/feed"
+	"soundtube/internal/domain/notification"
+	"soundtube/internal/domain/realtime"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type CommentService struct {
+	repository comment.ICommentRepository
+	sounds     sound.ISoundRepositoryReader
+	events     realtime.IEventPublisher
+	notifier   notification.INotifier
+	activities feed.IActivityPublisher
+	logger     *pkg.CustomLogger
+}
+
+func NewCommentService(repository comment.ICommentRepository, sounds sound.ISoundRepositoryReader, events realtime.IEventPublisher,
+	notifier notification.INotifier, activities feed.IActivityPublisher, logger *pkg.CustomLogger) *CommentService {
+	return &CommentService{repository: repository, sounds: sounds, events: events, notifier: notifier, activities: activities, logger: logger}
+}
+
+// CreateComment stores a comment on a sound, or a reply when parentID is set, pushes it
+// to the sound's live subscribers and followers' feeds, and notifies the sound author and
+// the replied-to author.
+func (s *CommentService) CreateComment(ctx context.Context, userID, soundID, parentID int, content string) (*comment.CommentDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "CommentService.CreateComment")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("sound.id", soundID),
+		attribute.Int("comment.parent_id", parentID),
+	)
+
+	c, err := comment.NewComment(soundID, userID, parentID, content)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid comment params", err)
+		return nil, InvalidComment
+	}
+
+	sd, err := s.sounds.GetSoundByID(ctx, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+	if sd == nil {
+		return nil, SoundNotFound
+	}
+
+	var parent *comment.Comment
+	if parentID != 0 {
+		if parent, err = s.repository.GetCommentByID(ctx, parentID); err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			return nil, err
+		}
+		if parent == nil || parent.SoundID() != soundID {
+			return nil, CommentNotFound
+		}
+	}
+
+	id, err := s.repository.CreateComment(ctx, c)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	created, err := s.repository.GetCommentByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+	if created == nil {
+		return nil, CommentNotFound
+	}
+
+	dto := created.ToDTO()
+	s.publishComment(ctx, dto)
+	s.notifyComment(ctx, created, sd, parent)
+
+	if err := s.activities.Publish(ctx, userID, feed.TypeComment, soundID); err != nil {
+		s.logger.WarnContext(ctx, "failed to publish comment activity", err)
+	}
+
+	return dto, nil
+}
+
+func (s *CommentService) GetComments(ctx context.Context, soundID, limit, offset int) ([]*comment.CommentDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "CommentService.GetComments")
+	defer span.End()
+
+	sd, err := s.sounds.GetSoundByID(ctx, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+	if sd == nil {
+		return nil, SoundNotFound
+	}
+
+	comments, err := s.repository.GetCommentsBySound(ctx, soundID, limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return comment.CommentsToDTO(comments), nil
+}
+
+func (s *CommentService) UpdateComment(ctx context.Context, userID, id int, content string) (*comment.CommentDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "CommentService.UpdateComment")
+	defer span.End()
+
+	c, err := s.getComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.AuthorID() != userID {
+		return nil, CommentForbidden
+	}
+
+	if err := c.Edit(content); err != nil {
+		s.logger.WarnContext(ctx, "invalid comment params", err)
+		return nil, InvalidComment
+	}
+
+	if err := s.repository.UpdateComment(ctx, c); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return c.ToDTO(), nil
+}
+
+// DeleteComment removes a comment and its replies. Besides the author, the author of the
+// sound and admins may remove comments as moderators; the comment's author is then told.
+func (s *CommentService) DeleteComment(ctx context.Context, userID int, role string, id int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "CommentService.DeleteComment")
+	defer span.End()
+
+	c, err := s.getComment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	moderated := c.AuthorID() != userID
+	if moderated && role != auth.RoleAdmin {
+		sd, err := s.sounds.GetSoundByID(ctx, c.SoundID())
+		if err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			return err
+		}
+		if sd == nil || sd.AuthorID() != userID {
+			return CommentForbidden
+		}
+	}
+
+	if err := s.repository.DeleteComment(ctx, id); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if moderated {
+		n, err := notification.NewNotification(c.AuthorID(), 0, notification.TypeModeration, c.SoundID(), c.ID(), "removed")
+		if err != nil {
+			s.logger.WarnContext(ctx, "invalid notification params", err)
+			return nil
+		}
+		if err := s.notifier.Notify(ctx, n); err != nil {
+			s.logger.WarnContext(ctx, "failed to notify about moderation", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *CommentService) getComment(ctx context.Context, id int) (*comment.Comment, error) {
+	c, err := s.repository.GetCommentByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+	if c == nil {
+		return nil, CommentNotFound
+	}
+
+	return c, nil
+}
+
+// notifyComment tells the parent comment's author about a reply and the sound author
+// about a new comment. The sound author gets a single notification when the reply is to
+// their own comment.
+func (s *CommentService) notifyComment(ctx context.Context, c *comment.Comment, sd *sound.Sound, parent *comment.Comment) {
+	var recipients []*notification.Notification
+
+	if parent != nil {
+		n, err := notification.NewNotification(parent.AuthorID(), c.AuthorID(), notification.TypeReply, c.SoundID(), c.ID(), "")
+		if err != nil {
+			s.logger.WarnContext(ctx, "invalid notification params", err)
+		} else {
+			recipients = append(recipients, n)
+		}
+	}
+
+	if parent == nil || parent.AuthorID() != sd.AuthorID() {
+		n, err := notification.NewNotification(sd.AuthorID(), c.AuthorID(), notification.TypeComment, c.SoundID(), c.ID(), "")
+		if err != nil {
+			s.logger.WarnContext(ctx, "invalid notification params", err)
+		} else {
+			recipients = append(recipients, n)
+		}
+	}
+
+	for _, n := range recipients {
+		if err := s.notifier.Notify(ctx, n); err != nil {
+			s.logger.WarnContext(ctx, "failed to notify about comment", err)
+		}
+	}
+}
+
+func (s *CommentService) publishComment(ctx context.Context, dto *comment.CommentDTO) {
+	event, err := realtime.NewEvent(realtime.EventComment, dto.SoundID, dto)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to build comment event", err)
+		return
+	}
+
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.WarnContext(ctx, "failed to publish comment event", err)
+	}
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/comment"
+	"soundtube/internal/domain/notification"
+	"soundtube/internal/domain/realtime"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg/config"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryComments struct {
+	mu       sync.Mutex
+	comments map[int]*comment.Comment
+	nextID   int
+}
+
+func newMemoryComments() *memoryComments {
+	return &memoryComments{comments: make(map[int]*comment.Comment)}
+}
+
+func (r *memoryComments) GetCommentByID(_ context.Context, id int) (*comment.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.comments[id], nil
+}
+
+func (r *memoryComments) GetCommentsBySound(_ context.Context, soundID, limit, offset int) ([]*comment.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*comment.Comment
+	for id := 1; id <= r.nextID; id++ {
+		if c, ok := r.comments[id]; ok && c.SoundID() == soundID {
+			result = append(result, c)
+		}
+	}
+	if offset >= len(result) {
+		return nil, nil
+	}
+	return result[offset:min(offset+limit, len(result))], nil
+}
+
+func (r *memoryComments) GetCommentsByAuthor(_ context.Context, authorID int) ([]*comment.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*comment.Comment
+	for _, c := range r.comments {
+		if c.AuthorID() == authorID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryComments) CreateComment(_ context.Context, c *comment.Comment) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	r.comments[r.nextID] = comment.RebuildCommentFromStorage(r.nextID, c.SoundID(), c.AuthorID(), "user", c.ParentID(),
+		c.Content(), c.CreatedAt(), c.UpdatedAt())
+	return r.nextID, nil
+}
+
+func (r *memoryComments) UpdateComment(_ context.Context, c *comment.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.comments[c.ID()] = c
+	return nil
+}
+
+func (r *memoryComments) DeleteComment(_ context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.comments, id)
+	return nil
+}
+
+// memoryBus delivers published events straight to the listener, like a single instance
+// talking to Redis would.
+type memoryBus struct {
+	mu        sync.Mutex
+	published []*realtime.Event
+	handle    func(*realtime.Event)
+	listening chan struct{}
+	closed    chan struct{}
+}
+
+func newMemoryBus() *memoryBus {
+	return &memoryBus{listening: make(chan struct{}), closed: make(chan struct{})}
+}
+
+func (b *memoryBus) Publish(_ context.Context, event *realtime.Event) error {
+	b.mu.Lock()
+	b.published = append(b.published, event)
+	event.ID = fmt.Sprintf("%d-0", len(b.published))
+	handle := b.handle
+	b.mu.Unlock()
+
+	if handle != nil {
+		handle(event)
+	}
+	return nil
+}
+
+func (b *memoryBus) Since(context.Context, int, string) ([]*realtime.Event, error) { return nil, nil }
+
+func (b *memoryBus) Listen(handle func(*realtime.Event)) error {
+	b.mu.Lock()
+	b.handle = handle
+	b.mu.Unlock()
+	close(b.listening)
+
+	<-b.closed
+	return nil
+}
+
+func (b *memoryBus) Close() error {
+	close(b.closed)
+	return nil
+}
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []*notification.Notification
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notif *notification.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if notif.ActorID() != notif.RecipientID() {
+		n.sent = append(n.sent, notif)
+	}
+	return nil
+}
+
+func (n *recordingNotifier) NotifySoundAuthor(context.Context, int, string, int) error { return nil }
+
+type recordingActivities struct {
+	published []string
+}
+
+func (a *recordingActivities) Publish(_ context.Context, actorID int, activityType string, soundID int) error {
+	a.published = append(a.published, fmt.Sprintf("%d:%s:%d", actorID, activityType, soundID))
+	return nil
+}
+
+func newCommentService(t *testing.T) (*CommentService, *memoryComments, *memoryBus) {
+	service, comments, bus, _ := newCommentServiceWithNotifier(t)
+	return service, comments, bus
+}
+
+func newCommentServiceWithNotifier(t *testing.T) (*CommentService, *memoryComments, *memoryBus, *recordingNotifier) {
+	t.Helper()
+
+	comments := newMemoryComments()
+	sounds := &memorySounds{sounds: map[int]*sound.Sound{
+		7: sound.RebuildSoundFromStorage(7, 1, 120, "Song", "", "", "song.mp3", "/uploads/song.mp3", 1024, "mp3", "", "artist", 0, 0, 0),
+	}}
+	bus := newMemoryBus()
+	notifier := &recordingNotifier{}
+	return NewCommentService(comments, sounds, bus, notifier, &recordingActivities{}, testLogger()), comments, bus, notifier
+}
+
+func TestCommentServiceCreatePublishesToSubscribers(t *testing.T) {
+	service, _, bus := newCommentService(t)
+
+	hub := NewRealtimeHub(bus, &config.Realtime{SendBuffer: 4, MaxSubscriptions: 4}, testLogger())
+	go hub.Run()
+	defer hub.Stop()
+	<-bus.listening
+
+	subscriber := hub.Connect(2)
+	if err := hub.Subscribe(subscriber, []int{7}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	other := hub.Connect(3)
+	if err := hub.Subscribe(other, []int{8}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	created, err := service.CreateComment(context.Background(), 5, 7, 0, "  nice drop  ")
+	if err != nil {
+		t.Fatalf("create comment: %v", err)
+	}
+
+	select {
+	case msg := <-subscriber.Messages():
+		var event struct {
+			ID      string             `json:"id"`
+			Type    string             `json:"type"`
+			SoundID int                `json:"sound_id"`
+			Data    comment.CommentDTO `json:"data"`
+		}
+		if err := json.Unmarshal(msg, &event); err != nil {
+			t.Fatalf("decode event: %v", err)
+		}
+		if event.Type != realtime.EventComment || event.SoundID != 7 || event.ID == "" {
+			t.Fatalf("unexpected event %+v", event)
+		}
+		if event.Data.ID != created.ID || event.Data.Content != "nice drop" || event.Data.Author.ID != 5 {
+			t.Fatalf("unexpected event payload %+v", event.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber did not receive the comment event")
+	}
+
+	select {
+	case msg := <-other.Messages():
+		t.Fatalf("client subscribed to another sound got %s", msg)
+	default:
+	}
+}
+
+func TestCommentServiceCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		soundID  int
+		parentID int
+		content  string
+		wantErr  error
+		events   int
+	}{
+		{name: "comment", soundID: 7, content: "first", events: 1},
+		{name: "reply", soundID: 7, parentID: 1, content: "reply", events: 1},
+		{name: "empty content", soundID: 7, content: "   ", wantErr: InvalidComment},
+		{name: "markup", soundID: 7, content: "<script>x</script>", wantErr: InvalidComment},
+		{name: "unknown sound", soundID: 8, content: "hi", wantErr: SoundNotFound},
+		{name: "unknown parent", soundID: 7, parentID: 42, content: "hi", wantErr: CommentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, comments, bus := newCommentService(t)
+			if _, err := comments.CreateComment(context.Background(), mustComment(t, 7, 9, 0, "root")); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := service.CreateComment(context.Background(), 5, tt.soundID, tt.parentID, tt.content)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(bus.published) != tt.events {
+				t.Fatalf("published %d events, want %d", len(bus.published), tt.events)
+			}
+			if activities := service.activities.(*recordingActivities).published; len(activities) != tt.events ||
+				(tt.events == 1 && activities[0] != "5:comment:7") {
+				t.Fatalf("published activities %v", activities)
+			}
+		})
+	}
+}
+
+func TestCommentServiceNotifications(t *testing.T) {
+	type sent struct {
+		recipient int
+		kind      string
+	}
+
+	// Sound 7 belongs to user 1; comment 1 is by user 9 and comment 2 by user 1.
+	tests := []struct {
+		name     string
+		authorID int
+		parentID int
+		want     []sent
+	}{
+		{name: "comment on someone's sound", authorID: 5, want: []sent{{1, notification.TypeComment}}},
+		{name: "comment on own sound", authorID: 1},
+		{name: "reply to a listener", authorID: 5, parentID: 1, want: []sent{{9, notification.TypeReply}, {1, notification.TypeComment}}},
+		{name: "reply to the sound author", authorID: 5, parentID: 2, want: []sent{{1, notification.TypeReply}}},
+		{name: "sound author replies", authorID: 1, parentID: 1, want: []sent{{9, notification.TypeReply}}},
+		{name: "reply to yourself", authorID: 9, parentID: 1, want: []sent{{1, notification.TypeComment}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, comments, _, notifier := newCommentServiceWithNotifier(t)
+			comments.CreateComment(context.Background(), mustComment(t, 7, 9, 0, "listener"))
+			comments.CreateComment(context.Background(), mustComment(t, 7, 1, 0, "author"))
+
+			created, err := service.CreateComment(context.Background(), tt.authorID, 7, tt.parentID, "hello")
+			if err != nil {
+				t.Fatalf("create comment: %v", err)
+			}
+
+			if len(notifier.sent) != len(tt.want) {
+				t.Fatalf("sent %d notifications, want %d", len(notifier.sent), len(tt.want))
+			}
+			for i, n := range notifier.sent {
+				if n.RecipientID() != tt.want[i].recipient || n.Type() != tt.want[i].kind {
+					t.Fatalf("notification %d = %d/%s, want %+v", i, n.RecipientID(), n.Type(), tt.want[i])
+				}
+				if n.CommentID() != created.ID || n.SoundID() != 7 {
+					t.Fatalf("notification %d points at sound %d comment %d", i, n.SoundID(), n.CommentID())
+				}
+			}
+		})
+	}
+}
+
+func TestCommentServiceUpdateOwnership(t *testing.T) {
+	service, comments, _ := newCommentService(t)
+	id, _ := comments.CreateComment(context.Background(), mustComment(t, 7, 5, 0, "mine"))
+
+	if _, err := service.UpdateComment(context.Background(), 1, id, "edited"); !errors.Is(err, CommentForbidden) {
+		t.Fatalf("update by sound author: err = %v, want %v", err, CommentForbidden)
+	}
+
+	updated, err := service.UpdateComment(context.Background(), 5, id, "edited")
+	if err != nil || updated.Content != "edited" {
+		t.Fatalf("update by author: %+v, %v", updated, err)
+	}
+}
+
+func TestCommentServiceDelete(t *testing.T) {
+	// Sound 7 belongs to user 1; the comment is by user 5.
+	tests := []struct {
+		name       string
+		userID     int
+		role       string
+		wantErr    error
+		moderation bool
+	}{
+		{name: "author", userID: 5, role: auth.RoleUser},
+		{name: "sound author", userID: 1, role: auth.RoleUser, moderation: true},
+		{name: "admin", userID: 3, role: auth.RoleAdmin, moderation: true},
+		{name: "stranger", userID: 6, role: auth.RoleUser, wantErr: CommentForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, comments, _, notifier := newCommentServiceWithNotifier(t)
+			id, _ := comments.CreateComment(context.Background(), mustComment(t, 7, 5, 0, "mine"))
+
+			err := service.DeleteComment(context.Background(), tt.userID, tt.role, id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if _, kept := comments.comments[id]; kept != (tt.wantErr != nil) {
+				t.Fatalf("comment kept = %v", kept)
+			}
+
+			if !tt.moderation {
+				if len(notifier.sent) != 0 {
+					t.Fatalf("unexpected notifications %v", notifier.sent)
+				}
+				return
+			}
+			if len(notifier.sent) != 1 {
+				t.Fatalf("sent %d notifications, want 1", len(notifier.sent))
+			}
+			n := notifier.sent[0]
+			if n.Type() != notification.TypeModeration || n.RecipientID() != 5 || n.CommentID() != id || n.Detail() != "removed" {
+				t.Fatalf("unexpected moderation notification %d/%s/%d/%q", n.RecipientID(), n.Type(), n.CommentID(), n.Detail())
+			}
+		})
+	}
+
+	service, _, _ := newCommentService(t)
+	if err := service.DeleteComment(context.Background(), 5, auth.RoleUser, 99); !errors.Is(err, CommentNotFound) {
+		t.Fatalf("missing comment: err = %v, want %v", err, CommentNotFound)
+	}
+}
+
+func mustComment(t *testing.T, soundID, authorID, parentID int, content string) *comment.Comment {
+	t.Helper()
+
+	c, err := comment.NewComment(soundID, authorID, parentID, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
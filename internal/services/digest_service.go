@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"soundtube/internal/domain/notification"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type DigestService struct {
+	repository    notification.IDigestRepository
+	notifications notification.INotificationRepositoryReader
+	email         notification.IDigestEmailSender
+	logger        *pkg.CustomLogger
+
+	secret           []byte
+	publicURL        string
+	interval         time.Duration
+	defaultFrequency string
+	maxItems         int
+	batchSize        int
+
+	done chan struct{}
+}
+
+func NewDigestService(repository notification.IDigestRepository, notifications notification.INotificationRepositoryReader, email notification.IDigestEmailSender,
+	publicURL string, cfg *config.Digest, logger *pkg.CustomLogger) *DigestService {
+	return &DigestService{
+		repository:       repository,
+		notifications:    notifications,
+		email:            email,
+		logger:           logger,
+		secret:           []byte(cfg.UnsubscribeSecret),
+		publicURL:        strings.TrimRight(publicURL, "/"),
+		interval:         cfg.Interval,
+		defaultFrequency: cfg.DefaultFrequency,
+		maxItems:         cfg.MaxItems,
+		batchSize:        cfg.BatchSize,
+		done:             make(chan struct{}),
+	}
+}
+
+// Run sends the digests that became due on every interval until Stop is called.
+func (s *DigestService) Run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sendDue(context.Background())
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *DigestService) Stop() {
+	close(s.done)
+}
+
+// sendDue claims one batch per tick, so every digest goes out from a single instance.
+// Users left over are picked up on the next tick; a failed digest is retried with
+// exponential backoff.
+func (s *DigestService) sendDue(ctx context.Context) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "DigestService.sendDue")
+	defer span.End()
+
+	recipients, err := s.repository.ClaimDueDigests(ctx, s.defaultFrequency, s.batchSize, notification.DigestClaimLease)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return
+	}
+
+	sent := 0
+	for _, recipient := range recipients {
+		if err := s.sendDigest(ctx, recipient); err != nil {
+			s.logger.WarnContext(ctx, "failed to send digest", err)
+			retryAfter := notification.DigestRetryDelay(recipient.Failures + 1)
+			if err := s.repository.MarkDigestFailed(ctx, recipient.UserID, retryAfter); err != nil {
+				s.logger.ErrorContext(ctx, "db error", err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	if len(recipients) > 0 {
+		s.logger.InfoContext(ctx, "digests sent", "sent", sent, "due", len(recipients))
+	}
+}
+
+func (s *DigestService) sendDigest(ctx context.Context, recipient *notification.DigestRecipient) error {
+	unread, err := s.notifications.GetNotifications(ctx, recipient.UserID, true, s.maxItems+1, 0)
+	if err != nil {
+		return err
+	}
+
+	var items []*notification.Notification
+	for _, n := range unread {
+		if n.UpdatedAt().After(recipient.Since) {
+			items = append(items, n)
+		}
+	}
+	if len(items) == 0 {
+		return s.repository.MarkDigestSent(ctx, recipient.UserID)
+	}
+
+	more := 0
+	if len(items) > s.maxItems {
+		total, err := s.notifications.CountUnread(ctx, recipient.UserID)
+		if err != nil {
+			return err
+		}
+		items = items[:s.maxItems]
+		more = max(total-s.maxItems, 0)
+	}
+
+	digest := &notification.Digest{
+		Username:  recipient.Username,
+		Frequency: recipient.Frequency,
+		Items:     items,
+		More:      more,
+		Link:      s.publicURL + "/",
+	}
+
+	if err := s.email.SendDigestEmail(ctx, recipient.Email, recipient.Locale, digest, s.UnsubscribeURL(recipient.UserID)); err != nil {
+		return err
+	}
+
+	return s.repository.MarkDigestSent(ctx, recipient.UserID)
+}
+
+func (s *DigestService) GetSettings(ctx context.Context, userID int) (*notification.DigestSettingsDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "DigestService.GetSettings")
+	defer span.End()
+
+	settings, err := s.repository.GetDigestSettings(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return s.toDTO(settings), nil
+}
+
+func (s *DigestService) UpdateSettings(ctx context.Context, userID int, frequency, locale string) (*notification.DigestSettingsDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "DigestService.UpdateSettings")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("user.id", userID),
+		attribute.String("digest.frequency", frequency),
+	)
+
+	if locale != "" && !s.email.SupportsLocale(locale) {
+		return nil, fmt.Errorf("%w: unsupported locale %q", InvalidDigestSettings, locale)
+	}
+
+	settings, err := notification.NewDigestSettings(userID, frequency, locale)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid digest settings", err)
+		return nil, fmt.Errorf("%w: %v", InvalidDigestSettings, err)
+	}
+
+	if err := s.repository.SaveDigestSettings(ctx, settings); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return s.toDTO(settings), nil
+}
+
+// Unsubscribe turns digests off for the user the signed token was issued to.
+func (s *DigestService) Unsubscribe(ctx context.Context, token string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "DigestService.Unsubscribe")
+	defer span.End()
+
+	userID, err := s.VerifyUnsubscribeToken(token)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid unsubscribe token", err)
+		return err
+	}
+
+	settings, err := s.repository.GetDigestSettings(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	unsubscribed, err := notification.NewDigestSettings(userID, notification.FrequencyOff, settings.Locale())
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.SaveDigestSettings(ctx, unsubscribed); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "user unsubscribed from digests", "user_id", userID)
+	return nil
+}
+
+// UnsubscribeURL is the absolute one-click link put into digest emails. It does not
+// expire so that old emails keep working.
+func (s *DigestService) UnsubscribeURL(userID int) string {
+	return s.publicURL + "/api/email/unsubscribe?token=" + url.QueryEscape(s.unsubscribeToken(userID))
+}
+
+func (s *DigestService) VerifyUnsubscribeToken(token string) (int, error) {
+	rawID, _, found := strings.Cut(token, ".")
+	if !found {
+		return 0, UnsubscribeLinkInvalid
+	}
+
+	userID, err := strconv.Atoi(rawID)
+	if err != nil || userID <= 0 {
+		return 0, UnsubscribeLinkInvalid
+	}
+
+	if !hmac.Equal([]byte(token), []byte(s.unsubscribeToken(userID))) {
+		return 0, UnsubscribeLinkInvalid
+	}
+
+	return userID, nil
+}
+
+func (s *DigestService) unsubscribeToken(userID int) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("unsubscribe|" + strconv.Itoa(userID)))
+	return strconv.Itoa(userID) + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *DigestService) toDTO(settings *notification.DigestSettings) *notification.DigestSettingsDTO {
+	dto := &notification.DigestSettingsDTO{Frequency: settings.Frequency(), Locale: settings.Locale()}
+	if dto.Frequency == "" {
+		dto.Frequency = s.defaultFrequency
+	}
+	return dto
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"soundtube/internal/domain/notification"
+	"soundtube/pkg/config"
+	"strings"
+	"testing"
+	"time"
+)
+
+type memoryDigests struct {
+	due      []*notification.DigestRecipient
+	lease    time.Duration
+	sent     []int
+	failed   map[int]time.Duration
+	settings map[int]*notification.DigestSettings
+}
+
+func (r *memoryDigests) GetDigestSettings(_ context.Context, userID int) (*notification.DigestSettings, error) {
+	if s, ok := r.settings[userID]; ok {
+		return s, nil
+	}
+	return notification.RebuildDigestSettingsFromStorage(userID, "", ""), nil
+}
+
+func (r *memoryDigests) SaveDigestSettings(_ context.Context, settings *notification.DigestSettings) error {
+	r.settings[settings.UserID()] = settings
+	return nil
+}
+
+// ClaimDueDigests hands every due recipient out once, like the row claim does across instances.
+func (r *memoryDigests) ClaimDueDigests(_ context.Context, _ string, limit int, lease time.Duration) ([]*notification.DigestRecipient, error) {
+	r.lease = lease
+	n := min(limit, len(r.due))
+	claimed := r.due[:n]
+	r.due = r.due[n:]
+	return claimed, nil
+}
+
+func (r *memoryDigests) MarkDigestSent(_ context.Context, userID int) error {
+	r.sent = append(r.sent, userID)
+	return nil
+}
+
+func (r *memoryDigests) MarkDigestFailed(_ context.Context, userID int, retryAfter time.Duration) error {
+	r.failed[userID] = retryAfter
+	return nil
+}
+
+type unreadNotifications struct {
+	notification.INotificationRepositoryReader
+}
+
+func (unreadNotifications) GetNotifications(_ context.Context, userID int, _ bool, _, _ int) ([]*notification.Notification, error) {
+	return []*notification.Notification{
+		notification.RebuildNotificationFromStorage(1, userID, 2, "bob", 1, notification.TypeLike, 3, "Intro", 0, "", false, time.Now()),
+	}, nil
+}
+
+func (unreadNotifications) CountUnread(context.Context, int) (int, error) { return 1, nil }
+
+type fakeDigestEmail struct {
+	failing map[string]bool
+	links   []string
+}
+
+func (e *fakeDigestEmail) SupportsLocale(locale string) bool { return locale == "en" }
+
+func (e *fakeDigestEmail) SendDigestEmail(_ context.Context, email, _ string, digest *notification.Digest, unsubscribeURL string) error {
+	if e.failing[email] {
+		return errors.New("smtp unavailable")
+	}
+	e.links = append(e.links, digest.Link, unsubscribeURL)
+	return nil
+}
+
+func newDigestService(repo *memoryDigests, email *fakeDigestEmail) *DigestService {
+	cfg := &config.Digest{
+		Interval:          time.Minute,
+		DefaultFrequency:  notification.FrequencyWeekly,
+		MaxItems:          10,
+		BatchSize:         10,
+		UnsubscribeSecret: "digest-unsubscribe-secret",
+	}
+	return NewDigestService(repo, unreadNotifications{}, email, "https://soundtube.example/", cfg, testLogger())
+}
+
+func TestDigestServiceSendDue(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		failing   bool
+		wantSent  bool
+		wantRetry time.Duration
+	}{
+		{name: "sent", wantSent: true},
+		{name: "first failure", failing: true, wantRetry: 15 * time.Minute},
+		{name: "repeated failure backs off", failures: 3, failing: true, wantRetry: 2 * time.Hour},
+		{name: "success after failures", failures: 3, wantSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryDigests{
+				due: []*notification.DigestRecipient{
+					{UserID: 7, Email: "alice@example.com", Username: "alice", Frequency: notification.FrequencyWeekly, Failures: tt.failures},
+				},
+				failed: map[int]time.Duration{},
+			}
+			email := &fakeDigestEmail{failing: map[string]bool{"alice@example.com": tt.failing}}
+			s := newDigestService(repo, email)
+
+			s.sendDue(context.Background())
+
+			if repo.lease != notification.DigestClaimLease {
+				t.Fatalf("lease = %s, want %s", repo.lease, notification.DigestClaimLease)
+			}
+			if sent := len(repo.sent) == 1; sent != tt.wantSent {
+				t.Fatalf("sent = %v, want %v", repo.sent, tt.wantSent)
+			}
+			if repo.failed[7] != tt.wantRetry {
+				t.Fatalf("retry after = %s, want %s", repo.failed[7], tt.wantRetry)
+			}
+
+			s.sendDue(context.Background())
+			if len(repo.sent)+len(repo.failed) != 1 {
+				t.Fatalf("digest handled more than once: sent %v, failed %v", repo.sent, repo.failed)
+			}
+		})
+	}
+}
+
+func TestDigestServiceLinksAreAbsolute(t *testing.T) {
+	repo := &memoryDigests{
+		due:    []*notification.DigestRecipient{{UserID: 7, Email: "alice@example.com", Username: "alice"}},
+		failed: map[int]time.Duration{},
+	}
+	email := &fakeDigestEmail{}
+	newDigestService(repo, email).sendDue(context.Background())
+
+	if len(email.links) != 2 {
+		t.Fatalf("links = %v", email.links)
+	}
+	for _, link := range email.links {
+		u, err := url.Parse(link)
+		if err != nil || u.Scheme != "https" || u.Host != "soundtube.example" {
+			t.Fatalf("link %q is not an absolute https URL", link)
+		}
+	}
+	if !strings.HasPrefix(email.links[1], "https://soundtube.example/api/email/unsubscribe?token=") {
+		t.Fatalf("unsubscribe link = %q", email.links[1])
+	}
+}
+
+func TestDigestServiceUnsubscribeToken(t *testing.T) {
+	s := newDigestService(&memoryDigests{}, &fakeDigestEmail{})
+	other := NewDigestService(&memoryDigests{}, unreadNotifications{}, &fakeDigestEmail{}, "https://soundtube.example",
+		&config.Digest{UnsubscribeSecret: "another-unsubscribe-secret"}, testLogger())
+
+	token := s.unsubscribeToken(7)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantID  int
+		wantErr error
+	}{
+		{name: "valid", token: token, wantID: 7},
+		{name: "other user id", token: "8" + token[1:], wantErr: UnsubscribeLinkInvalid},
+		{name: "signed with another secret", token: other.unsubscribeToken(7), wantErr: UnsubscribeLinkInvalid},
+		{name: "no signature", token: "7", wantErr: UnsubscribeLinkInvalid},
+		{name: "not a user id", token: "x." + strings.Repeat("0", 64), wantErr: UnsubscribeLinkInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := s.VerifyUnsubscribeToken(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if id != tt.wantID {
+				t.Fatalf("id = %d, want %d", id, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestDigestServiceUnsubscribe(t *testing.T) {
+	repo := &memoryDigests{settings: map[int]*notification.DigestSettings{
+		7: notification.RebuildDigestSettingsFromStorage(7, notification.FrequencyDaily, "en"),
+	}}
+	s := newDigestService(repo, &fakeDigestEmail{})
+
+	if err := s.Unsubscribe(context.Background(), s.unsubscribeToken(7)); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+
+	settings := repo.settings[7]
+	if settings.Frequency() != notification.FrequencyOff || settings.Locale() != "en" {
+		t.Fatalf("settings = %q/%q, want off/en", settings.Frequency(), settings.Locale())
+	}
+}
@@ -7,137 +7,165 @@ import (
 	"errors"
 	"fmt"
 	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/mail"
+	"soundtube/internal/domain/notification"
 	"soundtube/pkg"
-	"soundtube/pkg/config"
-	"strconv"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
-	"gopkg.in/gomail.v2"
 )
 
+// EmailService renders outgoing emails and hands them to the outbox; delivery happens
+// in OutboxService.
 type EmailService struct {
 	logger     *pkg.CustomLogger
 	repository auth.IUserRepository
-	dialer     *gomail.Dialer
+	outbox     mail.IOutbox
+	templates  atomic.Pointer[EmailTemplates]
 	addr       string
-	from       string
 }
 
-func NewEmailService(repositoory auth.IUserRepository, fullAddr string, cfg *config.Email, logger *pkg.CustomLogger) *EmailService {
-	var port, _ = strconv.Atoi(cfg.SMTPort)
-	var dialer = gomail.NewDialer(cfg.SMTHost, port, cfg.Username, cfg.Password)
-
-	return &EmailService{
+func NewEmailService(repositoory auth.IUserRepository, outbox mail.IOutbox, fullAddr string, templates *EmailTemplates, logger *pkg.CustomLogger) *EmailService {
+	service := &EmailService{
 		repository: repositoory,
 		logger:     logger,
-		dialer:     dialer,
+		outbox:     outbox,
 		addr:       fullAddr,
-		from:       cfg.From,
 	}
+	service.templates.Store(templates)
+
+	return service
+}
+
+// SetTemplates switches to reloaded templates; messages being rendered finish with the old ones.
+func (s *EmailService) SetTemplates(templates *EmailTemplates) {
+	s.templates.Store(templates)
 }
 
-func (s *EmailService) SendVerificationEmail(ctx context.Context, email, verifyToken string) error {
-	ctx, span := s.logger.GetTracer().Start(ctx, "EmailService.SendVerificationEmail")
+// VerificationEmail builds the verification message. It is stored together with the
+// new user rather than enqueued separately.
+func (s *EmailService) VerificationEmail(ctx context.Context, email, verifyToken string) (*mail.Message, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "EmailService.VerificationEmail")
 	defer span.End()
 
 	span.SetAttributes(
 		attribute.String("email", email),
-		attribute.String("token", verifyToken),
 	)
 
 	verifyLink := fmt.Sprintf(s.addr+"/api/auth"+"/verify-email?token=%s", verifyToken)
 
-	s.logger.Info("created link ni email service: ", verifyLink).WithTrace(ctx)
-
-	htmlBody := fmt.Sprintf(`
-		<!DOCTYPE html>
-		<html>
-		<head>
-			<meta charset="UTF-8">
-			<title>Verify Your Email</title>
-		</head>
-		<body>
-			<h2>Email Verification</h2>
-			<p>Hello,</p>
-			<p>Please verify your email address by clicking the button below:</p>
-			<p>
-				<a href="%s" style="
-					background-color: #007bff; 
-					color: white; 
-					padding: 12px 24px; 
-					text-decoration: none; 
-					border-radius: 4px; 
-					display: inline-block;
-				">Verify Email</a>
-			</p>
-			<p>Or copy and paste this link in your browser:</p>
-			<p>%s</p>
-			<p>If you didn't create an account, please ignore this email.</p>
-			<br>
-			<p>Best regards,<br>Your App Team</p>
-		</body>
-		</html>
-	`, verifyLink, verifyLink)
-
-	textBody := fmt.Sprintf(`
-		Verify Your Email Address
-		
-		Please verify your email address by visiting the following link:
-		%s
-		
-		If you didn't create an account, please ignore this email.
-		
-		Best regards,
-		Your App Team
-	`, verifyLink)
-
-	messege := gomail.NewMessage()
-	messege.SetHeader("From", s.from)
-	messege.SetHeader("To", email)
-	messege.SetHeader("Subject", "Verify your email address")
-
-	messege.SetBody("text/html", htmlBody)
-
-	messege.AddAlternative("text/plain", textBody)
-
-	if err := s.dialer.DialAndSend(messege); err != nil {
-		s.logger.Error("failed to send verification email", err).WithTrace(ctx)
+	msg, err := s.compose(email, "", "verify", map[string]string{"Link": verifyLink}, "", nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to build verification email", err)
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func (s *EmailService) SendExportEmail(ctx context.Context, email, downloadLink string, expiresAt time.Time) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "EmailService.SendExportEmail")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("email", email),
+	)
+
+	data := map[string]string{
+		"Link":    downloadLink,
+		"Expires": expiresAt.UTC().Format(time.RFC1123),
+	}
+
+	msg, err := s.compose(email, "", "export", data, "", nil)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to build export email", err)
+		return err
+	}
+
+	if err := s.outbox.Enqueue(ctx, msg); err != nil {
+		s.logger.ErrorContext(ctx, "failed to enqueue export email", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "export email queued", "email", email)
+	return nil
+}
+
+func (s *EmailService) SupportsLocale(locale string) bool {
+	return s.templates.Load().HasLocale(locale)
+}
+
+// SendDigestEmail sends a notification digest. The unsubscribe link is also announced
+// in List-Unsubscribe headers so mail clients can offer one-click unsubscribe.
+func (s *EmailService) SendDigestEmail(ctx context.Context, email, locale string, digest *notification.Digest, unsubscribeURL string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "EmailService.SendDigestEmail")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("email", email),
+		attribute.String("locale", locale),
+	)
+
+	headers := map[string][]string{
+		"List-Unsubscribe":      {"<" + unsubscribeURL + ">"},
+		"List-Unsubscribe-Post": {"List-Unsubscribe=One-Click"},
+	}
+
+	msg, err := s.compose(email, locale, "digest", digest, unsubscribeURL, headers)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to build digest email", err)
+		return err
+	}
+
+	if err := s.outbox.Enqueue(ctx, msg); err != nil {
+		s.logger.ErrorContext(ctx, "failed to enqueue digest email", err)
 		return err
 	}
 
-	s.logger.Info("sending verify email", "email", email, "link", verifyLink).WithTrace(ctx)
+	s.logger.InfoContext(ctx, "digest email queued", "email", email)
 	return nil
 }
 
+func (s *EmailService) compose(to, locale, template string, data any, unsubscribeURL string, headers map[string][]string) (*mail.Message, error) {
+	// An empty or untranslated locale renders in the default locale.
+	rendered, err := s.templates.Load().Render(locale, template, data, unsubscribeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return mail.NewMessage(to, rendered.Subject, rendered.HTML, rendered.Text, headers)
+}
+
 func (s *EmailService) VerifyEmail(ctx context.Context, token string) error {
 	_, span := s.logger.GetTracer().Start(ctx, "EmailService.VerifyEmail")
 	defer span.End()
 
 	user, err := s.repository.GetUserByToken(ctx, token)
 	if err != nil {
-		s.logger.Error("db error", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "db error", err)
 		return err
 	}
 
 	if user == nil {
 		err = errors.New("user not found")
-		s.logger.Error("incorrect user", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "incorrect user", err)
 		return err
 	}
 
 	if user.IsVerified() {
 		err = errors.New("user already verified")
-		s.logger.Error("incorrect user", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "incorrect user", err)
 		return err
 	}
 
 	err = s.repository.MarkUserAsVerified(ctx, user.ID())
 	if err != nil {
-		s.logger.Error("db error", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "db error", err)
 		return err
 	}
 
-	s.logger.Info("verify for user ", user.ID(), " is competed").WithTrace(ctx)
+	s.logger.InfoContext(ctx, "verify for user ", user.ID(), " is competed")
 
 	return nil
 }
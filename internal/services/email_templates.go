@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/email
+var emailTemplateFS embed.FS
+
+const emailTemplateDir = "templates/email"
+
+// RenderedEmail is a message body with its plain text alternative.
+type RenderedEmail struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// emailData is what every template is executed with. Layout partials read the top
+// level fields, message templates read Data.
+type emailData struct {
+	UnsubscribeURL string
+	Data           any
+}
+
+// EmailTemplates holds the parsed message templates of every locale. Each message
+// is a "<name>.html.tmpl" / "<name>.txt.tmpl" pair in the locale directory, wrapped in
+// the shared layouts together with the locale's footer partial.
+type EmailTemplates struct {
+	defaultLocale string
+	html          map[string]*htmltemplate.Template
+	text          map[string]*texttemplate.Template
+}
+
+// NewEmailTemplates parses the templates in dir, or the embedded ones when dir is empty.
+func NewEmailTemplates(dir, defaultLocale string) (*EmailTemplates, error) {
+	t := &EmailTemplates{
+		defaultLocale: defaultLocale,
+		html:          make(map[string]*htmltemplate.Template),
+		text:          make(map[string]*texttemplate.Template),
+	}
+
+	var templateFS fs.FS = emailTemplateFS
+	var root = emailTemplateDir
+	if dir != "" {
+		templateFS, root = os.DirFS(dir), "."
+	}
+
+	locales, err := fs.ReadDir(templateFS, root)
+	if err != nil {
+		return nil, fmt.Errorf("read email templates %s: %w", dir, err)
+	}
+
+	for _, locale := range locales {
+		if !locale.IsDir() {
+			continue
+		}
+
+		dir := path.Join(root, locale.Name())
+		files, err := fs.ReadDir(templateFS, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			name, ok := strings.CutSuffix(file.Name(), ".html.tmpl")
+			if !ok || name == "footer" {
+				continue
+			}
+
+			key := locale.Name() + "/" + name
+
+			t.html[key], err = htmltemplate.ParseFS(templateFS,
+				path.Join(root, "layout.html.tmpl"), path.Join(dir, "footer.html.tmpl"), path.Join(dir, file.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("parse %s html template: %w", key, err)
+			}
+
+			t.text[key], err = texttemplate.ParseFS(templateFS,
+				path.Join(root, "layout.txt.tmpl"), path.Join(dir, "footer.txt.tmpl"), path.Join(dir, name+".txt.tmpl"))
+			if err != nil {
+				return nil, fmt.Errorf("parse %s text template: %w", key, err)
+			}
+		}
+	}
+
+	if !t.HasLocale(defaultLocale) {
+		return nil, fmt.Errorf("no email templates for default locale %q", defaultLocale)
+	}
+
+	return t, nil
+}
+
+func (t *EmailTemplates) HasLocale(locale string) bool {
+	for key := range t.html {
+		if strings.HasPrefix(key, locale+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Render executes message name in locale, falling back to the default locale when the
+// message is not translated.
+func (t *EmailTemplates) Render(locale, name string, data any, unsubscribeURL string) (*RenderedEmail, error) {
+	key := locale + "/" + name
+	if _, exists := t.html[key]; !exists {
+		key = t.defaultLocale + "/" + name
+	}
+
+	html, exists := t.html[key]
+	if !exists {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+	text := t.text[key]
+
+	input := emailData{UnsubscribeURL: unsubscribeURL, Data: data}
+
+	var subject, htmlBody, textBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", input); err != nil {
+		return nil, err
+	}
+	if err := html.ExecuteTemplate(&htmlBody, "layout", input); err != nil {
+		return nil, err
+	}
+	if err := text.ExecuteTemplate(&textBody, "layout", input); err != nil {
+		return nil, err
+	}
+
+	return &RenderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    htmlBody.String(),
+		Text:    strings.TrimSpace(textBody.String()),
+	}, nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// copyEmailTemplates writes the embedded templates to a directory, as an operator would
+// when customising them.
+func copyEmailTemplates(t *testing.T) string {
+	t.Helper()
+
+	sub, err := fs.Sub(emailTemplateFS, emailTemplateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "email")
+	if err := os.CopyFS(dir, sub); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestNewEmailTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     func(t *testing.T) string
+		locale  string
+		wantErr bool
+	}{
+		{name: "embedded", dir: func(t *testing.T) string { return "" }, locale: "en"},
+		{name: "directory", dir: copyEmailTemplates, locale: "ru"},
+		{name: "missing directory", dir: func(t *testing.T) string { return filepath.Join(t.TempDir(), "nope") }, locale: "en", wantErr: true},
+		{name: "unknown default locale", dir: func(t *testing.T) string { return "" }, locale: "de", wantErr: true},
+		{name: "broken template", dir: func(t *testing.T) string {
+			dir := copyEmailTemplates(t)
+			if err := os.WriteFile(filepath.Join(dir, "en", "verify.html.tmpl"), []byte("{{define \"content\"}}{{.Data.Link"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			return dir
+		}, locale: "en", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEmailTemplates(tt.dir(t), tt.locale)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEmailTemplatesReadEditedFiles(t *testing.T) {
+	dir := copyEmailTemplates(t)
+	edited := "{{define \"subject\"}}Confirm your address{{end}}\n{{define \"content\"}}{{.Data.Link}}{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, "en", "verify.txt.tmpl"), []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := NewEmailTemplates(dir, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		locale      string
+		wantSubject string
+	}{
+		{locale: "en", wantSubject: "Confirm your address"},
+		{locale: "fr", wantSubject: "Confirm your address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			email, err := templates.Render(tt.locale, "verify", map[string]string{"Link": "https://example.com/verify"}, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if email.Subject != tt.wantSubject {
+				t.Fatalf("subject = %q, want %q", email.Subject, tt.wantSubject)
+			}
+			if !strings.Contains(email.Text, "https://example.com/verify") {
+				t.Fatalf("text = %q", email.Text)
+			}
+		})
+	}
+}
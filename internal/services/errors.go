@@ -4,4 +4,39 @@ import "errors"
 
 var (
 	UserAlreadyExits = errors.New("user already exists")
+	UserNotFound     = errors.New("user not found")
+	SoundNotFound    = errors.New("sound not found")
+	RepostOwnSound   = errors.New("cannot repost your own sound")
+	AlbumNotFound    = errors.New("album not found")
+	AlbumForbidden   = errors.New("not allowed to modify this album")
+
+	CommentNotFound  = errors.New("comment not found")
+	CommentForbidden = errors.New("not allowed to modify this comment")
+	InvalidComment   = errors.New("invalid comment")
+
+	PlaylistNotFound          = errors.New("playlist not found")
+	PlaylistForbidden         = errors.New("not allowed to modify this playlist")
+	PlaylistOwnerCollaborator = errors.New("owner is already able to edit the playlist")
+	TrackNotFound             = errors.New("track not found")
+
+	ExportQueueFull   = errors.New("export queue is full, try again later")
+	ExportLinkInvalid = errors.New("export link is invalid")
+	ExportLinkExpired = errors.New("export link has expired")
+
+	InvalidSearchQuery = errors.New("invalid search query")
+	InvalidSoundQuery  = errors.New("invalid sound query")
+	InvalidListenEvent = errors.New("invalid listening event")
+	InvalidChartQuery  = errors.New("invalid chart query")
+
+	TooManySubscriptions = errors.New("too many subscriptions")
+	InvalidLastEventID   = errors.New("invalid Last-Event-ID")
+
+	NotificationNotFound    = errors.New("notification not found")
+	InvalidNotificationType = errors.New("invalid notification type")
+
+	InvalidDigestSettings  = errors.New("invalid digest settings")
+	UnsubscribeLinkInvalid = errors.New("unsubscribe link is invalid")
+
+	FeatureFlagNotFound = errors.New("feature flag override not found")
+	InvalidFeatureFlag  = errors.New("invalid feature flag")
 )
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/comment"
+	"soundtube/internal/domain/export"
+	"soundtube/internal/domain/sound"
+	"soundtube/internal/repositories"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"soundtube/scripts"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ExportService struct {
+	users        auth.IUserRepositoryReader
+	sounds       sound.ISoundRepositoryReader
+	participants *repositories.SoundPartisipantsRepository
+	comments     comment.ICommentRepositoryReader
+	sessions     auth.ISessionRepository
+	email        export.IExportEmailSender
+	logger       *pkg.CustomLogger
+
+	secret    []byte
+	publicURL string
+	dir       string
+	staticDir string
+	ttl       time.Duration
+
+	jobs     chan int
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewExportService(users auth.IUserRepositoryReader, sounds sound.ISoundRepositoryReader, participants *repositories.SoundPartisipantsRepository,
+	comments comment.ICommentRepositoryReader, sessions auth.ISessionRepository, email export.IExportEmailSender,
+	publicURL, staticDir string, cfg *config.Export, logger *pkg.CustomLogger) *ExportService {
+	return &ExportService{
+		users:        users,
+		sounds:       sounds,
+		participants: participants,
+		comments:     comments,
+		sessions:     sessions,
+		email:        email,
+		logger:       logger,
+		secret:       []byte(cfg.Secret),
+		publicURL:    strings.TrimRight(publicURL, "/"),
+		dir:          cfg.Dir,
+		staticDir:    staticDir,
+		ttl:          cfg.LinkTTL,
+		jobs:         make(chan int, cfg.QueueSize),
+		done:         make(chan struct{}),
+	}
+}
+
+// RequestExport queues an archive job for the user. The archive is assembled in the
+// background and the download link is delivered by email.
+func (s *ExportService) RequestExport(ctx context.Context, userID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ExportService.RequestExport")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("user.id", userID),
+	)
+
+	select {
+	case s.jobs <- userID:
+		s.logger.InfoContext(ctx, "export job queued", "user_id", userID)
+		return nil
+	default:
+		s.logger.WarnContext(ctx, "export job rejected", ExportQueueFull)
+		return ExportQueueFull
+	}
+}
+
+// Run processes queued export jobs until Stop is called.
+func (s *ExportService) Run() {
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case userID := <-s.jobs:
+			if err := s.buildExport(context.Background(), userID); err != nil {
+				s.logger.Error("export job failed", err)
+			}
+		case <-cleanup.C:
+			s.removeExpired()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *ExportService) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+// HealthCheck fails while the job queue is full and new exports are being rejected.
+func (s *ExportService) HealthCheck(ctx context.Context) error {
+	if len(s.jobs) == cap(s.jobs) {
+		return ExportQueueFull
+	}
+	return nil
+}
+
+// ResolveDownload checks the signed link and returns the path of the archive on disk.
+func (s *ExportService) ResolveDownload(ctx context.Context, archiveID, expires, signature string) (string, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ExportService.ResolveDownload")
+	defer span.End()
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid export link expiration", err)
+		return "", ExportLinkInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(archiveID, expiresUnix))) {
+		s.logger.WarnContext(ctx, "invalid export link signature", ExportLinkInvalid)
+		return "", ExportLinkInvalid
+	}
+
+	if time.Now().After(time.Unix(expiresUnix, 0)) {
+		s.logger.WarnContext(ctx, "expired export link", ExportLinkExpired)
+		return "", ExportLinkExpired
+	}
+
+	path := filepath.Join(s.dir, archiveID+".zip")
+	if _, err := os.Stat(path); err != nil {
+		s.logger.ErrorContext(ctx, "export archive not found", err)
+		return "", ExportLinkExpired
+	}
+
+	return path, nil
+}
+
+func (s *ExportService) buildExport(ctx context.Context, userID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ExportService.buildExport")
+	defer span.End()
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if user == nil {
+		err = errors.New("user not found")
+		s.logger.ErrorContext(ctx, "invalid user id", err)
+		return err
+	}
+
+	archive, err := export.NewArchive(scripts.GenerateUUID(), userID, s.ttl)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "invalid archive params", err)
+		return err
+	}
+
+	if err = os.MkdirAll(s.dir, 0700); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create export directory", err)
+		return err
+	}
+
+	path := filepath.Join(s.dir, archive.FileName())
+	if err = s.writeArchive(ctx, path, user); err != nil {
+		os.Remove(path)
+		s.logger.ErrorContext(ctx, "failed to write export archive", err)
+		return err
+	}
+
+	link := s.downloadLink(archive)
+
+	if err = s.email.SendExportEmail(ctx, user.Email(), link, archive.ExpiresAt()); err != nil {
+		s.logger.ErrorContext(ctx, "export email failed", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "export archive created", "user_id", userID, "archive_id", archive.ID())
+	return nil
+}
+
+func (s *ExportService) writeArchive(ctx context.Context, path string, user *auth.User) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+
+	profile := export.ProfileData{
+		ID:         user.ID(),
+		Username:   user.Username(),
+		Email:      user.Email(),
+		IsVerified: user.IsVerified(),
+		IsBanned:   user.IsBanned(),
+	}
+	if err = writeJSON(zw, "profile.json", profile); err != nil {
+		return err
+	}
+
+	sounds, err := s.sounds.GetSoundsByAuthor(ctx, user.ID())
+	if err != nil {
+		return err
+	}
+	if err = writeJSON(zw, "sounds.json", sound.SoundsToDTO(sounds)); err != nil {
+		return err
+	}
+
+	for _, snd := range sounds {
+		if snd.FilePath() == "" {
+			continue
+		}
+		if err = s.copyAudio(zw, snd); err != nil {
+			s.logger.WarnContext(ctx, "failed to add audio file to export", err)
+		}
+	}
+
+	participants, err := s.participants.GetByUser(ctx, user.ID())
+	if err != nil {
+		return err
+	}
+	reactions := make([]export.ReactionData, 0, len(participants))
+	for _, p := range participants {
+		reactions = append(reactions, export.ReactionData{SoundID: p.SoundID, ReactType: p.ReactType, CreatedAt: p.CreatedAt})
+	}
+	if err = writeJSON(zw, "reactions.json", reactions); err != nil {
+		return err
+	}
+
+	comments, err := s.comments.GetCommentsByAuthor(ctx, user.ID())
+	if err != nil {
+		return err
+	}
+	if err = writeJSON(zw, "comments.json", exportComments(comments)); err != nil {
+		return err
+	}
+
+	sessions, err := s.sessions.GetSessionsByUser(ctx, user.ID())
+	if err != nil {
+		return err
+	}
+	if err = writeJSON(zw, "sessions.json", exportSessions(sessions)); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func (s *ExportService) copyAudio(zw *zip.Writer, snd *sound.Sound) error {
+	src, err := os.Open(filepath.Join(s.staticDir, snd.FilePath()))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(filepath.ToSlash(filepath.Join("audio", filepath.Base(snd.FilePath()))))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func (s *ExportService) removeExpired() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < s.ttl {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil {
+			s.logger.Warn("failed to remove expired export", err)
+		}
+	}
+}
+
+func (s *ExportService) downloadLink(archive *export.Archive) string {
+	expiresUnix := archive.ExpiresAt().Unix()
+	return fmt.Sprintf("%s/api/exports/%s?expires=%d&signature=%s", s.publicURL, archive.ID(), expiresUnix, s.sign(archive.ID(), expiresUnix))
+}
+
+func (s *ExportService) sign(archiveID string, expiresUnix int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(archiveID + "|" + strconv.FormatInt(expiresUnix, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func exportComments(comments []*comment.Comment) []export.CommentData {
+	data := make([]export.CommentData, 0, len(comments))
+	for _, c := range comments {
+		data = append(data, export.CommentData{
+			ID:        c.ID(),
+			SoundID:   c.SoundID(),
+			ParentID:  c.ParentID(),
+			Content:   c.Content(),
+			CreatedAt: c.CreatedAt(),
+			UpdatedAt: c.UpdatedAt(),
+		})
+	}
+	return data
+}
+
+func exportSessions(sessions []*auth.Session) []export.SessionData {
+	data := make([]export.SessionData, 0, len(sessions))
+	for _, session := range sessions {
+		item := export.SessionData{ID: session.ID(), CreatedAt: session.CreatedAt(), ExpiresAt: session.ExpiresAt()}
+		if ended := session.EndedAt(); !ended.IsZero() {
+			item.EndedAt = &ended
+		}
+		data = append(data, item)
+	}
+	return data
+}
+
+func writeJSON(zw *zip.Writer, name string, value any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/comment"
+	"soundtube/internal/domain/export"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testLogger() *pkg.CustomLogger {
+	return pkg.NewLogger(slog.New(slog.DiscardHandler), false)
+}
+
+func newExportService(t *testing.T, publicURL, secret string) *ExportService {
+	t.Helper()
+
+	cfg := &config.Export{Dir: t.TempDir(), LinkTTL: time.Hour, QueueSize: 1, Secret: secret}
+	return NewExportService(nil, nil, nil, nil, nil, nil, publicURL, "", cfg, testLogger())
+}
+
+// resolveLink feeds the parts of a download link back to ResolveDownload.
+func resolveLink(s *ExportService, link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	return s.ResolveDownload(context.Background(), path.Base(u.Path), u.Query().Get("expires"), u.Query().Get("signature"))
+}
+
+func TestExportServiceDownloadLink(t *testing.T) {
+	tests := []struct {
+		name      string
+		publicURL string
+		want      string
+	}{
+		{name: "https", publicURL: "https://soundtube.example", want: "https://soundtube.example/api/exports/"},
+		{name: "trailing slash", publicURL: "https://soundtube.example/", want: "https://soundtube.example/api/exports/"},
+		{name: "path prefix", publicURL: "http://localhost:8080/tube", want: "http://localhost:8080/tube/api/exports/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newExportService(t, tt.publicURL, "export-secret-0123")
+			archive, _ := export.NewArchive("abc", 1, time.Hour)
+
+			link := s.downloadLink(archive)
+			if !strings.HasPrefix(link, tt.want+"abc?") {
+				t.Fatalf("link = %q, want prefix %q", link, tt.want)
+			}
+			if u, err := url.Parse(link); err != nil || !u.IsAbs() {
+				t.Fatalf("link %q is not absolute: %v", link, err)
+			}
+		})
+	}
+}
+
+func TestExportServiceResolveDownload(t *testing.T) {
+	s := newExportService(t, "https://soundtube.example", "export-secret-0123")
+	other := newExportService(t, "https://soundtube.example", "another-secret-456")
+
+	valid, _ := export.NewArchive("valid", 1, time.Hour)
+	os.WriteFile(filepath.Join(s.dir, valid.FileName()), []byte("zip"), 0600)
+	missing, _ := export.NewArchive("missing", 1, time.Hour)
+	expiredUnix := time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name    string
+		link    string
+		wantErr error
+	}{
+		{name: "valid", link: s.downloadLink(valid)},
+		{name: "signed with another secret", link: other.downloadLink(valid), wantErr: ExportLinkInvalid},
+		{name: "archive gone", link: s.downloadLink(missing), wantErr: ExportLinkExpired},
+		{name: "expired", link: "https://soundtube.example/api/exports/valid?expires=" + strconv.FormatInt(expiredUnix, 10) +
+			"&signature=" + s.sign("valid", expiredUnix), wantErr: ExportLinkExpired},
+		{name: "tampered expiry", link: strings.Replace(s.downloadLink(valid), "expires=", "expires=9", 1), wantErr: ExportLinkInvalid},
+		{name: "bad expiry", link: "https://soundtube.example/api/exports/valid?expires=soon&signature=x", wantErr: ExportLinkInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveLink(s, tt.link)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != filepath.Join(s.dir, "valid.zip") {
+				t.Fatalf("path = %q", got)
+			}
+		})
+	}
+}
+
+func TestExportServiceStopTwice(t *testing.T) {
+	s := newExportService(t, "https://soundtube.example", "export-secret-0123")
+
+	done := make(chan struct{})
+	go func() {
+		s.Run()
+		close(done)
+	}()
+
+	s.Stop()
+	s.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after Stop")
+	}
+}
+
+func TestExportSessionsOmitTokens(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ended := start.Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		session *auth.Session
+		want    string
+	}{
+		{
+			name:    "open",
+			session: auth.RebuildSessionFromStorage("jti-1", 1, start, start.Add(time.Hour), time.Time{}),
+			want:    `{"id":"jti-1","created_at":"2026-01-02T03:04:05Z","expires_at":"2026-01-02T04:04:05Z"}`,
+		},
+		{
+			name:    "logged out",
+			session: auth.RebuildSessionFromStorage("jti-2", 1, start, start.Add(time.Hour), ended),
+			want:    `{"id":"jti-2","created_at":"2026-01-02T03:04:05Z","expires_at":"2026-01-02T04:04:05Z","ended_at":"2026-01-02T03:05:05Z"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(exportSessions([]*auth.Session{tt.session})[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("session = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportComments(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	comments := []*comment.Comment{
+		comment.RebuildCommentFromStorage(1, 7, 5, "alice", 0, "first", at, at),
+		comment.RebuildCommentFromStorage(2, 7, 5, "alice", 1, "reply", at, at.Add(time.Minute)),
+	}
+
+	got := exportComments(comments)
+	want := []export.CommentData{
+		{ID: 1, SoundID: 7, Content: "first", CreatedAt: at, UpdatedAt: at},
+		{ID: 2, SoundID: 7, ParentID: 1, Content: "reply", CreatedAt: at, UpdatedAt: at.Add(time.Minute)},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d comments, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("comment %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"soundtube/internal/domain/feature"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// FeatureService evaluates feature flags. The configured flags are the defaults and the
+// overrides stored by admins win over them. Both are kept in memory, so checking a flag
+// costs no I/O; overrides are re-read when any instance publishes a change and on every
+// refresh interval in case a message was missed.
+type FeatureService struct {
+	repository feature.IFlagRepository
+	notifier   feature.IFlagNotifier
+	logger     *pkg.CustomLogger
+
+	defaults  atomic.Pointer[map[string]*feature.Flag]
+	overrides atomic.Pointer[map[string]*feature.Flag]
+
+	interval time.Duration
+	done     chan struct{}
+}
+
+func NewFeatureService(repository feature.IFlagRepository, notifier feature.IFlagNotifier, cfg *config.Features, logger *pkg.CustomLogger) (*FeatureService, error) {
+	defaults, err := ParseFeatureFlags(cfg.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FeatureService{
+		repository: repository,
+		notifier:   notifier,
+		logger:     logger,
+		interval:   cfg.RefreshInterval,
+		done:       make(chan struct{}),
+	}
+	s.defaults.Store(&defaults)
+	s.overrides.Store(&map[string]*feature.Flag{})
+
+	return s, nil
+}
+
+// ParseFeatureFlags builds the configured flags, so that a reload can reject bad ones
+// before anything is switched.
+func ParseFeatureFlags(cfg map[string]config.FeatureFlag) (map[string]*feature.Flag, error) {
+	flags := make(map[string]*feature.Flag, len(cfg))
+	for name, c := range cfg {
+		flag, err := feature.NewFlag(name, c.Description, c.Enabled, c.Percentage, c.Users, c.Roles, feature.SourceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("features.flags.%s: %w", name, err)
+		}
+		flags[name] = flag
+	}
+
+	return flags, nil
+}
+
+// SetDefaults replaces the configured flags; overrides stay in place.
+func (s *FeatureService) SetDefaults(flags map[string]*feature.Flag) {
+	s.defaults.Store(&flags)
+}
+
+// Run follows override changes until Stop is called. Call Refresh first so that the
+// overrides are in place before the server takes traffic.
+func (s *FeatureService) Run() {
+	go s.listen()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Refresh(context.Background()); err != nil {
+				s.logger.Warn("feature flag refresh failed", err)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *FeatureService) listen() {
+	for {
+		err := s.notifier.Listen(func(name string) {
+			if err := s.Refresh(context.Background()); err != nil {
+				s.logger.Warn("feature flag refresh failed", err)
+			}
+		})
+		if err != nil {
+			s.logger.Error("feature flag notifier listen failed", err)
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (s *FeatureService) Stop() {
+	close(s.done)
+	if err := s.notifier.Close(); err != nil {
+		s.logger.Warn("failed to close feature flag notifier", err)
+	}
+}
+
+// Refresh re-reads the overrides. On failure the previous ones stay in use.
+func (s *FeatureService) Refresh(ctx context.Context) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeatureService.Refresh")
+	defer span.End()
+
+	flags, err := s.repository.GetFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[string]*feature.Flag, len(flags))
+	for _, flag := range flags {
+		overrides[flag.Name()] = flag
+	}
+	s.overrides.Store(&overrides)
+
+	span.SetAttributes(attribute.Int("features.overrides", len(overrides)))
+	return nil
+}
+
+func (s *FeatureService) flag(name string) *feature.Flag {
+	if flag, ok := (*s.overrides.Load())[name]; ok {
+		return flag
+	}
+	return (*s.defaults.Load())[name]
+}
+
+// Enabled reports whether the feature is on for the subject. Unknown flags are off.
+func (s *FeatureService) Enabled(name string, subject feature.Subject) bool {
+	flag := s.flag(name)
+	return flag != nil && flag.EnabledFor(subject)
+}
+
+// List returns every flag in effect, configured or overridden, sorted by name.
+func (s *FeatureService) List() []*feature.Flag {
+	flags := maps.Clone(*s.defaults.Load())
+	maps.Copy(flags, *s.overrides.Load())
+
+	list := make([]*feature.Flag, 0, len(flags))
+	for _, name := range slices.Sorted(maps.Keys(flags)) {
+		list = append(list, flags[name])
+	}
+	return list
+}
+
+// Evaluate reports every flag's state for the subject, for clients that hide features
+// themselves.
+func (s *FeatureService) Evaluate(subject feature.Subject) map[string]bool {
+	flags := s.List()
+
+	states := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		states[flag.Name()] = flag.EnabledFor(subject)
+	}
+	return states
+}
+
+// Set stores an override for the flag and tells the other instances about it.
+func (s *FeatureService) Set(ctx context.Context, name, description string, enabled bool, percentage int, users []int, roles []string) (*feature.Flag, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeatureService.Set")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("feature.name", name),
+		attribute.Bool("feature.enabled", enabled),
+		attribute.Int("feature.percentage", percentage),
+	)
+
+	flag, err := feature.NewFlag(name, description, enabled, percentage, users, roles, feature.SourceOverride)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid feature flag", err)
+		return nil, fmt.Errorf("%w: %w", InvalidFeatureFlag, err)
+	}
+
+	if err := s.repository.SaveFlag(ctx, flag); err != nil {
+		s.logger.ErrorContext(ctx, "failed to save feature flag", err)
+		return nil, err
+	}
+
+	s.changed(ctx, name)
+	s.logger.InfoContext(ctx, "feature flag overridden", "name", name, "enabled", enabled, "percentage", percentage)
+
+	return flag, nil
+}
+
+// Reset drops the override so the configured flag applies again.
+func (s *FeatureService) Reset(ctx context.Context, name string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeatureService.Reset")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feature.name", name))
+
+	deleted, err := s.repository.DeleteFlag(ctx, name)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete feature flag", err)
+		return err
+	}
+	if !deleted {
+		return FeatureFlagNotFound
+	}
+
+	s.changed(ctx, name)
+	s.logger.InfoContext(ctx, "feature flag override removed", "name", name)
+
+	return nil
+}
+
+// changed applies a stored change locally and announces it. A lost announcement is
+// caught up by the other instances on their next refresh.
+func (s *FeatureService) changed(ctx context.Context, name string) {
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.WarnContext(ctx, "feature flag refresh failed", err)
+	}
+	if err := s.notifier.Publish(ctx, name); err != nil {
+		s.logger.WarnContext(ctx, "failed to publish feature flag change", err)
+	}
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"soundtube/internal/domain/feed"
+	"soundtube/internal/domain/follow"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type FeedService struct {
+	activities feed.IActivityRepository
+	store      feed.IFeedStore
+	follows    follow.IFollowRepositoryReader
+	sounds     sound.ISoundRepositoryReader
+	logger     *pkg.CustomLogger
+
+	fanoutThreshold int
+	maxLength       int
+}
+
+func NewFeedService(activities feed.IActivityRepository, store feed.IFeedStore, follows follow.IFollowRepositoryReader,
+	sounds sound.ISoundRepositoryReader, cfg *config.Feed, logger *pkg.CustomLogger) *FeedService {
+	return &FeedService{
+		activities:      activities,
+		store:           store,
+		follows:         follows,
+		sounds:          sounds,
+		logger:          logger,
+		fanoutThreshold: cfg.FanoutThreshold,
+		maxLength:       cfg.MaxLength,
+	}
+}
+
+// Publish stores the activity and pushes it into followers' feeds. Accounts with more
+// followers than the fan-out threshold are skipped here and merged on read instead.
+func (s *FeedService) Publish(ctx context.Context, actorID int, activityType string, soundID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeedService.Publish")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("actor.id", actorID),
+		attribute.String("activity.type", activityType),
+		attribute.Int("sound.id", soundID),
+	)
+
+	activity, err := feed.NewActivity(actorID, activityType, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "invalid activity params", err)
+		return err
+	}
+
+	activityID, err := s.activities.Create(ctx, activity)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if activityID == 0 {
+		return nil
+	}
+
+	followers, err := s.follows.CountFollowers(ctx, actorID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if followers == 0 || followers > s.fanoutThreshold {
+		return nil
+	}
+
+	go s.fanout(actorID, activityID, activity.CreatedAt())
+
+	return nil
+}
+
+func (s *FeedService) Repost(ctx context.Context, userID, soundID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeedService.Repost")
+	defer span.End()
+
+	existing, err := s.sounds.GetSoundByID(ctx, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if existing == nil {
+		s.logger.WarnContext(ctx, "invalid sound id", SoundNotFound)
+		return SoundNotFound
+	}
+
+	if existing.AuthorID() == userID {
+		s.logger.WarnContext(ctx, "invalid repost", RepostOwnSound)
+		return RepostOwnSound
+	}
+
+	return s.Publish(ctx, userID, feed.TypeRepost, soundID)
+}
+
+func (s *FeedService) Unrepost(ctx context.Context, userID, soundID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeedService.Unrepost")
+	defer span.End()
+
+	if err := s.activities.DeleteRepost(ctx, userID, soundID); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+// Backfill puts the recent activities of a newly followed account into the follower's
+// feed. Large accounts are merged on read, so there is nothing to copy for them.
+func (s *FeedService) Backfill(ctx context.Context, followerID, followeeID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeedService.Backfill")
+	defer span.End()
+
+	followers, err := s.follows.CountFollowers(ctx, followeeID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if followers > s.fanoutThreshold {
+		return nil
+	}
+
+	activities, err := s.activities.GetByActors(ctx, []int{followeeID}, time.Now(), s.maxLength)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if err := s.store.Add(ctx, followerID, activities); err != nil {
+		s.logger.ErrorContext(ctx, "feed store error", err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge takes an unfollowed account's activities out of the follower's feed. Only the
+// newest max length activities can still be in it.
+func (s *FeedService) Purge(ctx context.Context, followerID, followeeID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeedService.Purge")
+	defer span.End()
+
+	activities, err := s.activities.GetByActors(ctx, []int{followeeID}, time.Now(), s.maxLength)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	ids := make([]int, 0, len(activities))
+	for _, activity := range activities {
+		ids = append(ids, activity.ID())
+	}
+
+	if err := s.store.Remove(ctx, followerID, ids); err != nil {
+		s.logger.ErrorContext(ctx, "feed store error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetFeed merges the fanned-out feed from the store with recent activities of large
+// accounts the user follows, newest first.
+func (s *FeedService) GetFeed(ctx context.Context, userID int, before time.Time, limit int) ([]*feed.ActivityDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FeedService.GetFeed")
+	defer span.End()
+
+	ids, err := s.store.Range(ctx, userID, before, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "feed store error", err)
+		return nil, err
+	}
+
+	pushed, err := s.activities.GetByIDs(ctx, ids)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	largeIDs, err := s.follows.GetLargeFollowingIDs(ctx, userID, s.fanoutThreshold)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	pulled, err := s.activities.GetByActors(ctx, largeIDs, before, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	activities := mergeActivities(pushed, pulled, limit)
+
+	soundIDs := make([]int, 0, len(activities))
+	for _, activity := range activities {
+		soundIDs = append(soundIDs, activity.SoundID())
+	}
+
+	sounds, err := s.sounds.GetSoundsByIDs(ctx, soundIDs)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	soundsByID := make(map[int]*sound.Sound, len(sounds))
+	for _, snd := range sounds {
+		soundsByID[snd.ID()] = snd
+	}
+
+	dtos := make([]*feed.ActivityDTO, 0, len(activities))
+	for _, activity := range activities {
+		if snd, exists := soundsByID[activity.SoundID()]; exists {
+			dtos = append(dtos, activity.ToDTO(snd))
+		}
+	}
+
+	return dtos, nil
+}
+
+func (s *FeedService) fanout(actorID, activityID int, at time.Time) {
+	ctx, span := s.logger.GetTracer().Start(context.Background(), "FeedService.fanout")
+	defer span.End()
+
+	followerIDs, err := s.follows.GetFollowerIDs(ctx, actorID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to load followers for fan-out", err)
+		return
+	}
+
+	if err = s.store.Push(ctx, followerIDs, activityID, at); err != nil {
+		s.logger.ErrorContext(ctx, "failed to fan out activity", err)
+		return
+	}
+
+	s.logger.InfoContext(ctx, "activity fanned out", "activity_id", activityID, "followers", len(followerIDs))
+}
+
+func mergeActivities(pushed, pulled []*feed.Activity, limit int) []*feed.Activity {
+	seen := make(map[int]bool, len(pushed)+len(pulled))
+	merged := make([]*feed.Activity, 0, len(pushed)+len(pulled))
+
+	for _, activity := range append(pushed, pulled...) {
+		if seen[activity.ID()] {
+			continue
+		}
+		seen[activity.ID()] = true
+		merged = append(merged, activity)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].CreatedAt().After(merged[j].CreatedAt())
+	})
+
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+
+	return merged
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"soundtube/internal/domain/feed"
+	"soundtube/internal/domain/follow"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg/config"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryActivities struct {
+	mu         sync.Mutex
+	activities []*feed.Activity
+}
+
+func (r *memoryActivities) Create(_ context.Context, activity *feed.Activity) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.activities {
+		if activity.Type() == feed.TypeRepost && existing.Type() == feed.TypeRepost &&
+			existing.ActorID() == activity.ActorID() && existing.SoundID() == activity.SoundID() {
+			return 0, nil
+		}
+	}
+
+	id := len(r.activities) + 1
+	r.activities = append(r.activities, feed.RebuildActivityFromStorage(id, activity.ActorID(), "", activity.Type(),
+		activity.SoundID(), activity.CreatedAt()))
+	return id, nil
+}
+
+func (r *memoryActivities) DeleteRepost(context.Context, int, int) error { return nil }
+
+func (r *memoryActivities) GetByIDs(_ context.Context, ids []int) ([]*feed.Activity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*feed.Activity
+	for _, id := range ids {
+		if id > 0 && id <= len(r.activities) {
+			result = append(result, r.activities[id-1])
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryActivities) GetByActors(_ context.Context, actorIDs []int, before time.Time, limit int) ([]*feed.Activity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*feed.Activity
+	for i := len(r.activities) - 1; i >= 0 && len(result) < limit; i-- {
+		activity := r.activities[i]
+		for _, actorID := range actorIDs {
+			if activity.ActorID() == actorID && activity.CreatedAt().Before(before) {
+				result = append(result, activity)
+			}
+		}
+	}
+	return result, nil
+}
+
+type memoryFeedStore struct {
+	mu    sync.Mutex
+	feeds map[int]map[int]time.Time
+}
+
+func newMemoryFeedStore() *memoryFeedStore {
+	return &memoryFeedStore{feeds: make(map[int]map[int]time.Time)}
+}
+
+func (s *memoryFeedStore) Push(_ context.Context, userIDs []int, activityID int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, userID := range userIDs {
+		s.add(userID, activityID, at)
+	}
+	return nil
+}
+
+func (s *memoryFeedStore) Range(_ context.Context, userID int, before time.Time, limit int) ([]int, error) {
+	return s.ids(userID), nil
+}
+
+func (s *memoryFeedStore) Add(_ context.Context, userID int, activities []*feed.Activity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, activity := range activities {
+		s.add(userID, activity.ID(), activity.CreatedAt())
+	}
+	return nil
+}
+
+func (s *memoryFeedStore) Remove(_ context.Context, userID int, activityIDs []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range activityIDs {
+		delete(s.feeds[userID], id)
+	}
+	return nil
+}
+
+func (s *memoryFeedStore) add(userID, activityID int, at time.Time) {
+	if s.feeds[userID] == nil {
+		s.feeds[userID] = make(map[int]time.Time)
+	}
+	s.feeds[userID][activityID] = at
+}
+
+func (s *memoryFeedStore) ids(userID int) []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := []int{}
+	for id := range s.feeds[userID] {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// memoryFollows answers the follower queries the feed needs from a follower count per user.
+type memoryFollows struct {
+	follow.IFollowRepositoryReader
+	followers map[int][]int
+}
+
+func (r *memoryFollows) CountFollowers(_ context.Context, userID int) (int, error) {
+	return len(r.followers[userID]), nil
+}
+
+func (r *memoryFollows) GetFollowerIDs(_ context.Context, userID int) ([]int, error) {
+	return r.followers[userID], nil
+}
+
+// memorySounds serves GetSoundByID from a map; the rest of the reader is not used here.
+type memorySounds struct {
+	sound.ISoundRepositoryReader
+	sounds map[int]*sound.Sound
+}
+
+func (r *memorySounds) GetSoundByID(_ context.Context, id int) (*sound.Sound, error) {
+	return r.sounds[id], nil
+}
+
+func newFeedService(followers map[int][]int) (*FeedService, *memoryActivities, *memoryFeedStore) {
+	activities := &memoryActivities{}
+	store := newMemoryFeedStore()
+	sounds := &memorySounds{sounds: map[int]*sound.Sound{
+		7: sound.RebuildSoundFromStorage(7, 1, 120, "Song", "", "", "song.mp3", "/uploads/song.mp3", 1024, "mp3", "", "artist", 0, 0, 0),
+	}}
+	cfg := &config.Feed{FanoutThreshold: 2, MaxLength: 3}
+	return NewFeedService(activities, store, &memoryFollows{followers: followers}, sounds, cfg, testLogger()), activities, store
+}
+
+func TestFeedServiceRepost(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int
+		soundID int
+		times   int
+		wantErr error
+		stored  int
+	}{
+		{name: "repost", userID: 5, soundID: 7, times: 1, stored: 1},
+		{name: "repost twice", userID: 5, soundID: 7, times: 2, stored: 1},
+		{name: "own sound", userID: 1, soundID: 7, times: 1, wantErr: RepostOwnSound},
+		{name: "unknown sound", userID: 5, soundID: 8, times: 1, wantErr: SoundNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, activities, _ := newFeedService(nil)
+
+			for i := 0; i < tt.times; i++ {
+				if err := service.Repost(context.Background(), tt.userID, tt.soundID); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("repost %d: err = %v, want %v", i+1, err, tt.wantErr)
+				}
+			}
+
+			if len(activities.activities) != tt.stored {
+				t.Fatalf("stored %d activities, want %d", len(activities.activities), tt.stored)
+			}
+		})
+	}
+}
+
+func TestFeedServiceFollowKeepsFeedInStep(t *testing.T) {
+	tests := []struct {
+		name      string
+		followers map[int][]int
+		want      []int
+	}{
+		// MaxLength is 3, so only the three newest of user 2's activities are copied.
+		{name: "small account", followers: map[int][]int{2: {9}}, want: []int{2, 3, 4}},
+		// Accounts above the fan-out threshold of 2 are merged on read instead.
+		{name: "large account", followers: map[int][]int{2: {9, 10, 11}}, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, activities, store := newFeedService(tt.followers)
+			start := time.Now().Add(-time.Hour)
+			for i, actorID := range []int{2, 2, 2, 2, 3} {
+				a := feed.RebuildActivityFromStorage(0, actorID, "", feed.TypeSound, 7, start.Add(time.Duration(i)*time.Minute))
+				activities.Create(context.Background(), a)
+			}
+			store.Push(context.Background(), []int{9}, 5, start.Add(5*time.Minute))
+
+			if err := service.Backfill(context.Background(), 9, 2); err != nil {
+				t.Fatalf("backfill: %v", err)
+			}
+			if got := store.ids(9); !slices.Equal(got, append(tt.want, 5)) {
+				t.Fatalf("feed after follow = %v, want %v", got, append(tt.want, 5))
+			}
+
+			if err := service.Purge(context.Background(), 9, 2); err != nil {
+				t.Fatalf("purge: %v", err)
+			}
+			if got := store.ids(9); !slices.Equal(got, []int{5}) {
+				t.Fatalf("feed after unfollow = %v, want [5]", got)
+			}
+		})
+	}
+}
+
+func TestMergeActivities(t *testing.T) {
+	at := time.Now()
+	activity := func(id int, minutes int) *feed.Activity {
+		return feed.RebuildActivityFromStorage(id, 1, "", feed.TypeSound, 7, at.Add(time.Duration(minutes)*time.Minute))
+	}
+
+	tests := []struct {
+		name   string
+		pushed []*feed.Activity
+		pulled []*feed.Activity
+		limit  int
+		want   []int
+	}{
+		{name: "newest first", pushed: []*feed.Activity{activity(1, 1), activity(3, 3)}, pulled: []*feed.Activity{activity(2, 2)}, limit: 10, want: []int{3, 2, 1}},
+		{name: "duplicates dropped", pushed: []*feed.Activity{activity(1, 1)}, pulled: []*feed.Activity{activity(1, 1)}, limit: 10, want: []int{1}},
+		{name: "limit", pushed: []*feed.Activity{activity(1, 1), activity(2, 2)}, pulled: []*feed.Activity{activity(3, 3)}, limit: 2, want: []int{3, 2}},
+		{name: "empty", limit: 5, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeActivities(tt.pushed, tt.pulled, tt.limit)
+			got := make([]int, 0, len(merged))
+			for _, a := range merged {
+				got = append(got, a.ID())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("merged = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
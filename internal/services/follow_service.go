@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/feed"
+	"soundtube/internal/domain/follow"
+	"soundtube/internal/domain/notification"
+	"soundtube/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type FollowService struct {
+	repository follow.IFollowRepository
+	users      auth.IUserRepositoryReader
+	notifier   notification.INotifier
+	feed       feed.IFollowFeed
+	logger     *pkg.CustomLogger
+}
+
+func NewFollowService(repository follow.IFollowRepository, users auth.IUserRepositoryReader, notifier notification.INotifier,
+	feed feed.IFollowFeed, logger *pkg.CustomLogger) *FollowService {
+	return &FollowService{repository: repository, users: users, notifier: notifier, feed: feed, logger: logger}
+}
+
+func (s *FollowService) Follow(ctx context.Context, followerID int, username string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FollowService.Follow")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("user.id", followerID),
+		attribute.String("followee.name", username),
+	)
+
+	followee, err := s.getUser(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	f, err := follow.NewFollow(followerID, followee.ID())
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid follow params", err)
+		return err
+	}
+
+	inserted, err := s.repository.Follow(ctx, f)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+	// Following again changes nothing, so it must not notify the followee or refill the feed.
+	if !inserted {
+		return nil
+	}
+
+	s.logger.InfoContext(ctx, "user followed", "follower_id", followerID, "followee_id", followee.ID())
+
+	if err := s.feed.Backfill(ctx, followerID, followee.ID()); err != nil {
+		s.logger.WarnContext(ctx, "failed to backfill feed", err)
+	}
+
+	if n, err := notification.NewNotification(followee.ID(), followerID, notification.TypeFollow, 0, 0, ""); err != nil {
+		s.logger.WarnContext(ctx, "invalid notification params", err)
+	} else if err := s.notifier.Notify(ctx, n); err != nil {
+		s.logger.WarnContext(ctx, "failed to notify followee", err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) Unfollow(ctx context.Context, followerID int, username string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FollowService.Unfollow")
+	defer span.End()
+
+	followee, err := s.getUser(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repository.Unfollow(ctx, followerID, followee.ID()); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "user unfollowed", "follower_id", followerID, "followee_id", followee.ID())
+
+	if err := s.feed.Purge(ctx, followerID, followee.ID()); err != nil {
+		s.logger.WarnContext(ctx, "failed to purge feed", err)
+	}
+	return nil
+}
+
+func (s *FollowService) GetFollowers(ctx context.Context, username string, limit, offset int) ([]*follow.UserSummary, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FollowService.GetFollowers")
+	defer span.End()
+
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := s.repository.GetFollowers(ctx, user.ID(), limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return followers, nil
+}
+
+func (s *FollowService) GetFollowing(ctx context.Context, username string, limit, offset int) ([]*follow.UserSummary, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "FollowService.GetFollowing")
+	defer span.End()
+
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	following, err := s.repository.GetFollowing(ctx, user.ID(), limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return following, nil
+}
+
+func (s *FollowService) getUser(ctx context.Context, username string) (*auth.User, error) {
+	user, err := s.users.GetUserByName(ctx, username)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	if user == nil || user.IsBanned() {
+		s.logger.WarnContext(ctx, "user not found", UserNotFound)
+		return nil, UserNotFound
+	}
+
+	return user, nil
+}
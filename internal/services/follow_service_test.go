@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/follow"
+	"testing"
+)
+
+// memoryFollowGraph stores follow edges as a set, ignoring duplicates like the
+// ON CONFLICT DO NOTHING insert does.
+type memoryFollowGraph struct {
+	follow.IFollowRepositoryReader
+	edges map[[2]int]bool
+}
+
+func (r *memoryFollowGraph) Follow(_ context.Context, f *follow.Follow) (bool, error) {
+	edge := [2]int{f.FollowerID(), f.FolloweeID()}
+	if r.edges[edge] {
+		return false, nil
+	}
+	r.edges[edge] = true
+	return true, nil
+}
+
+func (r *memoryFollowGraph) Unfollow(_ context.Context, followerID, followeeID int) error {
+	delete(r.edges, [2]int{followerID, followeeID})
+	return nil
+}
+
+type namedUsers struct {
+	auth.IUserRepositoryReader
+	users map[string]*auth.User
+}
+
+func (r *namedUsers) GetUserByName(_ context.Context, name string) (*auth.User, error) {
+	return r.users[name], nil
+}
+
+type countingFeed struct {
+	backfills, purges int
+}
+
+func (f *countingFeed) Backfill(context.Context, int, int) error {
+	f.backfills++
+	return nil
+}
+
+func (f *countingFeed) Purge(context.Context, int, int) error {
+	f.purges++
+	return nil
+}
+
+func TestFollowServiceRepeatedFollow(t *testing.T) {
+	users := &namedUsers{users: map[string]*auth.User{
+		"bob": auth.RebuildUserFromStorage(2, "bob", "bob@example.com", "", auth.RoleUser, true, false, ""),
+	}}
+	notifier := &recordingNotifier{}
+	feed := &countingFeed{}
+	service := NewFollowService(&memoryFollowGraph{edges: map[[2]int]bool{}}, users, notifier, feed, testLogger())
+
+	for i := range 3 {
+		if err := service.Follow(context.Background(), 1, "bob"); err != nil {
+			t.Fatalf("follow #%d: %v", i+1, err)
+		}
+	}
+	if len(notifier.sent) != 1 || feed.backfills != 1 {
+		t.Fatalf("after three follows: %d notifications, %d backfills, want 1 each", len(notifier.sent), feed.backfills)
+	}
+
+	if err := service.Unfollow(context.Background(), 1, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Follow(context.Background(), 1, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 2 || feed.backfills != 2 {
+		t.Fatalf("after following again: %d notifications, %d backfills, want 2 each", len(notifier.sent), feed.backfills)
+	}
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"soundtube/internal/domain/listening"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ListeningService struct {
+	repository listening.IPlayRepository
+	sessions   listening.ISessionTracker
+	sounds     sound.ISoundRepositoryReader
+	logger     *pkg.CustomLogger
+
+	threshold     int
+	batchSize     int
+	flushInterval time.Duration
+
+	plays   chan *listening.Play
+	done    chan struct{}
+	stopped chan struct{}
+
+	now func() time.Time
+}
+
+func NewListeningService(repository listening.IPlayRepository, sessions listening.ISessionTracker, sounds sound.ISoundRepositoryReader,
+	cfg *config.Listening, logger *pkg.CustomLogger) *ListeningService {
+	return &ListeningService{
+		repository:    repository,
+		sessions:      sessions,
+		sounds:        sounds,
+		logger:        logger,
+		threshold:     cfg.PlayThreshold,
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		plays:         make(chan *listening.Play, cfg.QueueSize),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+		now:           time.Now,
+	}
+}
+
+// Track handles a player event. A start event records the server time; the first later
+// event of that session that passes the play rule is queued as a play, everything else
+// is only acknowledged. Progress for a session that never started is rejected.
+func (s *ListeningService) Track(ctx context.Context, userID, soundID int, sessionID, kind string, position int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ListeningService.Track")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("sound.id", soundID),
+		attribute.String("listening.event", kind),
+		attribute.Int("listening.position", position),
+	)
+
+	event, err := listening.NewEvent(userID, soundID, sessionID, kind, position)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid listening event", err)
+		return fmt.Errorf("%w: %v", InvalidListenEvent, err)
+	}
+
+	now := s.now()
+	if kind == listening.EventStart {
+		if err := s.sessions.Start(ctx, userID, sessionID, soundID, now); err != nil {
+			s.logger.ErrorContext(ctx, "redis error", err)
+			return err
+		}
+		return nil
+	}
+
+	startedAt, started, err := s.sessions.StartedAt(ctx, userID, sessionID, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "redis error", err)
+		return err
+	}
+	if !started {
+		err = fmt.Errorf("%w: session was not started", InvalidListenEvent)
+		s.logger.WarnContext(ctx, "invalid listening event", err)
+		return err
+	}
+
+	duration := 0
+	if kind == listening.EventComplete && position < s.threshold {
+		snd, err := s.sounds.GetSoundByID(ctx, soundID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			return err
+		}
+		if snd == nil {
+			return SoundNotFound
+		}
+		duration = snd.Duration()
+	}
+
+	if !event.CountsAsPlay(s.threshold, duration, now.Sub(startedAt)) {
+		return nil
+	}
+
+	first, err := s.sessions.MarkCounted(ctx, userID, sessionID, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "redis error", err)
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	play := listening.NewPlay(event, now)
+	select {
+	case s.plays <- play:
+		return nil
+	default:
+		s.logger.InfoContext(ctx, "play queue is full, writing directly", "sound_id", soundID)
+		return s.repository.SavePlays(ctx, []*listening.Play{play})
+	}
+}
+
+// Run writes queued plays in batches until Stop is called, then flushes what is left.
+func (s *ListeningService) Run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*listening.Play, 0, s.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.repository.SavePlays(context.Background(), batch); err != nil {
+			s.logger.Error("failed to save plays", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case play := <-s.plays:
+			batch = append(batch, play)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.done:
+			for {
+				select {
+				case play := <-s.plays:
+					batch = append(batch, play)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *ListeningService) Stop() {
+	close(s.done)
+	<-s.stopped
+}
+
+func (s *ListeningService) GetHistory(ctx context.Context, userID, limit, offset int) ([]*listening.HistoryEntry, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ListeningService.GetHistory")
+	defer span.End()
+
+	entries, err := s.repository.GetHistory(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"soundtube/internal/domain/listening"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg/config"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryPlays struct {
+	mu      sync.Mutex
+	batches [][]*listening.Play
+}
+
+func (r *memoryPlays) SavePlays(_ context.Context, plays []*listening.Play) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]*listening.Play(nil), plays...))
+	return nil
+}
+
+func (r *memoryPlays) GetHistory(context.Context, int, int, int) ([]*listening.HistoryEntry, error) {
+	return nil, nil
+}
+
+func (r *memoryPlays) saved() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, batch := range r.batches {
+		n += len(batch)
+	}
+	return n
+}
+
+type memorySessions struct {
+	started map[string]time.Time
+	counted map[string]bool
+}
+
+func sessionKey(userID int, sessionID string, soundID int) string {
+	return fmt.Sprintf("%d:%s:%d", userID, sessionID, soundID)
+}
+
+func (s *memorySessions) Start(_ context.Context, userID int, sessionID string, soundID int, at time.Time) error {
+	s.started[sessionKey(userID, sessionID, soundID)] = at
+	return nil
+}
+
+func (s *memorySessions) StartedAt(_ context.Context, userID int, sessionID string, soundID int) (time.Time, bool, error) {
+	at, ok := s.started[sessionKey(userID, sessionID, soundID)]
+	return at, ok, nil
+}
+
+func (s *memorySessions) MarkCounted(_ context.Context, userID int, sessionID string, soundID int) (bool, error) {
+	key := sessionKey(userID, sessionID, soundID)
+	if s.counted[key] {
+		return false, nil
+	}
+	s.counted[key] = true
+	return true, nil
+}
+
+// newListeningService returns the service and a function that moves its clock forward.
+func newListeningService(plays *memoryPlays, queueSize int) (*ListeningService, func(time.Duration)) {
+	sounds := &memorySounds{sounds: map[int]*sound.Sound{
+		2: sound.RebuildSoundFromStorage(2, 1, 12, "Jingle", "", "", "", "", 0, "", "", "", 0, 0, 0),
+	}}
+	cfg := &config.Listening{PlayThreshold: 30, BatchSize: 2, FlushInterval: time.Hour, QueueSize: queueSize}
+	sessions := &memorySessions{started: map[string]time.Time{}, counted: map[string]bool{}}
+	s := NewListeningService(plays, sessions, sounds, cfg, testLogger())
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestListeningServiceTrack(t *testing.T) {
+	type event struct {
+		soundID  int
+		session  string
+		kind     string
+		position int
+		after    time.Duration
+	}
+
+	tests := []struct {
+		name      string
+		events    []event
+		wantPlays int
+		wantErr   error
+	}{
+		{name: "start only", events: []event{{2, "a", listening.EventStart, 0, 0}}, wantPlays: 0},
+		{name: "one play per session", events: []event{
+			{3, "a", listening.EventStart, 0, 0},
+			{3, "a", listening.EventProgress, 30, 30 * time.Second},
+			{3, "a", listening.EventProgress, 60, 30 * time.Second},
+			{3, "a", listening.EventComplete, 90, 30 * time.Second},
+		}, wantPlays: 1},
+		{name: "new session counts again", events: []event{
+			{3, "a", listening.EventStart, 0, 0}, {3, "a", listening.EventProgress, 30, 30 * time.Second},
+			{3, "b", listening.EventStart, 0, 0}, {3, "b", listening.EventProgress, 30, 30 * time.Second},
+		}, wantPlays: 2},
+		{name: "position ahead of the clock", events: []event{
+			{3, "a", listening.EventStart, 0, 0}, {3, "a", listening.EventProgress, 30, time.Second},
+		}, wantPlays: 0},
+		{name: "clock catches up", events: []event{
+			{3, "a", listening.EventStart, 0, 0},
+			{3, "a", listening.EventProgress, 30, time.Second},
+			{3, "a", listening.EventProgress, 31, 30 * time.Second},
+		}, wantPlays: 1},
+		{name: "restart resets the clock", events: []event{
+			{3, "a", listening.EventStart, 0, 0},
+			{3, "a", listening.EventStart, 0, time.Minute},
+			{3, "a", listening.EventProgress, 30, time.Second},
+		}, wantPlays: 0},
+		{name: "progress without start", events: []event{{3, "a", listening.EventProgress, 30, time.Minute}}, wantErr: InvalidListenEvent},
+		{name: "progress for another session", events: []event{
+			{3, "a", listening.EventStart, 0, 0}, {3, "b", listening.EventProgress, 30, time.Minute},
+		}, wantErr: InvalidListenEvent},
+		{name: "short sound completed", events: []event{
+			{2, "a", listening.EventStart, 0, 0}, {2, "a", listening.EventComplete, 12, 12 * time.Second},
+		}, wantPlays: 1},
+		{name: "short sound completed too soon", events: []event{
+			{2, "a", listening.EventStart, 0, 0}, {2, "a", listening.EventComplete, 12, time.Second},
+		}, wantPlays: 0},
+		{name: "unknown short sound", events: []event{
+			{9, "a", listening.EventStart, 0, 0}, {9, "a", listening.EventComplete, 12, 12 * time.Second},
+		}, wantErr: SoundNotFound},
+		{name: "invalid event", events: []event{{2, "a", "seek", 0, 0}}, wantErr: InvalidListenEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plays := &memoryPlays{}
+			s, advance := newListeningService(plays, 16)
+
+			var err error
+			for _, e := range tt.events {
+				advance(e.after)
+				if err = s.Track(context.Background(), 1, e.soundID, e.session, e.kind, e.position); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			go s.Run()
+			s.Stop()
+
+			if got := plays.saved(); got != tt.wantPlays {
+				t.Fatalf("plays = %d, want %d", got, tt.wantPlays)
+			}
+		})
+	}
+}
+
+func TestListeningServiceFullQueueWritesDirectly(t *testing.T) {
+	plays := &memoryPlays{}
+	s, advance := newListeningService(plays, 1)
+
+	sessions := []string{"a", "b", "c"}
+	for _, session := range sessions {
+		if err := s.Track(context.Background(), 1, 3, session, listening.EventStart, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	advance(30 * time.Second)
+	for _, session := range sessions {
+		if err := s.Track(context.Background(), 1, 3, session, listening.EventProgress, 30); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One play waits in the queue, the other two were written on the spot.
+	if got := plays.saved(); got != 2 {
+		t.Fatalf("plays written directly = %d, want 2", got)
+	}
+
+	go s.Run()
+	s.Stop()
+	if got := plays.saved(); got != 3 {
+		t.Fatalf("plays after stop = %d, want 3", got)
+	}
+}
@@ -3,10 +3,11 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
 	"soundtube/internal/domain/auth"
 	"soundtube/pkg"
 	"soundtube/pkg/config"
+	"soundtube/pkg/metrics"
+	"soundtube/scripts"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,65 +17,87 @@ import (
 
 type LoginService struct {
 	repository auth.IUserRepository
+	sessions   auth.ISessionRepository
 	blackList  auth.ITokenBlacklist
+	metrics    *metrics.Metrics
 	logger     *pkg.CustomLogger
 	jwtkey     []byte
-	exp        int
+	exp        time.Duration
 }
 
-func NewLoginService(cfg config.Token, repository auth.IUserRepository, blackList auth.ITokenBlacklist, logger *pkg.CustomLogger) *LoginService {
-	return &LoginService{jwtkey: []byte(cfg.JwtKey), exp: cfg.Exp, repository: repository, blackList: blackList, logger: logger}
+func NewLoginService(cfg config.Token, repository auth.IUserRepository, sessions auth.ISessionRepository, blackList auth.ITokenBlacklist,
+	metrics *metrics.Metrics, logger *pkg.CustomLogger) *LoginService {
+	return &LoginService{jwtkey: []byte(cfg.JwtKey), exp: cfg.Exp, repository: repository, sessions: sessions, blackList: blackList, metrics: metrics, logger: logger}
 }
 
-func (s *LoginService) Login(ctx context.Context, username, password string) (string, error) {
+func (s *LoginService) Login(ctx context.Context, username, password string) (_ string, err error) {
 	ctx, span := s.logger.GetTracer().Start(ctx, "LoginService.Login")
 	defer span.End()
 
+	defer func() {
+		if err != nil {
+			s.metrics.Login("failure")
+			return
+		}
+		s.metrics.Login("success")
+	}()
+
 	span.SetAttributes(
 		attribute.String("user.name", username),
 	)
 
 	if username == "" || password == "" {
 		err := errors.New("username & password are requered")
-		s.logger.Warn(err.Error(), err).WithTrace(ctx)
+		s.logger.WarnContext(ctx, err.Error(), err)
 		return "", err
 	}
 
 	user, err := s.repository.GetUserByName(ctx, username)
 	if err != nil || user == nil {
-		s.logger.Warn("user not found", err).WithTrace(ctx)
+		s.logger.WarnContext(ctx, "user not found", err)
 		return "", err
 	}
 
 	if !user.IsVerified() {
 		err = errors.New("user not verified")
-		s.logger.Warn(err.Error(), err).WithTrace(ctx)
+		s.logger.WarnContext(ctx, err.Error(), err)
 		return "", err
 	}
 
 	if err = bcrypt.CompareHashAndPassword([]byte(user.Password()), []byte(password)); err != nil {
-		s.logger.Warn("invalid password", err).WithTrace(ctx)
+		s.logger.WarnContext(ctx, "invalid password", err)
 		return "", err
 	}
 
 	now := time.Now()
 
-	expiration := time.Hour
+	session, err := auth.NewSession(scripts.GenerateUUID(), user.ID(), now, now.Add(s.exp))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "invalid session params", err)
+		return "", err
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti":      session.ID(),
 		"sub":      user.ID(),
 		"username": username,
-		"exp":      now.Add(expiration).Unix(),
+		"role":     user.Role(),
+		"exp":      session.ExpiresAt().Unix(),
 		"iat":      now.Unix(),
 	})
 
 	tokenString, err := token.SignedString(s.jwtkey)
 	if err != nil {
-		s.logger.Error("token generation error", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "token generation error", err)
+		return "", err
+	}
+
+	if err = s.sessions.CreateSession(ctx, session); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
 		return "", err
 	}
 
-	s.logger.Info("login successful", "username", username).WithTrace(ctx)
+	s.logger.InfoContext(ctx, "login successful", "username", username)
 	return tokenString, nil
 }
 
@@ -105,41 +128,55 @@ func (s *LoginService) Logout(ctx context.Context, token string) error {
 	}
 
 	if err = s.blackList.Add(ctx, token, expectation); err != nil {
-		s.logger.Error("falied to add token to black list", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "falied to add token to black list", err)
 		return err
 	}
 
-	s.logger.Info("token added to black list successfully").WithTrace(ctx)
+	s.logger.InfoContext(ctx, "token added to black list successfully")
+
+	// Tokens issued before sessions were recorded carry no jti.
+	if jti, _ := claims["jti"].(string); jti != "" {
+		if err = s.sessions.EndSession(ctx, jti, time.Now()); err != nil {
+			s.logger.WarnContext(ctx, "failed to end session", err)
+		}
+	}
+
 	return nil
 }
 
-func (s *LoginService) ValidToken(ctx context.Context, token string) (string, int, error) {
+func (s *LoginService) ValidToken(ctx context.Context, token string) (string, int, string, error) {
 	ctx, span := s.logger.GetTracer().Start(ctx, "LoginService.ValidateToken")
 	defer span.End()
 
 	inBlacklist, err := s.blackList.Exist(ctx, token)
 	if err != nil {
-		s.logger.Error("blacklist check failed", err)
-		return "", 0, err
+		s.logger.ErrorContext(ctx, "blacklist check failed", err)
+		return "", 0, "", err
 	}
 	if inBlacklist {
-		return "", 0, errors.New("token is revoked")
+		return "", 0, "", errors.New("token is revoked")
 	}
 
 	parsed, err := jwt.Parse(token, func(t *jwt.Token) (any, error) {
 		return s.jwtkey, nil
 	})
 	if err != nil || !parsed.Valid {
-		return "", 0, errors.New("invalid token")
+		return "", 0, "", errors.New("invalid token")
 	}
 
 	claims, ok := parsed.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", 0, errors.New("invalid token claims")
+		return "", 0, "", errors.New("invalid token claims")
 	}
 
 	username, _ := claims["username"].(string)
 
+	// Tokens issued before roles existed carry no role claim.
+	role, _ := claims["role"].(string)
+	if role == "" {
+		role = auth.RoleUser
+	}
+
 	var userID int
 	switch sub := claims["sub"].(type) {
 	case float64:
@@ -149,13 +186,10 @@ func (s *LoginService) ValidToken(ctx context.Context, token string) (string, in
 	case int64:
 		userID = int(sub)
 	default:
-		return "", 0, errors.New("invalid user id type in token")
+		return "", 0, "", errors.New("invalid user id type in token")
 	}
 
-	s.logger.Info("Token validation",
-		"username", username,
-		"userID", userID,
-		"sub_type", fmt.Sprintf("%T", claims["sub"])).WithTrace(ctx)
+	s.logger.DebugContext(ctx, "token validated", "username", username, "user_id", userID, "role", role)
 
-	return username, userID, nil
+	return username, userID, role, nil
 }
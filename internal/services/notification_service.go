@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"soundtube/internal/domain/notification"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type NotificationService struct {
+	repository notification.INotificationRepository
+	sounds     sound.ISoundRepositoryReader
+	logger     *pkg.CustomLogger
+}
+
+func NewNotificationService(repository notification.INotificationRepository, sounds sound.ISoundRepositoryReader, logger *pkg.CustomLogger) *NotificationService {
+	return &NotificationService{repository: repository, sounds: sounds, logger: logger}
+}
+
+// Notify stores n unless the recipient caused it or has switched its type off.
+func (s *NotificationService) Notify(ctx context.Context, n *notification.Notification) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "NotificationService.Notify")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("user.id", n.RecipientID()),
+		attribute.String("notification.type", n.Type()),
+	)
+
+	if n.ActorID() == n.RecipientID() {
+		return nil
+	}
+
+	enabled, err := s.repository.IsNotificationEnabled(ctx, n.RecipientID(), n.Type())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	if err := s.repository.CreateNotification(ctx, n); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *NotificationService) NotifySoundAuthor(ctx context.Context, actorID int, notificationType string, soundID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "NotificationService.NotifySoundAuthor")
+	defer span.End()
+
+	snd, err := s.sounds.GetSoundByID(ctx, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+	if snd == nil {
+		return SoundNotFound
+	}
+
+	n, err := notification.NewNotification(snd.AuthorID(), actorID, notificationType, soundID, 0, "")
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid notification params", err)
+		return err
+	}
+
+	return s.Notify(ctx, n)
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, userID int, unreadOnly bool, limit, offset int) (*notification.InboxDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "NotificationService.GetNotifications")
+	defer span.End()
+
+	notifications, err := s.repository.GetNotifications(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	unread, err := s.repository.CountUnread(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return notification.ToInboxDTO(notifications, unread), nil
+}
+
+func (s *NotificationService) CountUnread(ctx context.Context, userID int) (int, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "NotificationService.CountUnread")
+	defer span.End()
+
+	unread, err := s.repository.CountUnread(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return 0, err
+	}
+
+	return unread, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "NotificationService.MarkRead")
+	defer span.End()
+
+	updated, err := s.repository.MarkRead(ctx, userID, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+	if !updated {
+		return NotificationNotFound
+	}
+
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "NotificationService.MarkAllRead")
+	defer span.End()
+
+	if err := s.repository.MarkAllRead(ctx, userID); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetPreferences returns every notification type with whether the user receives it.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID int) (map[string]bool, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "NotificationService.GetPreferences")
+	defer span.End()
+
+	stored, err := s.repository.GetPreferences(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	preferences := make(map[string]bool, len(notification.Types))
+	for _, t := range notification.Types {
+		enabled, exists := stored[t]
+		preferences[t] = !exists || enabled
+	}
+
+	return preferences, nil
+}
+
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID int, preferences map[string]bool) (map[string]bool, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "NotificationService.UpdatePreferences")
+	defer span.End()
+
+	for t := range preferences {
+		if !notification.ValidType(t) {
+			return nil, fmt.Errorf("%w: %s", InvalidNotificationType, t)
+		}
+	}
+
+	if err := s.repository.SetPreferences(ctx, userID, preferences); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return s.GetPreferences(ctx, userID)
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"soundtube/internal/domain/mail"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"sync/atomic"
+	"time"
+)
+
+// outboxLease is how long a claimed message is hidden from other relays while it is
+// being sent.
+const outboxLease = 5 * time.Minute
+
+// OutboxService relays messages from the mail outbox to the configured mailer,
+// retrying failures with backoff.
+type OutboxService struct {
+	repository mail.IOutboxRepository
+	mailer     mail.IMailer
+	logger     *pkg.CustomLogger
+
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+
+	// lastRelay is when the relay last read the outbox, in Unix nanoseconds.
+	lastRelay atomic.Int64
+
+	done chan struct{}
+}
+
+func NewOutboxService(repository mail.IOutboxRepository, mailer mail.IMailer, cfg *config.Email, logger *pkg.CustomLogger) *OutboxService {
+	s := &OutboxService{
+		repository:  repository,
+		mailer:      mailer,
+		logger:      logger,
+		interval:    cfg.OutboxInterval,
+		batchSize:   cfg.BatchSize,
+		maxAttempts: cfg.MaxAttempts,
+		done:        make(chan struct{}),
+	}
+	s.lastRelay.Store(time.Now().UnixNano())
+
+	return s
+}
+
+// Run relays due messages on every interval until Stop is called.
+func (s *OutboxService) Run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.relay(context.Background())
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *OutboxService) Stop() {
+	close(s.done)
+}
+
+// HealthCheck fails when the relay has not read the outbox for three intervals.
+func (s *OutboxService) HealthCheck(ctx context.Context) error {
+	since := time.Since(time.Unix(0, s.lastRelay.Load()))
+	if since > 3*s.interval {
+		return fmt.Errorf("outbox relay stalled for %s", since.Round(time.Second))
+	}
+	return nil
+}
+
+func (s *OutboxService) relay(ctx context.Context) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "OutboxService.relay")
+	defer span.End()
+
+	messages, err := s.repository.ClaimDue(ctx, s.batchSize, outboxLease)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return
+	}
+	s.lastRelay.Store(time.Now().UnixNano())
+
+	for _, msg := range messages {
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			s.failed(ctx, msg, err)
+			continue
+		}
+
+		if err := s.repository.MarkSent(ctx, msg.ID()); err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+		}
+	}
+}
+
+func (s *OutboxService) failed(ctx context.Context, msg *mail.Message, sendErr error) {
+	attempts := msg.Attempts() + 1
+
+	var err error
+	if attempts >= s.maxAttempts {
+		s.logger.ErrorContext(ctx, "giving up on mail delivery", sendErr)
+		err = s.repository.MarkDead(ctx, msg.ID(), sendErr.Error())
+	} else {
+		s.logger.WarnContext(ctx, "mail delivery failed, will retry", sendErr)
+		err = s.repository.MarkFailed(ctx, msg.ID(), sendErr.Error(), mail.RetryDelay(attempts))
+	}
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"soundtube/internal/domain/mail"
+	"soundtube/pkg/config"
+	"testing"
+	"time"
+)
+
+type memoryOutbox struct {
+	mail.IOutboxRepository
+	due    []*mail.Message
+	sent   []int
+	failed map[int]time.Duration
+	dead   []int
+}
+
+func (o *memoryOutbox) ClaimDue(context.Context, int, time.Duration) ([]*mail.Message, error) {
+	due := o.due
+	o.due = nil
+	return due, nil
+}
+
+func (o *memoryOutbox) MarkSent(_ context.Context, id int) error {
+	o.sent = append(o.sent, id)
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(_ context.Context, id int, _ string, retryIn time.Duration) error {
+	o.failed[id] = retryIn
+	return nil
+}
+
+func (o *memoryOutbox) MarkDead(_ context.Context, id int, _ string) error {
+	o.dead = append(o.dead, id)
+	return nil
+}
+
+type failingMailer struct{ err error }
+
+func (m failingMailer) Send(context.Context, *mail.Message) error { return m.err }
+
+func TestOutboxServiceRelay(t *testing.T) {
+	cfg := &config.Email{OutboxInterval: time.Minute, BatchSize: 10, MaxAttempts: 3}
+	msg := func(id, attempts int) *mail.Message {
+		return mail.RebuildMessageFromStorage(id, "user@example.com", "Hi", "", "Hi", nil, attempts)
+	}
+
+	tests := []struct {
+		name      string
+		sendErr   error
+		attempts  int
+		wantSent  bool
+		wantRetry time.Duration
+		wantDead  bool
+	}{
+		{name: "delivered", wantSent: true},
+		{name: "first failure retries", sendErr: errors.New("smtp down"), attempts: 0, wantRetry: time.Minute},
+		{name: "second failure backs off", sendErr: errors.New("smtp down"), attempts: 1, wantRetry: 4 * time.Minute},
+		{name: "last attempt gives up", sendErr: errors.New("smtp down"), attempts: 2, wantDead: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &memoryOutbox{due: []*mail.Message{msg(7, tt.attempts)}, failed: map[int]time.Duration{}}
+			s := NewOutboxService(outbox, failingMailer{err: tt.sendErr}, cfg, testLogger())
+
+			s.relay(context.Background())
+
+			if got := len(outbox.sent) == 1; got != tt.wantSent {
+				t.Fatalf("sent = %v, want %v", outbox.sent, tt.wantSent)
+			}
+			if got := outbox.failed[7]; got != tt.wantRetry {
+				t.Fatalf("retry in = %s, want %s", got, tt.wantRetry)
+			}
+			if got := len(outbox.dead) == 1; got != tt.wantDead {
+				t.Fatalf("dead = %v, want %v", outbox.dead, tt.wantDead)
+			}
+		})
+	}
+}
+
+func TestOutboxServiceHealthCheck(t *testing.T) {
+	cfg := &config.Email{OutboxInterval: time.Minute, BatchSize: 10, MaxAttempts: 3}
+
+	tests := []struct {
+		name      string
+		lastRelay time.Duration
+		wantErr   bool
+	}{
+		{name: "fresh", lastRelay: 0},
+		{name: "within three intervals", lastRelay: 2 * time.Minute},
+		{name: "stalled", lastRelay: 4 * time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewOutboxService(&memoryOutbox{}, failingMailer{}, cfg, testLogger())
+			s.lastRelay.Store(time.Now().Add(-tt.lastRelay).UnixNano())
+
+			if err := s.HealthCheck(context.Background()); (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/playlist"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	ExportFormatM3U8 = "m3u8"
+	ExportFormatXSPF = "xspf"
+)
+
+type PlaylistService struct {
+	repository playlist.IPlaylistRepository
+	sounds     sound.ISoundRepositoryReader
+	users      auth.IUserRepositoryReader
+	logger     *pkg.CustomLogger
+	publicURL  string
+}
+
+func NewPlaylistService(repository playlist.IPlaylistRepository, sounds sound.ISoundRepositoryReader, users auth.IUserRepositoryReader, publicURL string, logger *pkg.CustomLogger) *PlaylistService {
+	return &PlaylistService{repository: repository, sounds: sounds, users: users, publicURL: strings.TrimRight(publicURL, "/"), logger: logger}
+}
+
+func (s *PlaylistService) CreatePlaylist(ctx context.Context, userID int, title, description, visibility string) (*playlist.PlaylistDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.CreatePlaylist")
+	defer span.End()
+
+	p, err := playlist.NewPlaylist(userID, title, description, visibility)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid playlist params", err)
+		return nil, err
+	}
+
+	id, err := s.repository.CreatePlaylist(ctx, p)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return s.GetPlaylist(ctx, userID, id)
+}
+
+func (s *PlaylistService) GetPlaylist(ctx context.Context, userID, id int) (*playlist.PlaylistDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.GetPlaylist")
+	defer span.End()
+
+	p, err := s.getPlaylist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.CanView(userID) {
+		s.logger.WarnContext(ctx, "private playlist", PlaylistNotFound)
+		return nil, PlaylistNotFound
+	}
+
+	tracks, sounds, err := s.loadTracks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := p.ToDTO()
+	dto.Tracks = make([]*playlist.TrackDTO, 0, len(tracks))
+	for _, track := range tracks {
+		if snd, exists := sounds[track.SoundID()]; exists {
+			dto.Tracks = append(dto.Tracks, track.ToDTO(snd))
+		}
+	}
+
+	return dto, nil
+}
+
+func (s *PlaylistService) GetUserPlaylists(ctx context.Context, userID int) ([]*playlist.Playlist, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.GetUserPlaylists")
+	defer span.End()
+
+	playlists, err := s.repository.GetPlaylistsByUser(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+func (s *PlaylistService) UpdatePlaylist(ctx context.Context, userID, id int, title, description, visibility string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.UpdatePlaylist")
+	defer span.End()
+
+	p, err := s.getManageablePlaylist(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err = p.Update(title, description, visibility); err != nil {
+		s.logger.WarnContext(ctx, "invalid playlist params", err)
+		return err
+	}
+
+	if err = s.repository.UpdatePlaylist(ctx, id, p); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *PlaylistService) DeletePlaylist(ctx context.Context, userID, id int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.DeletePlaylist")
+	defer span.End()
+
+	if _, err := s.getManageablePlaylist(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := s.repository.DeletePlaylist(ctx, id); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *PlaylistService) AddCollaborator(ctx context.Context, userID, id int, username string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.AddCollaborator")
+	defer span.End()
+
+	p, err := s.getManageablePlaylist(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	collaborator, err := s.users.GetUserByName(ctx, username)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if collaborator == nil {
+		return UserNotFound
+	}
+
+	if collaborator.ID() == p.OwnerID() {
+		return PlaylistOwnerCollaborator
+	}
+
+	if err = s.repository.AddCollaborator(ctx, id, collaborator.ID()); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *PlaylistService) RemoveCollaborator(ctx context.Context, userID, id int, username string) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.RemoveCollaborator")
+	defer span.End()
+
+	if _, err := s.getManageablePlaylist(ctx, userID, id); err != nil {
+		return err
+	}
+
+	collaborator, err := s.users.GetUserByName(ctx, username)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if collaborator == nil {
+		return UserNotFound
+	}
+
+	if err = s.repository.RemoveCollaborator(ctx, id, collaborator.ID()); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *PlaylistService) AddTrack(ctx context.Context, userID, id, soundID int) (int, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.AddTrack")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("playlist.id", id),
+		attribute.Int("sound.id", soundID),
+	)
+
+	if _, err := s.getEditablePlaylist(ctx, userID, id); err != nil {
+		return 0, err
+	}
+
+	snd, err := s.sounds.GetSoundByID(ctx, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return 0, err
+	}
+
+	if snd == nil {
+		return 0, SoundNotFound
+	}
+
+	trackID, err := s.repository.AppendTrack(ctx, id, soundID, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return 0, err
+	}
+
+	return trackID, nil
+}
+
+func (s *PlaylistService) RemoveTrack(ctx context.Context, userID, id, trackID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.RemoveTrack")
+	defer span.End()
+
+	if _, err := s.getEditablePlaylist(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := s.repository.RemoveTrack(ctx, id, trackID); err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MoveTrack places the track right after afterTrackID, or at the top when afterTrackID is 0.
+// Only the moved track gets a new rank, the rest of the list is untouched.
+func (s *PlaylistService) MoveTrack(ctx context.Context, userID, id, trackID, afterTrackID int) error {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.MoveTrack")
+	defer span.End()
+
+	if _, err := s.getEditablePlaylist(ctx, userID, id); err != nil {
+		return err
+	}
+
+	moved, err := s.repository.MoveTrack(ctx, id, trackID, afterTrackID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return err
+	}
+
+	if !moved {
+		return TrackNotFound
+	}
+
+	return nil
+}
+
+// Export renders the playlist in the requested format and returns the body with its content type.
+func (s *PlaylistService) Export(ctx context.Context, userID, id int, format string) ([]byte, string, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "PlaylistService.Export")
+	defer span.End()
+
+	p, err := s.getPlaylist(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !p.CanView(userID) {
+		return nil, "", PlaylistNotFound
+	}
+
+	tracks, soundsByID, err := s.loadTracks(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sounds := make([]*sound.Sound, 0, len(tracks))
+	for _, track := range tracks {
+		if snd, exists := soundsByID[track.SoundID()]; exists {
+			sounds = append(sounds, snd)
+		}
+	}
+
+	switch format {
+	case ExportFormatM3U8:
+		return []byte(playlist.ToM3U8(p, sounds, s.publicURL)), "application/vnd.apple.mpegurl", nil
+	case ExportFormatXSPF:
+		body, err := playlist.ToXSPF(p, sounds, s.publicURL)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to render xspf", err)
+			return nil, "", err
+		}
+		return body, "application/xspf+xml", nil
+	default:
+		return nil, "", errors.New("format must be m3u8 or xspf")
+	}
+}
+
+func (s *PlaylistService) loadTracks(ctx context.Context, id int) ([]*playlist.Track, map[int]*sound.Sound, error) {
+	tracks, err := s.repository.GetTracks(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, nil, err
+	}
+
+	soundIDs := make([]int, 0, len(tracks))
+	for _, track := range tracks {
+		soundIDs = append(soundIDs, track.SoundID())
+	}
+
+	sounds, err := s.sounds.GetSoundsByIDs(ctx, soundIDs)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, nil, err
+	}
+
+	soundsByID := make(map[int]*sound.Sound, len(sounds))
+	for _, snd := range sounds {
+		soundsByID[snd.ID()] = snd
+	}
+
+	return tracks, soundsByID, nil
+}
+
+func (s *PlaylistService) getPlaylist(ctx context.Context, id int) (*playlist.Playlist, error) {
+	p, err := s.repository.GetPlaylistByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, PlaylistNotFound
+	}
+
+	return p, nil
+}
+
+func (s *PlaylistService) getManageablePlaylist(ctx context.Context, userID, id int) (*playlist.Playlist, error) {
+	p, err := s.getPlaylist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.CanView(userID) {
+		return nil, PlaylistNotFound
+	}
+
+	if !p.CanManage(userID) {
+		s.logger.WarnContext(ctx, "playlist management denied", PlaylistForbidden)
+		return nil, PlaylistForbidden
+	}
+
+	return p, nil
+}
+
+func (s *PlaylistService) getEditablePlaylist(ctx context.Context, userID, id int) (*playlist.Playlist, error) {
+	p, err := s.getPlaylist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.CanView(userID) {
+		return nil, PlaylistNotFound
+	}
+
+	if !p.CanEditTracks(userID) {
+		s.logger.WarnContext(ctx, "playlist edit denied", PlaylistForbidden)
+		return nil, PlaylistForbidden
+	}
+
+	return p, nil
+}
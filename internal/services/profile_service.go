@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ProfileService struct {
+	profiles auth.IProfileRepository
+	sounds   sound.ISoundRepositoryReader
+	logger   *pkg.CustomLogger
+}
+
+func NewProfileService(profiles auth.IProfileRepository, sounds sound.ISoundRepositoryReader, logger *pkg.CustomLogger) *ProfileService {
+	return &ProfileService{profiles: profiles, sounds: sounds, logger: logger}
+}
+
+func (s *ProfileService) GetProfile(ctx context.Context, username string) (*auth.Profile, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ProfileService.GetProfile")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.name", username),
+	)
+
+	profile, err := s.profiles.GetProfileByName(ctx, username)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	if profile == nil {
+		s.logger.WarnContext(ctx, "profile not found", UserNotFound)
+		return nil, UserNotFound
+	}
+
+	return profile, nil
+}
+
+// GetUserSounds returns a page of the user's sounds together with the total number of uploads.
+func (s *ProfileService) GetUserSounds(ctx context.Context, username string, limit, offset int) ([]*sound.Sound, int, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "ProfileService.GetUserSounds")
+	defer span.End()
+
+	profile, err := s.GetProfile(ctx, username)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sounds, err := s.sounds.GetSoundsPageByAuthor(ctx, profile.ID(), limit, offset)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, 0, err
+	}
+
+	return sounds, profile.UploadsCount(), nil
+}
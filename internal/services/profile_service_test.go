@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/sound"
+	"testing"
+)
+
+type memoryProfiles map[string]*auth.Profile
+
+func (p memoryProfiles) GetProfileByName(_ context.Context, username string) (*auth.Profile, error) {
+	if username == "broken" {
+		return nil, errors.New("db down")
+	}
+	return p[username], nil
+}
+
+type authorSounds struct {
+	sound.ISoundRepositoryReader
+	sounds []*sound.Sound
+	asked  [3]int
+}
+
+func (r *authorSounds) GetSoundsPageByAuthor(_ context.Context, authorID, limit, offset int) ([]*sound.Sound, error) {
+	r.asked = [3]int{authorID, limit, offset}
+	return r.sounds, nil
+}
+
+func TestProfileServiceGetUserSounds(t *testing.T) {
+	profiles := memoryProfiles{
+		"alice": auth.RebuildProfileFromStorage(7, "alice", "2025-01-01", 3, 10, 20, 1),
+	}
+	page := []*sound.Sound{
+		sound.RebuildSoundFromStorage(1, 7, 180, "Intro", "", "jazz", "intro.mp3", "uploads/intro.mp3", 1024, "mp3", "2025-01-02", "alice", 0, 0, 0),
+	}
+
+	tests := []struct {
+		name      string
+		username  string
+		wantErr   error
+		wantTotal int
+		wantPage  int
+	}{
+		{name: "existing user", username: "alice", wantTotal: 3, wantPage: 1},
+		{name: "unknown user", username: "bob", wantErr: UserNotFound},
+		{name: "db error", username: "broken", wantErr: errors.New("db down")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sounds := &authorSounds{sounds: page}
+			s := NewProfileService(profiles, sounds, testLogger())
+
+			got, total, err := s.GetUserSounds(context.Background(), tt.username, 10, 20)
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if total != tt.wantTotal || len(got) != tt.wantPage {
+				t.Fatalf("total = %d, page = %d, want %d and %d", total, len(got), tt.wantTotal, tt.wantPage)
+			}
+			if sounds.asked != [3]int{7, 10, 20} {
+				t.Fatalf("page asked = %v, want author 7, limit 10, offset 20", sounds.asked)
+			}
+			if author := got[0].ToDTO().Author; author.ID != 7 || author.Username != "alice" {
+				t.Fatalf("author = %+v", author)
+			}
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"soundtube/internal/domain"
+	"soundtube/internal/domain/notification"
 	"soundtube/internal/domain/reactions"
+	"soundtube/internal/domain/realtime"
 	"soundtube/internal/repositories"
 	"soundtube/pkg"
+	"soundtube/pkg/metrics"
 	"sync"
 	"time"
 )
@@ -17,6 +20,9 @@ type ReactionService struct {
 	participants *repositories.SoundPartisipantsRepository
 	logger       *pkg.CustomLogger
 	cache        domain.ICache
+	events       realtime.IEventPublisher
+	notifier     notification.INotifier
+	metrics      *metrics.Metrics
 }
 
 type SoundReactionsResponse struct {
@@ -26,53 +32,102 @@ type SoundReactionsResponse struct {
 	UserReaction *string `json:"user_reaction,omitempty"`
 }
 
-func NewRactionService(repository *repositories.SoundReactionRepository, participants *repositories.SoundPartisipantsRepository, cache domain.ICache, logger *pkg.CustomLogger) *ReactionService {
+func NewRactionService(repository *repositories.SoundReactionRepository, participants *repositories.SoundPartisipantsRepository, cache domain.ICache,
+	events realtime.IEventPublisher, notifier notification.INotifier, metrics *metrics.Metrics, logger *pkg.CustomLogger) *ReactionService {
 	return &ReactionService{
 		repository:   repository,
 		participants: participants,
 		cache:        cache,
+		events:       events,
+		notifier:     notifier,
+		metrics:      metrics,
 		logger:       logger,
 	}
 }
 
+// SetSoundReaction toggles the user's reaction, pushes the new totals to live subscribers
+// and lets the author know about a new like.
 func (s *ReactionService) SetSoundReaction(ctx context.Context, userID, soundID int, reactionType string) error {
 	ctx, span := s.logger.GetTracer().Start(ctx, "ReactionService.SetSoundReaction")
 	defer span.End()
 
-	existingReaction, err := s.participants.Get(ctx, userID, soundID)
+	set, err := s.applySoundReaction(ctx, userID, soundID, reactionType)
 	if err != nil {
 		return err
 	}
 
+	s.publishReactionCounts(ctx, soundID)
+
+	if set {
+		s.metrics.Reaction(reactionType)
+	}
+
+	if set && reactionType == "like" {
+		if err := s.notifier.NotifySoundAuthor(ctx, userID, notification.TypeLike, soundID); err != nil {
+			s.logger.WarnContext(ctx, "failed to notify sound author", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *ReactionService) publishReactionCounts(ctx context.Context, soundID int) {
+	stats, err := s.repository.GetReactionStats(ctx, soundID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to load reaction stats for event", err)
+		return
+	}
+
+	event, err := realtime.NewEvent(realtime.EventReactions, soundID, realtime.ReactionCounts{Likes: stats.Likes, Dislikes: stats.Dislikes})
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to build reaction event", err)
+		return
+	}
+
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.WarnContext(ctx, "failed to publish reaction event", err)
+	}
+}
+
+func (s *ReactionService) applySoundReaction(ctx context.Context, userID, soundID int, reactionType string) (bool, error) {
+	existingReaction, err := s.participants.Get(ctx, userID, soundID)
+	if err != nil {
+		return false, err
+	}
+
 	if existingReaction != nil && existingReaction.ReactType == reactionType {
 		err = s.repository.Delete(ctx, soundID, reactionType)
 		if err != nil {
-			return err
+			return false, err
 		}
-		return s.participants.Remove(ctx, userID, soundID)
+		return false, s.participants.Remove(ctx, userID, soundID)
 	}
 
 	if existingReaction != nil {
 		err = s.repository.Delete(ctx, soundID, existingReaction.ReactType)
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 
 	newReaction := reactions.NewReaction(soundID, reactionType)
 	_, err = s.repository.Create(ctx, newReaction)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	cacheKey := fmt.Sprintf("sound_reactions:stats:%d", soundID)
 	if err := s.cache.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("failed to invalidate reaction cache", err).WithTrace(ctx)
+		s.logger.WarnContext(ctx, "failed to invalidate reaction cache", err)
 	} else {
-		s.logger.Info("reaction cache invalidated", "sound_id", soundID).WithTrace(ctx)
+		s.logger.InfoContext(ctx, "reaction cache invalidated", "sound_id", soundID)
+	}
+
+	if err := s.participants.AddOrUpdate(ctx, userID, soundID, reactionType); err != nil {
+		return false, err
 	}
 
-	return s.participants.AddOrUpdate(ctx, userID, soundID, reactionType)
+	return true, nil
 }
 
 func (s *ReactionService) GetSoundReactions(ctx context.Context, userID, soundID int) (*SoundReactionsResponse, error) {
@@ -84,7 +139,7 @@ func (s *ReactionService) GetSoundReactions(ctx context.Context, userID, soundID
 	var reactionStats *repositories.ReactionStatus
 	if cashed, err := s.cache.Get(ctx, cashedKey); err == nil {
 		if err := json.Unmarshal([]byte(cashed), &reactionStats); err == nil {
-			s.logger.Info("reaction stats loaded from cache", "sound_id", soundID).WithTrace(ctx)
+			s.logger.InfoContext(ctx, "reaction stats loaded from cache", "sound_id", soundID)
 		}
 	}
 
@@ -97,7 +152,7 @@ func (s *ReactionService) GetSoundReactions(ctx context.Context, userID, soundID
 
 		if data, err := json.Marshal(reactionStats); err != nil {
 			if err := s.cache.Set(ctx, cashedKey, data, 15*time.Minute); err != nil {
-				s.logger.Warn("failed to cache reaction stats", err).WithTrace(ctx)
+				s.logger.WarnContext(ctx, "failed to cache reaction stats", err)
 			}
 		}
 	}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"soundtube/internal/domain/realtime"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"sync"
+	"time"
+)
+
+// RealtimeClient is one live connection. The hub only ever queues messages on it; the
+// transport drains Messages and stops when Done is closed.
+type RealtimeClient struct {
+	UserID int
+
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *RealtimeClient) Messages() <-chan []byte { return c.send }
+func (c *RealtimeClient) Done() <-chan struct{}   { return c.done }
+
+// Deliver queues msg without blocking and reports whether there was room for it.
+func (c *RealtimeClient) Deliver(msg []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *RealtimeClient) close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// RealtimeHub routes sound events from the event bus to the connections subscribed to them.
+type RealtimeHub struct {
+	bus    realtime.IEventBus
+	logger *pkg.CustomLogger
+
+	sendBuffer       int
+	maxSubscriptions int
+
+	mu            sync.RWMutex
+	subscribers   map[int]map[*RealtimeClient]struct{}
+	subscriptions map[*RealtimeClient]map[int]struct{}
+
+	done chan struct{}
+}
+
+func NewRealtimeHub(bus realtime.IEventBus, cfg *config.Realtime, logger *pkg.CustomLogger) *RealtimeHub {
+	return &RealtimeHub{
+		bus:              bus,
+		logger:           logger,
+		sendBuffer:       cfg.SendBuffer,
+		maxSubscriptions: cfg.MaxSubscriptions,
+		subscribers:      make(map[int]map[*RealtimeClient]struct{}),
+		subscriptions:    make(map[*RealtimeClient]map[int]struct{}),
+		done:             make(chan struct{}),
+	}
+}
+
+// Run listens to the event bus until Stop is called, reconnecting after failures.
+func (h *RealtimeHub) Run() {
+	for {
+		if err := h.bus.Listen(h.dispatch); err != nil {
+			h.logger.Error("event bus listen failed", err)
+		}
+
+		select {
+		case <-h.done:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (h *RealtimeHub) Stop() {
+	close(h.done)
+	if err := h.bus.Close(); err != nil {
+		h.logger.Warn("failed to close event bus", err)
+	}
+}
+
+func (h *RealtimeHub) Connect(userID int) *RealtimeClient {
+	client := &RealtimeClient{
+		UserID: userID,
+		send:   make(chan []byte, h.sendBuffer),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.subscriptions[client] = make(map[int]struct{})
+	h.mu.Unlock()
+
+	return client
+}
+
+func (h *RealtimeHub) Disconnect(client *RealtimeClient) {
+	h.mu.Lock()
+	h.remove(client)
+	h.mu.Unlock()
+}
+
+func (h *RealtimeHub) Subscribe(client *RealtimeClient, soundIDs []int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	current, ok := h.subscriptions[client]
+	if !ok {
+		return nil
+	}
+
+	for _, id := range soundIDs {
+		if _, exists := current[id]; exists {
+			continue
+		}
+		if len(current) >= h.maxSubscriptions {
+			return TooManySubscriptions
+		}
+
+		current[id] = struct{}{}
+		if h.subscribers[id] == nil {
+			h.subscribers[id] = make(map[*RealtimeClient]struct{})
+		}
+		h.subscribers[id][client] = struct{}{}
+	}
+
+	return nil
+}
+
+// Replay returns the events of a sound that a client which last saw lastEventID has
+// missed, as far as the retained history reaches.
+func (h *RealtimeHub) Replay(ctx context.Context, soundID int, lastEventID string) ([]*realtime.Event, error) {
+	if lastEventID == "" {
+		return nil, nil
+	}
+
+	if _, err := realtime.ParseEventID(lastEventID); err != nil {
+		return nil, fmt.Errorf("%w: %v", InvalidLastEventID, err)
+	}
+
+	events, err := h.bus.Since(ctx, soundID, lastEventID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to read event history", err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (h *RealtimeHub) Unsubscribe(client *RealtimeClient, soundIDs []int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, id := range soundIDs {
+		delete(h.subscriptions[client], id)
+		h.unsubscribe(client, id)
+	}
+}
+
+// dispatch fans an event out to subscribers. A client whose buffer is full is too slow
+// to keep up and gets disconnected instead of stalling everyone else.
+func (h *RealtimeHub) dispatch(event *realtime.Event) {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		h.logger.Error("failed to encode event", err)
+		return
+	}
+
+	var slow []*RealtimeClient
+
+	h.mu.RLock()
+	for client := range h.subscribers[event.SoundID] {
+		if !client.Deliver(msg) {
+			slow = append(slow, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(slow) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	for _, client := range slow {
+		h.logger.Info("dropping slow realtime client", "user_id", client.UserID)
+		h.remove(client)
+	}
+	h.mu.Unlock()
+}
+
+// remove must be called with mu held.
+func (h *RealtimeHub) remove(client *RealtimeClient) {
+	for id := range h.subscriptions[client] {
+		h.unsubscribe(client, id)
+	}
+	delete(h.subscriptions, client)
+	client.close()
+}
+
+func (h *RealtimeHub) unsubscribe(client *RealtimeClient, soundID int) {
+	delete(h.subscribers[soundID], client)
+	if len(h.subscribers[soundID]) == 0 {
+		delete(h.subscribers, soundID)
+	}
+}
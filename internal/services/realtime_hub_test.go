@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"soundtube/internal/domain/realtime"
+	"soundtube/pkg/config"
+	"testing"
+)
+
+// historyBus serves a fixed backlog and remembers what was asked of it.
+type historyBus struct {
+	*memoryBus
+	history []*realtime.Event
+	asked   []string
+}
+
+func (b *historyBus) Since(_ context.Context, _ int, lastID string) ([]*realtime.Event, error) {
+	b.asked = append(b.asked, lastID)
+	return b.history, nil
+}
+
+func TestRealtimeHubReplay(t *testing.T) {
+	history := []*realtime.Event{{ID: "1700000000001-0", Type: realtime.EventReactions, SoundID: 3}}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantEvents  int
+		wantAsked   bool
+		wantErr     error
+	}{
+		{name: "fresh connection", lastEventID: "", wantEvents: 0, wantAsked: false},
+		{name: "resume", lastEventID: "1700000000000-0", wantEvents: 1, wantAsked: true},
+		{name: "malformed id", lastEventID: "yesterday", wantErr: InvalidLastEventID},
+		{name: "stream id shortcut", lastEventID: "$", wantErr: InvalidLastEventID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := &historyBus{memoryBus: newMemoryBus(), history: history}
+			hub := NewRealtimeHub(bus, &config.Realtime{SendBuffer: 4, MaxSubscriptions: 4}, testLogger())
+
+			events, err := hub.Replay(context.Background(), 3, tt.lastEventID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(events) != tt.wantEvents {
+				t.Fatalf("events = %d, want %d", len(events), tt.wantEvents)
+			}
+			if asked := len(bus.asked) > 0; asked != tt.wantAsked {
+				t.Fatalf("history read = %v, want %v", asked, tt.wantAsked)
+			}
+		})
+	}
+}
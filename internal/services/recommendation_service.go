@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"soundtube/internal/domain/recommendation"
+	"soundtube/internal/domain/sound"
+	"soundtube/pkg"
+	"soundtube/pkg/config"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type RecommendationService struct {
+	repository recommendation.IRecommendationRepository
+	sounds     sound.ISoundRepositoryReader
+	lock       recommendation.IRebuildLock
+	logger     *pkg.CustomLogger
+
+	interval time.Duration
+	topK     int
+
+	done chan struct{}
+}
+
+func NewRecommendationService(repository recommendation.IRecommendationRepository, sounds sound.ISoundRepositoryReader,
+	lock recommendation.IRebuildLock, cfg *config.Recommendations, logger *pkg.CustomLogger) *RecommendationService {
+	return &RecommendationService{
+		repository: repository,
+		sounds:     sounds,
+		lock:       lock,
+		logger:     logger,
+		interval:   cfg.RefreshInterval,
+		topK:       cfg.TopK,
+		done:       make(chan struct{}),
+	}
+}
+
+// Run rebuilds the similarity table right away and then on every refresh interval until Stop is called.
+func (s *RecommendationService) Run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.rebuild(context.Background())
+
+	for {
+		select {
+		case <-ticker.C:
+			s.rebuild(context.Background())
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *RecommendationService) Stop() {
+	close(s.done)
+}
+
+// rebuild recomputes the similarity table unless another instance already did so
+// during this interval. The lock is kept until it expires, slightly before the next
+// tick, and is only released early when the rebuild fails so another instance can retry.
+func (s *RecommendationService) rebuild(ctx context.Context) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "RecommendationService.rebuild")
+	defer span.End()
+
+	token, ok, err := s.lock.Acquire(ctx, s.interval-s.interval/10)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to acquire similarities rebuild lock", err)
+		return
+	}
+	if !ok {
+		s.logger.DebugContext(ctx, "similarities rebuild skipped, another instance holds the lock")
+		return
+	}
+
+	started := time.Now()
+	pairs, err := s.repository.RebuildSimilarities(ctx, s.topK)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to rebuild similarities", err)
+		if err := s.lock.Release(ctx, token); err != nil {
+			s.logger.WarnContext(ctx, "failed to release similarities rebuild lock", err)
+		}
+		return
+	}
+
+	s.logger.InfoContext(ctx, "similarities rebuilt", "pairs", pairs, "took", time.Since(started).String())
+}
+
+// GetSimilar returns the nearest neighbors of a sound, topped up with popular sounds
+// of the same genre while the sound has too few co-listeners.
+func (s *RecommendationService) GetSimilar(ctx context.Context, soundID, limit int) ([]*sound.Sound, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "RecommendationService.GetSimilar")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("sound.id", soundID),
+	)
+
+	source, err := s.sounds.GetSoundByID(ctx, soundID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+	if source == nil {
+		return nil, SoundNotFound
+	}
+
+	ids, err := s.repository.GetSimilarIDs(ctx, soundID, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	var genres []string
+	if source.Genre() != "" {
+		genres = []string{strings.ToLower(source.Genre())}
+	}
+
+	ids, err = s.fillWithPopular(ctx, ids, &recommendation.PopularQuery{
+		Genres:     genres,
+		ExcludeIDs: append([]int{soundID}, ids...),
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadSounds(ctx, ids)
+}
+
+// GetRecommendations returns sounds liked by listeners with a similar taste. Users without
+// enough history get the most popular sounds of their favorite genres, then of all genres.
+func (s *RecommendationService) GetRecommendations(ctx context.Context, userID, limit int) ([]*sound.Sound, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "RecommendationService.GetRecommendations")
+	defer span.End()
+
+	ids, err := s.repository.GetRecommendedIDs(ctx, userID, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	if len(ids) < limit {
+		genres, err := s.repository.GetUserGenres(ctx, userID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			return nil, err
+		}
+
+		if len(genres) > 0 {
+			ids, err = s.fillWithPopular(ctx, ids, &recommendation.PopularQuery{
+				Genres:        genres,
+				ExcludeIDs:    ids,
+				ExcludeUserID: userID,
+				Limit:         limit,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	ids, err = s.fillWithPopular(ctx, ids, &recommendation.PopularQuery{
+		ExcludeIDs:    ids,
+		ExcludeUserID: userID,
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadSounds(ctx, ids)
+}
+
+// fillWithPopular appends popular sounds to ids until query.Limit ids are collected.
+func (s *RecommendationService) fillWithPopular(ctx context.Context, ids []int, query *recommendation.PopularQuery) ([]int, error) {
+	if len(ids) >= query.Limit {
+		return ids, nil
+	}
+
+	query.Limit -= len(ids)
+	popular, err := s.repository.GetPopularIDs(ctx, query)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return append(ids, popular...), nil
+}
+
+func (s *RecommendationService) loadSounds(ctx context.Context, ids []int) ([]*sound.Sound, error) {
+	sounds, err := s.sounds.GetSoundsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return orderSoundsByIDs(sounds, ids), nil
+}
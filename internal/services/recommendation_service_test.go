@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"soundtube/internal/domain/recommendation"
+	"soundtube/pkg/config"
+	"testing"
+	"time"
+)
+
+type countingRecommendations struct {
+	recommendation.IRecommendationRepositoryReader
+	rebuilds int
+	err      error
+}
+
+func (r *countingRecommendations) RebuildSimilarities(context.Context, int) (int, error) {
+	r.rebuilds++
+	return 10, r.err
+}
+
+type fakeRebuildLock struct {
+	held     bool
+	err      error
+	ttl      time.Duration
+	released []string
+}
+
+func (l *fakeRebuildLock) Acquire(_ context.Context, ttl time.Duration) (string, bool, error) {
+	if l.err != nil || l.held {
+		return "", false, l.err
+	}
+	l.held = true
+	l.ttl = ttl
+	return "token", true, nil
+}
+
+func (l *fakeRebuildLock) Release(_ context.Context, token string) error {
+	l.held = false
+	l.released = append(l.released, token)
+	return nil
+}
+
+func TestRecommendationServiceRebuildLock(t *testing.T) {
+	tests := []struct {
+		name         string
+		lock         *fakeRebuildLock
+		rebuildErr   error
+		wantRebuilds int
+		wantReleased int
+		wantHeld     bool
+	}{
+		{name: "rebuilds and keeps the lock", lock: &fakeRebuildLock{}, wantRebuilds: 1, wantHeld: true},
+		{name: "skips while another instance holds the lock", lock: &fakeRebuildLock{held: true}, wantRebuilds: 0, wantHeld: true},
+		{name: "skips when the lock is unavailable", lock: &fakeRebuildLock{err: errors.New("redis down")}, wantRebuilds: 0},
+		{name: "releases the lock when the rebuild fails", lock: &fakeRebuildLock{}, rebuildErr: errors.New("db down"), wantRebuilds: 1, wantReleased: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &countingRecommendations{err: tt.rebuildErr}
+			s := NewRecommendationService(repo, &memorySounds{}, tt.lock,
+				&config.Recommendations{RefreshInterval: time.Hour, TopK: 50}, testLogger())
+
+			s.rebuild(context.Background())
+
+			if repo.rebuilds != tt.wantRebuilds {
+				t.Fatalf("rebuilds = %d, want %d", repo.rebuilds, tt.wantRebuilds)
+			}
+			if len(tt.lock.released) != tt.wantReleased {
+				t.Fatalf("released = %v, want %d releases", tt.lock.released, tt.wantReleased)
+			}
+			if tt.lock.held != tt.wantHeld {
+				t.Fatalf("held = %v, want %v", tt.lock.held, tt.wantHeld)
+			}
+		})
+	}
+}
+
+func TestRecommendationServiceRebuildOncePerInterval(t *testing.T) {
+	repo := &countingRecommendations{}
+	lock := &fakeRebuildLock{}
+	cfg := &config.Recommendations{RefreshInterval: time.Hour, TopK: 50}
+
+	replicas := []*RecommendationService{
+		NewRecommendationService(repo, &memorySounds{}, lock, cfg, testLogger()),
+		NewRecommendationService(repo, &memorySounds{}, lock, cfg, testLogger()),
+		NewRecommendationService(repo, &memorySounds{}, lock, cfg, testLogger()),
+	}
+	for _, s := range replicas {
+		s.rebuild(context.Background())
+	}
+
+	if repo.rebuilds != 1 {
+		t.Fatalf("rebuilds = %d, want 1", repo.rebuilds)
+	}
+	if lock.ttl <= 0 || lock.ttl >= cfg.RefreshInterval {
+		t.Fatalf("lock ttl = %s, want shorter than the refresh interval", lock.ttl)
+	}
+}
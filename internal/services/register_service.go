@@ -4,6 +4,7 @@ import (
 	"context"
 	"soundtube/internal/domain/auth"
 	"soundtube/pkg"
+	"soundtube/pkg/metrics"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -11,11 +12,12 @@ import (
 type RegisterService struct {
 	repository   auth.IUserRepository
 	emailService auth.IEmailSener
+	metrics      *metrics.Metrics
 	logger       *pkg.CustomLogger
 }
 
-func NewRegisterService(repository auth.IUserRepository, email auth.IEmailSener, logger *pkg.CustomLogger) *RegisterService {
-	return &RegisterService{repository: repository, emailService: email, logger: logger}
+func NewRegisterService(repository auth.IUserRepository, email auth.IEmailSener, metrics *metrics.Metrics, logger *pkg.CustomLogger) *RegisterService {
+	return &RegisterService{repository: repository, emailService: email, metrics: metrics, logger: logger}
 }
 
 func (s *RegisterService) Register(с context.Context, username, email, password string) error {
@@ -24,45 +26,47 @@ func (s *RegisterService) Register(с context.Context, username, email, password
 
 	existenceUser, err := s.repository.GetUserByName(ctx, username)
 	if err != nil {
-		s.logger.Error("failed to check existence user", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "failed to check existence user", err)
 		return err
 	}
 
 	if existenceUser != nil {
 		err = UserAlreadyExits
-		s.logger.Warn("user already exists", err).WithTrace(ctx)
+		s.logger.WarnContext(ctx, "user already exists", err)
 		return err
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		s.logger.Error("hashing password failed", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "hashing password failed", err)
 		return err
 	}
 
 	verifyToken, err := generateVerifyToken()
 	if err != nil {
-		s.logger.Error("failed to generate verification token", err)
+		s.logger.ErrorContext(ctx, "failed to generate verification token", err)
 		return err
 	}
 
 	user, err := auth.NewUser(username, email, string(hashedPassword), verifyToken)
 	if err != nil {
-		s.logger.Error("invalid user params", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "invalid user params", err)
 		return err
 	}
 
-	err = s.repository.CreateUser(ctx, user)
+	verification, err := s.emailService.VerificationEmail(ctx, email, verifyToken)
 	if err != nil {
-		s.logger.Error("db error", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "verification email failed", err)
 		return err
 	}
 
-	if err := s.emailService.SendVerificationEmail(ctx, email, verifyToken); err != nil {
-		s.logger.Error("verification email failed", err).WithTrace(ctx)
+	err = s.repository.CreateUser(ctx, user, verification)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
 		return err
 	}
 
-	s.logger.Info("user succesful registrated", user.Username()).WithTrace(ctx)
+	s.metrics.Registered()
+	s.logger.InfoContext(ctx, "user succesful registrated", user.Username())
 	return nil
 }
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"soundtube/internal/domain/search"
+	"soundtube/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type SearchService struct {
+	repository search.ISearchRepository
+	logger     *pkg.CustomLogger
+}
+
+func NewSearchService(repository search.ISearchRepository, logger *pkg.CustomLogger) *SearchService {
+	return &SearchService{repository: repository, logger: logger}
+}
+
+// Search returns ranked hits of the requested types along with per-type totals,
+// which are counted over all types so clients can render facet tabs.
+func (s *SearchService) Search(ctx context.Context, text string, types []string, limit, offset int) (*search.ResultsDTO, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "SearchService.Search")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("search.query", text),
+		attribute.StringSlice("search.types", types),
+	)
+
+	q, err := search.NewQuery(text, types, limit, offset)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid search query", err)
+		return nil, fmt.Errorf("%w: %v", InvalidSearchQuery, err)
+	}
+
+	hits, err := s.repository.Search(ctx, q)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	facets, err := s.repository.CountByType(ctx, q)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	return search.NewResultsDTO(q.Text(), hits, facets), nil
+}
+
+func (s *SearchService) Suggest(ctx context.Context, text string, limit int) ([]*search.Suggestion, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "SearchService.Suggest")
+	defer span.End()
+
+	prefix := search.PrefixQuery(text)
+	if prefix == "" {
+		return []*search.Suggestion{}, nil
+	}
+
+	suggestions, err := s.repository.Suggest(ctx, prefix, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return nil, err
+	}
+
+	if suggestions == nil {
+		suggestions = []*search.Suggestion{}
+	}
+
+	return suggestions, nil
+}
@@ -3,81 +3,175 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"soundtube/internal/domain/album"
 	"soundtube/internal/domain/auth"
+	"soundtube/internal/domain/feed"
+	"soundtube/internal/domain/realtime"
 	"soundtube/internal/domain/sound"
 	"soundtube/pkg"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type SoundService struct {
 	repository sound.ISoundRepository
 	logger     *pkg.CustomLogger
 	user       auth.IUserRepositoryReader
+	activity   feed.IActivityPublisher
+	albums     album.IAlbumRepository
+	events     realtime.IEventPublisher
 }
 
-func NewSoundService(repository sound.ISoundRepository, user auth.IUserRepositoryReader, logger *pkg.CustomLogger) *SoundService {
-	return &SoundService{repository: repository, logger: logger, user: user}
+func NewSoundService(repository sound.ISoundRepository, user auth.IUserRepositoryReader, albums album.IAlbumRepository, activity feed.IActivityPublisher,
+	events realtime.IEventPublisher, logger *pkg.CustomLogger) *SoundService {
+	return &SoundService{repository: repository, logger: logger, user: user, albums: albums, activity: activity, events: events}
 }
 
-func (s *SoundService) CreateSound(ctx context.Context, name, album, genre string, authorID int) error {
+// CreateSound stores a new sound. The album can be given either by id or, for older
+// clients, by title, in which case the author's album with that title is reused or created.
+func (s *SoundService) CreateSound(ctx context.Context, name, genre string, albumID int, albumTitle string, trackNumber, authorID int) error {
 	ctx, span := s.logger.GetTracer().Start(ctx, "SoundService.CreateSound")
 	defer span.End()
 
 	userExists, err := s.user.UserExists(ctx, authorID)
 	if err != nil {
-		s.logger.Error("db error checking user", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "db error checking user", err)
 		return err
 	}
 
 	if !userExists {
 		err = errors.New("user does not exist")
-		s.logger.Error("invalid user id", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "invalid user id", err)
+		return err
+	}
+
+	albumID, err = s.resolveAlbum(ctx, authorID, albumID, albumTitle)
+	if err != nil {
 		return err
 	}
 
-	sound, err := sound.NewSound(name, album, genre, authorID)
+	if albumID != 0 && trackNumber == 0 {
+		if trackNumber, err = s.repository.GetNextTrackNumber(ctx, albumID); err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			return err
+		}
+	}
+
+	sound, err := sound.NewSound(name, genre, authorID, albumID, trackNumber)
 	if err != nil {
-		s.logger.Error("invalid sound params", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "invalid sound params", err)
 		return err
 	}
 
 	existsSound, err := s.repository.GetSoundByName(ctx, name)
 	if err != nil {
-		s.logger.Error("db error", err)
+		s.logger.ErrorContext(ctx, "db error", err)
 		return err
 	}
 
 	if existsSound != nil {
 		err = errors.New("user already exits")
-		s.logger.Error("invalid sound params", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "invalid sound params", err)
 		return err
 	}
 
-	err = s.repository.CreateSound(ctx, sound)
+	soundID, err := s.repository.CreateSound(ctx, sound)
 	if err != nil {
-		s.logger.Error("db error", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "db error", err)
 		return err
 	}
 
+	if err = s.activity.Publish(ctx, authorID, feed.TypeSound, soundID); err != nil {
+		s.logger.WarnContext(ctx, "failed to publish sound activity", err)
+	}
+
 	return nil
 }
 
+func (s *SoundService) resolveAlbum(ctx context.Context, authorID, albumID int, albumTitle string) (int, error) {
+	if albumID != 0 {
+		existing, err := s.albums.GetAlbumByID(ctx, albumID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			return 0, err
+		}
+		if existing == nil || !existing.IsOwnedBy(authorID) {
+			s.logger.WarnContext(ctx, "invalid album id", AlbumNotFound)
+			return 0, AlbumNotFound
+		}
+		return albumID, nil
+	}
+
+	if albumTitle == "" {
+		return 0, nil
+	}
+
+	existing, err := s.albums.GetAlbumByTitle(ctx, authorID, albumTitle)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return 0, err
+	}
+	if existing != nil {
+		return existing.ID(), nil
+	}
+
+	created, err := album.NewAlbum(authorID, albumTitle, "")
+	if err != nil {
+		s.logger.ErrorContext(ctx, "invalid album params", err)
+		return 0, err
+	}
+
+	id, err := s.albums.CreateAlbum(ctx, created)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "db error", err)
+		return 0, err
+	}
+
+	return id, nil
+}
+
 func (s *SoundService) DeleteSound(ctx context.Context, name string) error {
 	_, span := s.logger.GetTracer().Start(ctx, "SoundService.DeleteSound")
 	defer span.End()
 	return nil
 }
 
-func (s *SoundService) GetSounds(ctx context.Context) ([]*sound.Sound, error) {
-	ctx, span := s.logger.GetTracer().Start(ctx, "SoundService.CreateSound")
+// FindSounds returns a page of the sound listing. An author filter naming an unknown
+// user simply yields an empty page.
+func (s *SoundService) FindSounds(ctx context.Context, query *sound.Query, authorName string) (*sound.Page, error) {
+	ctx, span := s.logger.GetTracer().Start(ctx, "SoundService.FindSounds")
 	defer span.End()
 
-	sounds, err := s.repository.GetSounds(ctx)
+	span.SetAttributes(
+		attribute.String("sound.sort", query.Sort),
+		attribute.Int("sound.limit", query.Limit),
+	)
+
+	if err := query.Validate(); err != nil {
+		s.logger.WarnContext(ctx, "invalid sound query", err)
+		return nil, fmt.Errorf("%w: %v", InvalidSoundQuery, err)
+	}
+
+	if authorName != "" {
+		author, err := s.user.GetUserByName(ctx, authorName)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "db error", err)
+			return nil, err
+		}
+		if author == nil {
+			return &sound.Page{Sounds: []*sound.Sound{}}, nil
+		}
+		query.AuthorID = author.ID()
+	}
+
+	page, err := s.repository.FindSounds(ctx, query)
 	if err != nil {
-		s.logger.Error("db error", err)
+		s.logger.ErrorContext(ctx, "db error", err)
 		return nil, err
 	}
 
-	return sounds, nil
+	return page, nil
 }
 
 func (s *SoundService) UpdateSoundFile(ctx context.Context, name, filename, filepath string, fileSize int64) error {
@@ -86,9 +180,46 @@ func (s *SoundService) UpdateSoundFile(ctx context.Context, name, filename, file
 
 	err := s.repository.UpdateSoundFile(ctx, name, filename, filepath, fileSize)
 	if err != nil {
-		s.logger.Error("failed to update sound file info in repository", err).WithTrace(ctx)
+		s.logger.ErrorContext(ctx, "failed to update sound file info in repository", err)
 		return err
 	}
 
+	s.publishStatus(ctx, name, realtime.StatusUploaded)
+
 	return nil
 }
+
+func (s *SoundService) publishStatus(ctx context.Context, name, status string) {
+	snd, err := s.repository.GetSoundByName(ctx, name)
+	if err != nil || snd == nil {
+		s.logger.WarnContext(ctx, "failed to load sound for status event", err)
+		return
+	}
+
+	event, err := realtime.NewEvent(realtime.EventStatus, snd.ID(), realtime.StatusChange{Status: status, FileFormat: snd.FileFormat()})
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to build status event", err)
+		return
+	}
+
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.WarnContext(ctx, "failed to publish status event", err)
+	}
+}
+
+// orderSoundsByIDs arranges sounds in the order of ids, dropping ids that were not found.
+func orderSoundsByIDs(sounds []*sound.Sound, ids []int) []*sound.Sound {
+	soundsByID := make(map[int]*sound.Sound, len(sounds))
+	for _, snd := range sounds {
+		soundsByID[snd.ID()] = snd
+	}
+
+	ordered := make([]*sound.Sound, 0, len(ids))
+	for _, id := range ids {
+		if snd, exists := soundsByID[id]; exists {
+			ordered = append(ordered, snd)
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,138 @@
+package pkg
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPResolver finds the address of the client behind a chain of reverse proxies.
+// Forwarding headers are only believed when the connection comes from a trusted proxy,
+// and the chain is walked right to left so a client cannot spoof its address by
+// sending its own X-Forwarded-For: the first untrusted hop is the client.
+type ClientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+// NewClientIPResolver accepts CIDRs ("10.0.0.0/8") and bare addresses ("127.0.0.1").
+func NewClientIPResolver(trustedProxies []string) (*ClientIPResolver, error) {
+	var resolver = ClientIPResolver{trusted: make([]netip.Prefix, 0, len(trustedProxies))}
+
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			resolver.trusted = append(resolver.trusted, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		resolver.trusted = append(resolver.trusted, prefix.Masked())
+	}
+
+	return &resolver, nil
+}
+
+func (r *ClientIPResolver) ClientIP(req *http.Request) string {
+	remote, ok := parseHop(req.RemoteAddr)
+	if !ok {
+		return req.RemoteAddr
+	}
+
+	if !r.isTrusted(remote) {
+		return remote.String()
+	}
+
+	hops := forwardedFor(req.Header)
+	if len(hops) == 0 {
+		hops = splitHeader(req.Header.Values("X-Forwarded-For"))
+	}
+
+	if len(hops) == 0 {
+		if realIP, ok := parseHop(req.Header.Get("X-Real-IP")); ok {
+			return realIP.String()
+		}
+		return remote.String()
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseHop(hops[i])
+		if !ok {
+			// "unknown" or an obfuscated identifier: nothing further left can be trusted.
+			break
+		}
+
+		client = hop
+		if !r.isTrusted(hop) {
+			break
+		}
+	}
+
+	return client.String()
+}
+
+func (r *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor returns the for= parameters of RFC 7239 Forwarded headers in order.
+func forwardedFor(header http.Header) []string {
+	var hops []string
+
+	for _, element := range splitHeader(header.Values("Forwarded")) {
+		for _, pair := range strings.Split(element, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if found && strings.EqualFold(key, "for") {
+				hops = append(hops, strings.Trim(value, `"`))
+			}
+		}
+	}
+
+	return hops
+}
+
+func splitHeader(values []string) []string {
+	var parts []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return parts
+}
+
+// parseHop reads "ip", "ip:port", "[ipv6]" or "[ipv6]:port".
+func parseHop(hop string) (netip.Addr, bool) {
+	hop = strings.TrimSpace(hop)
+	if host, _, err := net.SplitHostPort(hop); err == nil {
+		hop = host
+	}
+	hop = strings.TrimSuffix(strings.TrimPrefix(hop, "["), "]")
+
+	addr, err := netip.ParseAddr(hop)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap(), true
+}
@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIPResolver(t *testing.T) {
+	resolver, err := NewClientIPResolver([]string{"10.0.0.0/8", "127.0.0.1", " fd00::/8 ", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		remote    string
+		xff       []string
+		forwarded []string
+		realIP    string
+		want      string
+	}{
+		{name: "direct client", remote: "203.0.113.7:5000", want: "203.0.113.7"},
+		{name: "untrusted peer cannot spoof", remote: "203.0.113.7:5000", xff: []string{"1.1.1.1"}, want: "203.0.113.7"},
+		{name: "trusted proxy without headers", remote: "10.0.0.2:443", want: "10.0.0.2"},
+		{name: "single proxy", remote: "10.0.0.2:443", xff: []string{"198.51.100.4"}, want: "198.51.100.4"},
+		{name: "client spoofed left entry", remote: "10.0.0.2:443", xff: []string{"1.1.1.1, 198.51.100.4"}, want: "198.51.100.4"},
+		{name: "chain of trusted proxies", remote: "127.0.0.1:443", xff: []string{"198.51.100.4, 10.1.2.3, 10.0.0.9"}, want: "198.51.100.4"},
+		{name: "repeated headers", remote: "10.0.0.2:443", xff: []string{"1.1.1.1", "198.51.100.4, 10.0.0.3"}, want: "198.51.100.4"},
+		{name: "all hops trusted", remote: "10.0.0.2:443", xff: []string{"10.0.0.5, 10.0.0.6"}, want: "10.0.0.5"},
+		{name: "unparsable hop stops the walk", remote: "10.0.0.2:443", xff: []string{"198.51.100.4, unknown, 10.0.0.3"}, want: "10.0.0.3"},
+		{name: "ipv4-mapped ipv6 peer", remote: "[::ffff:10.0.0.2]:443", xff: []string{"198.51.100.4"}, want: "198.51.100.4"},
+		{name: "ipv6 hop", remote: "[fd00::1]:443", xff: []string{"2001:db8::1"}, want: "2001:db8::1"},
+		{name: "forwarded header wins", remote: "10.0.0.2:443", xff: []string{"1.1.1.1"},
+			forwarded: []string{`for=198.51.100.4;proto=https, for="[2001:db8::2]:4711"`}, want: "2001:db8::2"},
+		{name: "forwarded with trusted hop", remote: "10.0.0.2:443",
+			forwarded: []string{`for=198.51.100.4`, `For=10.0.0.3`}, want: "198.51.100.4"},
+		{name: "real ip without forwarding headers", remote: "10.0.0.2:443", realIP: "198.51.100.4", want: "198.51.100.4"},
+		{name: "real ip from untrusted peer ignored", remote: "203.0.113.7:5000", realIP: "198.51.100.4", want: "203.0.113.7"},
+		{name: "unparsable remote address", remote: "pipe", want: "pipe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remote
+			for _, value := range tt.xff {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			for _, value := range tt.forwarded {
+				req.Header.Add("Forwarded", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := resolver.ClientIP(req); got != tt.want {
+				t.Fatalf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientIPResolverRejectsInvalidProxies(t *testing.T) {
+	for _, proxy := range []string{"10.0.0.0/33", "not-an-ip", "10.0.0"} {
+		if _, err := NewClientIPResolver([]string{proxy}); err == nil {
+			t.Errorf("NewClientIPResolver(%q) succeeded, want an error", proxy)
+		}
+	}
+}
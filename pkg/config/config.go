@@ -1,7 +1,6 @@
 package config
 
 import (
-	"errors"
 	"time"
 
 	"github.com/spf13/viper"
@@ -9,24 +8,48 @@ import (
 
 type Config struct {
 	Environment         Environment         `mapstructure:"environment"`
+	Logging             Logging             `mapstructure:"logging"`
 	Redis               Redis               `mapstructure:"redis"`
 	Database            Database            `mapstructure:"database"`
 	DatabaseConnections DatabaseConnections `mapstructure:"database_connections"`
 	Server              Server              `mapstructure:"server"`
+	Security            Security            `mapstructure:"security"`
 	Traycing            Traycing            `mapstructure:"traycing"`
 	Token               Token               `mapstructure:"token"`
 	Email               Email               `mapstructure:"email"`
 	RateLimiter         RateLimiter         `mapstructure:"rate_limiter"`
+	Export              Export              `mapstructure:"export"`
+	Feed                Feed                `mapstructure:"feed"`
+	Listening           Listening           `mapstructure:"listening"`
+	Charts              Charts              `mapstructure:"charts"`
+	Recommendations     Recommendations     `mapstructure:"recommendations"`
+	Realtime            Realtime            `mapstructure:"realtime"`
+	Digest              Digest              `mapstructure:"digest"`
+	Features            Features            `mapstructure:"features"`
+	Metrics             Metrics             `mapstructure:"metrics"`
+	Health              Health              `mapstructure:"health"`
 }
 
 type Environment struct {
 	Current string `mapstructure:"current"`
 }
 
+// Logging selects the log level (debug, info, warn, error) and format (json, text).
+// Empty values fall back to debug/text in development and info/json elsewhere.
+type Logging struct {
+	Level  string `mapstructure:"level"`
+	Format string `mapstructure:"format"`
+}
+
+// Traycing configures the OTLP exporter. Protocol is "grpc" (default) or "http";
+// SampleRatio applies to root spans, child spans follow their parent's decision.
 type Traycing struct {
-	Enabled     bool   `mapstructure:"enabled"`
-	ServiceName string `mapstructure:"service_name"`
-	Endpoint    string `mapstructure:"endpoint"`
+	Enabled     bool    `mapstructure:"enabled"`
+	ServiceName string  `mapstructure:"service_name"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Protocol    string  `mapstructure:"protocol"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
 }
 
 type Redis struct {
@@ -44,66 +67,292 @@ type Database struct {
 }
 
 type DatabaseConnections struct {
-	MaxOpenConns    int `mapstructure:"max_open_conns"`
-	MaxIdleConns    int `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime int `mapstructure:"max_life_time"`
-	ConnMaxIdleTime int `mapstructure:"max_idle_time"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"max_life_time"`
+	ConnMaxIdleTime time.Duration `mapstructure:"max_idle_time"`
 }
 
+// Server.TrustedProxies lists the CIDRs or addresses of reverse proxies whose
+// Forwarded, X-Forwarded-For and X-Real-IP headers are believed.
+// Server.PublicURL is the absolute address clients reach the API at, used for links in
+// emails and exports. Host and Port are only what the server listens on.
+// Server.BaseDir anchors the relative directories in the config (static files, exports,
+// dropped mail and email templates); see Source for its default.
 type Server struct {
-	Host         string `mapstructure:"host"`
-	Port         string `mapstructure:"port"`
-	CookieSecure bool   `mapstructure:"cookie_secure"`
-	ReadTimeout  int    `mapstructure:"read_timeout"`
-	WriteTimeout int    `mapstructure:"write_timeout"`
-	IdleTimeout  int    `mapstructure:"idle_timeout"`
+	BaseDir        string        `mapstructure:"base_dir"`
+	StaticDir      string        `mapstructure:"static_dir"`
+	Host           string        `mapstructure:"host"`
+	Port           string        `mapstructure:"port"`
+	PublicURL      string        `mapstructure:"public_url"`
+	CookieSecure   bool          `mapstructure:"cookie_secure"`
+	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout    time.Duration `mapstructure:"idle_timeout"`
+	TrustedProxies []string      `mapstructure:"trusted_proxies"`
+}
+
+// Security configures CORS and the response security headers. CSP maps directives to
+// source lists; the 'nonce' source is replaced with a per-request nonce. API routes get
+// a locked-down policy of their own.
+type Security struct {
+	CORS              CORS              `mapstructure:"cors"`
+	CSP               map[string]string `mapstructure:"csp"`
+	ReferrerPolicy    string            `mapstructure:"referrer_policy"`
+	PermissionsPolicy string            `mapstructure:"permissions_policy"`
+}
+
+// CORS lists the origins allowed to call the API from a browser; empty means same-origin
+// only. AllowCredentials cannot be combined with the "*" origin. MaxAge is how long, in
+// seconds, browsers may cache a preflight answer.
+type CORS struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`
+	AllowedMethods   []string `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string `mapstructure:"allowed_headers"`
+	ExposedHeaders   []string `mapstructure:"exposed_headers"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	MaxAge           int      `mapstructure:"max_age"`
 }
 
 type Token struct {
-	JwtKey string `mapstructure:"jwt_key"`
-	Exp    int    `mapstructure:"exp"`
+	JwtKey string        `mapstructure:"jwt_key"`
+	Exp    time.Duration `mapstructure:"exp"`
 }
 
+// Email.TemplatesDir replaces the embedded email templates with a directory of the same
+// layout, so they can be edited and reloaded without a rebuild.
 type Email struct {
 	SMTHost  string `mapstructure:"smtHost"`
 	SMTPort  string `mapstructure:"smtPort"`
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
 	From     string `mapstructure:"from"`
+	Locale   string `mapstructure:"locale"`
+
+	TemplatesDir string `mapstructure:"templates_dir"`
+
+	Transport      string        `mapstructure:"transport"`
+	DropDir        string        `mapstructure:"drop_dir"`
+	OutboxInterval time.Duration `mapstructure:"outbox_interval"`
+	BatchSize      int           `mapstructure:"batch_size"`
+	MaxAttempts    int           `mapstructure:"max_attempts"`
 }
 
+// RateLimiter holds the default per-IP policy (MaxRequests per Window) and the
+// named policies applied to route groups. Backend is "redis" (shared between instances,
+// falling back to local limits while Redis is down) or "memory".
 type RateLimiter struct {
-	MaxRequests int `mapstructure:"max_requests"`
-	Window      int `mapstructure:"window"`
+	Backend     string                     `mapstructure:"backend"`
+	MaxRequests int                        `mapstructure:"max_requests"`
+	Window      time.Duration              `mapstructure:"window"`
+	Policies    map[string]RateLimitPolicy `mapstructure:"policies"`
+}
+
+type RateLimitPolicy struct {
+	MaxRequests int           `mapstructure:"max_requests"`
+	Window      time.Duration `mapstructure:"window"`
+	PerUser     bool          `mapstructure:"per_user"`
+}
+
+// Export.Secret signs download links. It is kept apart from the JWT key so that one
+// leaking or being rotated does not affect the other.
+type Export struct {
+	Dir       string        `mapstructure:"dir"`
+	LinkTTL   time.Duration `mapstructure:"link_ttl"`
+	QueueSize int           `mapstructure:"queue_size"`
+	Secret    string        `mapstructure:"secret"`
+}
+
+type Feed struct {
+	FanoutThreshold int           `mapstructure:"fanout_threshold"`
+	MaxLength       int           `mapstructure:"max_length"`
+	TTL             time.Duration `mapstructure:"ttl"`
+}
+
+type Listening struct {
+	PlayThreshold int           `mapstructure:"play_threshold"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	QueueSize     int           `mapstructure:"queue_size"`
+	SessionTTL    time.Duration `mapstructure:"session_ttl"`
+}
+
+type Charts struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	Size            int           `mapstructure:"size"`
+}
+
+type Recommendations struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	TopK            int           `mapstructure:"top_k"`
 }
 
-func LoadConfig() (*Config, error) {
-	viper.SetConfigName("dev")
-	viper.AddConfigPath("../.././configs")
+type Realtime struct {
+	SendBuffer       int           `mapstructure:"send_buffer"`
+	MaxSubscriptions int           `mapstructure:"max_subscriptions"`
+	HistoryLength    int           `mapstructure:"history_length"`
+	HistoryTTL       time.Duration `mapstructure:"history_ttl"`
+	Heartbeat        time.Duration `mapstructure:"heartbeat"`
+}
+
+type Digest struct {
+	Interval          time.Duration `mapstructure:"interval"`
+	DefaultFrequency  string        `mapstructure:"default_frequency"`
+	MaxItems          int           `mapstructure:"max_items"`
+	BatchSize         int           `mapstructure:"batch_size"`
+	UnsubscribeSecret string        `mapstructure:"unsubscribe_secret"`
+}
+
+// Features holds the feature flags and their defaults. Admins can override a flag at
+// runtime; overrides are stored in the database and re-read every RefreshInterval and
+// whenever another instance announces a change.
+type Features struct {
+	RefreshInterval time.Duration          `mapstructure:"refresh_interval"`
+	Flags           map[string]FeatureFlag `mapstructure:"flags"`
+}
+
+// FeatureFlag turns a feature on for the listed users and roles and for Percentage
+// percent of the other signed-in users. Enabled off disables it for everyone.
+type FeatureFlag struct {
+	Description string   `mapstructure:"description"`
+	Enabled     bool     `mapstructure:"enabled"`
+	Percentage  int      `mapstructure:"percentage"`
+	Users       []int    `mapstructure:"users"`
+	Roles       []string `mapstructure:"roles"`
+}
+
+// Metrics exposes /metrics on its own admin listener when Addr is set, otherwise on the
+// public server behind Token. Without either the endpoint is not mounted. The admin
+// listener stays on loopback by default; binding it anywhere else requires Token.
+type Metrics struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Addr    string `mapstructure:"addr"`
+	Token   string `mapstructure:"token"`
+}
+
+// Health tunes /health and /ready. Reports are cached for CacheTTL so that frequent
+// probes do not each hit every dependency, and Timeout bounds each check. On SIGTERM
+// /ready fails at once and the server keeps serving for DrainDelay before it stops
+// accepting connections, giving load balancers time to take it out of rotation.
+type Health struct {
+	CacheTTL   time.Duration `mapstructure:"cache_ttl"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
+}
+
+// LoadConfig reads the profile selected by source, then applies SOUNDTUBE_* environment
+// overrides and *_FILE secrets on top of it. The result is validated as a whole, so a
+// broken deployment reports every problem at once.
+func LoadConfig(source Source) (*Config, error) {
+	v := viper.New()
+	setDefaults(v)
 
-	if err := viper.ReadInConfig(); err != nil {
+	if err := readConfigFile(v, source); err != nil {
 		return nil, err
 	}
 
-	viper.AutomaticEnv()
-
-	viper.SetDefault("environment.current", "development")
-	viper.SetDefault("server.port", "8080")
-	viper.SetDefault("ratelimit.maxrequests", 100)
-	viper.SetDefault("ratelimit.window", time.Minute)
+	if err := bindEnv(v); err != nil {
+		return nil, err
+	}
 
 	var config Config
-	err := viper.Unmarshal(&config)
-	if err != nil {
+	if err := v.Unmarshal(&config, viper.DecodeHook(decodeHook())); err != nil {
 		return nil, err
 	}
-	if &config.Traycing == nil {
-		return nil, errors.New("config loading failed")
-	}
 
-	if len(config.Token.JwtKey) < 16 {
-		panic("invalid jwt key")
+	config.resolvePaths(source.baseDir(v.ConfigFileUsed()))
+
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
 	return &config, nil
 }
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("environment.current", "development")
+	v.SetDefault("server.static_dir", "static")
+	v.SetDefault("server.port", "8080")
+	v.SetDefault("server.public_url", "http://localhost:8080")
+	v.SetDefault("server.trusted_proxies", []string{"127.0.0.1", "::1"})
+	v.SetDefault("security.cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE"})
+	v.SetDefault("security.cors.allowed_headers", []string{"Authorization", "Content-Type", "X-Request-ID", "Last-Event-ID"})
+	v.SetDefault("security.cors.exposed_headers", []string{"X-Request-ID", "Link", "Retry-After",
+		"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"})
+	v.SetDefault("security.cors.max_age", 600)
+	v.SetDefault("security.csp", map[string]any{
+		"default-src":     "'self'",
+		"script-src":      "'self' 'nonce'",
+		"style-src":       "'self'",
+		"img-src":         "'self' data:",
+		"media-src":       "'self' blob:",
+		"connect-src":     "'self'",
+		"object-src":      "'none'",
+		"base-uri":        "'self'",
+		"form-action":     "'self'",
+		"frame-ancestors": "'none'",
+	})
+	v.SetDefault("security.referrer_policy", "strict-origin-when-cross-origin")
+	v.SetDefault("security.permissions_policy", "camera=(), microphone=(), geolocation=(), payment=(), usb=()")
+	v.SetDefault("token.exp", "1h")
+	v.SetDefault("traycing.service_name", "soundtube-api")
+	v.SetDefault("traycing.endpoint", "localhost:4317")
+	v.SetDefault("traycing.protocol", "grpc")
+	v.SetDefault("traycing.insecure", true)
+	v.SetDefault("traycing.sample_ratio", 1.0)
+	v.SetDefault("rate_limiter.backend", "redis")
+	v.SetDefault("rate_limiter.max_requests", 100)
+	v.SetDefault("rate_limiter.window", "1m")
+	v.SetDefault("rate_limiter.policies.auth.max_requests", 20)
+	v.SetDefault("rate_limiter.policies.auth.window", "1m")
+	v.SetDefault("rate_limiter.policies.login.max_requests", 5)
+	v.SetDefault("rate_limiter.policies.login.window", "1m")
+	v.SetDefault("rate_limiter.policies.user.max_requests", 300)
+	v.SetDefault("rate_limiter.policies.user.window", "1m")
+	v.SetDefault("rate_limiter.policies.user.per_user", true)
+	v.SetDefault("rate_limiter.policies.upload.max_requests", 20)
+	v.SetDefault("rate_limiter.policies.upload.window", "1h")
+	v.SetDefault("rate_limiter.policies.upload.per_user", true)
+	v.SetDefault("export.dir", "exports")
+	v.SetDefault("export.link_ttl", "24h")
+	v.SetDefault("export.queue_size", 16)
+	v.SetDefault("feed.fanout_threshold", 1000)
+	v.SetDefault("feed.max_length", 500)
+	v.SetDefault("feed.ttl", "168h")
+	v.SetDefault("listening.play_threshold", 30)
+	v.SetDefault("listening.batch_size", 200)
+	v.SetDefault("listening.flush_interval", "5s")
+	v.SetDefault("listening.queue_size", 4096)
+	v.SetDefault("listening.session_ttl", "12h")
+	v.SetDefault("charts.refresh_interval", "10m")
+	v.SetDefault("charts.size", 200)
+	v.SetDefault("recommendations.refresh_interval", "1h")
+	v.SetDefault("recommendations.top_k", 50)
+	v.SetDefault("realtime.send_buffer", 64)
+	v.SetDefault("realtime.max_subscriptions", 100)
+	v.SetDefault("realtime.history_length", 100)
+	v.SetDefault("realtime.history_ttl", "1h")
+	v.SetDefault("realtime.heartbeat", "15s")
+	v.SetDefault("email.locale", "en")
+	v.SetDefault("email.transport", "smtp")
+	v.SetDefault("email.drop_dir", "mail")
+	v.SetDefault("email.outbox_interval", "10s")
+	v.SetDefault("email.batch_size", 50)
+	v.SetDefault("email.max_attempts", 8)
+	v.SetDefault("digest.interval", "15m")
+	v.SetDefault("digest.default_frequency", "weekly")
+	v.SetDefault("digest.max_items", 10)
+	v.SetDefault("digest.batch_size", 100)
+	v.SetDefault("features.refresh_interval", "1m")
+	v.SetDefault("features.flags.comments.description", "Comments on sounds")
+	v.SetDefault("features.flags.comments.enabled", true)
+	v.SetDefault("features.flags.comments.percentage", 100)
+	v.SetDefault("features.flags.recommendations.description", "Personal recommendations and similar sounds")
+	v.SetDefault("features.flags.recommendations.enabled", true)
+	v.SetDefault("features.flags.recommendations.percentage", 100)
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.addr", "127.0.0.1:9090")
+	v.SetDefault("health.cache_ttl", "5s")
+	v.SetDefault("health.timeout", "2s")
+	v.SetDefault("health.drain_delay", "5s")
+}
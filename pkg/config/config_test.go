@@ -0,0 +1,358 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const baseYAML = `
+environment:
+  current: test
+database:
+  host: localhost
+  dbname: soundtube
+  user: soundtube
+redis:
+  addr: localhost:6379
+email:
+  smtHost: localhost
+token:
+  jwt_key: jwt-key-0123456789
+export:
+  secret: export-secret-0123456789
+digest:
+  unsubscribe_secret: unsubscribe-secret-0123456789
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	secret := writeFile(t, "jwt_key", "jwt-key-from-file-0123\n")
+
+	tests := []struct {
+		name    string
+		yaml    string
+		env     map[string]string
+		check   func(t *testing.T, c *Config)
+		wantErr string
+	}{
+		{
+			name: "file and defaults",
+			yaml: baseYAML,
+			check: func(t *testing.T, c *Config) {
+				if c.Token.JwtKey != "jwt-key-0123456789" || c.Server.Port == "" || c.Metrics.Addr != "127.0.0.1:9090" {
+					t.Fatalf("config = %+v", c)
+				}
+			},
+		},
+		{
+			name: "environment overrides the file",
+			yaml: baseYAML,
+			env:  map[string]string{"SOUNDTUBE_DATABASE_HOST": "db.internal", "SOUNDTUBE_FEED_TTL": "48h"},
+			check: func(t *testing.T, c *Config) {
+				if c.Database.Host != "db.internal" || c.Feed.TTL != 48*time.Hour {
+					t.Fatalf("database.host = %q, feed.ttl = %s", c.Database.Host, c.Feed.TTL)
+				}
+			},
+		},
+		{
+			name: "_FILE secret wins over the variable",
+			yaml: baseYAML,
+			env:  map[string]string{"SOUNDTUBE_TOKEN_JWT_KEY": "jwt-key-from-env-0123", "SOUNDTUBE_TOKEN_JWT_KEY_FILE": secret},
+			check: func(t *testing.T, c *Config) {
+				if c.Token.JwtKey != "jwt-key-from-file-0123" {
+					t.Fatalf("token.jwt_key = %q", c.Token.JwtKey)
+				}
+			},
+		},
+		{
+			name: "lists from the environment",
+			yaml: baseYAML,
+			env:  map[string]string{"SOUNDTUBE_SERVER_TRUSTED_PROXIES": "10.0.0.0/8, 127.0.0.1"},
+			check: func(t *testing.T, c *Config) {
+				if !slices.Equal(c.Server.TrustedProxies, []string{"10.0.0.0/8", "127.0.0.1"}) {
+					t.Fatalf("server.trusted_proxies = %q", c.Server.TrustedProxies)
+				}
+			},
+		},
+		{
+			name:    "missing _FILE",
+			yaml:    baseYAML,
+			env:     map[string]string{"SOUNDTUBE_EXPORT_SECRET_FILE": filepath.Join(t.TempDir(), "missing")},
+			wantErr: "SOUNDTUBE_EXPORT_SECRET_FILE",
+		},
+		{
+			name:    "duration without a unit",
+			yaml:    baseYAML + "feed:\n  ttl: 24\n",
+			wantErr: "needs a unit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			c, err := LoadConfig(Source{File: writeFile(t, "config.yaml", tt.yaml)})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, c)
+		})
+	}
+}
+
+func TestLoadConfigReportsAllErrors(t *testing.T) {
+	_, err := LoadConfig(Source{File: writeFile(t, "config.yaml", "environment:\n  current: staging\n")})
+	if err == nil {
+		t.Fatal("invalid config loaded")
+	}
+
+	for _, want := range []string{"environment.current must be", "database.host is required", "token.jwt_key must be", "export.secret must be"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "staging.yaml"), []byte(baseYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{name: "existing profile", source: Source{Profile: "staging", Dirs: []string{dir}}},
+		{name: "missing profile", source: Source{Profile: "qa", Dirs: []string{dir}}, wantErr: true},
+		{name: "missing file", source: Source{File: filepath.Join(dir, "missing.yaml")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigResolvesPaths(t *testing.T) {
+	project := t.TempDir()
+	configs := filepath.Join(project, "configs")
+	if err := os.Mkdir(configs, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configs, "staging.yaml"), []byte(baseYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file := writeFile(t, "soundtube.yaml", baseYAML)
+
+	tests := []struct {
+		name       string
+		source     Source
+		env        map[string]string
+		wantStatic string
+		wantExport string
+		wantMail   string
+	}{
+		{name: "profile in a configs directory", source: Source{Profile: "staging", Dirs: []string{configs}},
+			wantStatic: filepath.Join(project, "static"), wantExport: filepath.Join(project, "exports"), wantMail: filepath.Join(project, "mail")},
+		{name: "file outside a configs directory", source: Source{File: file}, wantStatic: filepath.Join(filepath.Dir(file), "static"),
+			wantExport: filepath.Join(filepath.Dir(file), "exports"), wantMail: filepath.Join(filepath.Dir(file), "mail")},
+		{name: "base dir set", source: Source{File: file}, env: map[string]string{"SOUNDTUBE_SERVER_BASE_DIR": "/srv/soundtube"},
+			wantStatic: "/srv/soundtube/static", wantExport: "/srv/soundtube/exports", wantMail: "/srv/soundtube/mail"},
+		{name: "absolute paths are kept", source: Source{File: file},
+			env:        map[string]string{"SOUNDTUBE_SERVER_STATIC_DIR": "/var/www", "SOUNDTUBE_EXPORT_DIR": "/var/exports"},
+			wantStatic: "/var/www", wantExport: "/var/exports", wantMail: filepath.Join(filepath.Dir(file), "mail")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			c, err := LoadConfig(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{c.Server.StaticDir, c.Export.Dir, c.Email.DropDir}
+			if want := []string{tt.wantStatic, tt.wantExport, tt.wantMail}; !slices.Equal(got, want) {
+				t.Fatalf("static, export and mail dirs = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSourceFromFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want Source
+	}{
+		{name: "nothing", want: Source{}},
+		{name: "flags", args: []string{"-profile", "prod", "-config", "/etc/soundtube.yaml"},
+			want: Source{Profile: "prod", File: "/etc/soundtube.yaml"}},
+		{name: "environment", env: map[string]string{"SOUNDTUBE_PROFILE": "test"}, want: Source{Profile: "test"}},
+		{name: "flags win over environment", args: []string{"-profile", "prod"}, env: map[string]string{"SOUNDTUBE_PROFILE": "test"},
+			want: Source{Profile: "prod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			source, err := SourceFromFlags(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if source.Profile != tt.want.Profile || source.File != tt.want.File {
+				t.Fatalf("source = %+v, want %+v", source, tt.want)
+			}
+		})
+	}
+}
+
+func TestReloadable(t *testing.T) {
+	current := validConfig(t)
+	next := validConfig(t)
+	next.Logging.Level = "warn"
+	next.Security.CORS.AllowedOrigins = []string{"https://app.example.com"}
+	next.Database.Host = "db.internal"
+	next.Server.Port = ":9999"
+
+	merged := current.Reloadable(next)
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "logging.level", want: true},
+		{key: "security.cors.allowed_origins", want: true},
+		{key: "database.host", want: false},
+		{key: "server.port", want: false},
+	}
+
+	changed := current.Changes(merged)
+	left := merged.Changes(next)
+	for _, tt := range tests {
+		if slices.Contains(changed, tt.key) != tt.want {
+			t.Errorf("%s applied = %v, want %v", tt.key, !tt.want, tt.want)
+		}
+		if slices.Contains(left, tt.key) == tt.want {
+			t.Errorf("%s left out = %v, want %v", tt.key, tt.want, !tt.want)
+		}
+	}
+}
+
+func TestChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+		want   []string
+	}{
+		{name: "identical", mutate: func(c *Config) {}, want: nil},
+		{name: "scalar", mutate: func(c *Config) { c.Logging.Level = "warn" }, want: []string{"logging.level"}},
+		{name: "nested struct", mutate: func(c *Config) { c.Security.CORS.MaxAge = 60 }, want: []string{"security.cors.max_age"}},
+		{name: "list as a whole", mutate: func(c *Config) {
+			c.Security.CORS.AllowedOrigins = append(c.Security.CORS.AllowedOrigins, "https://app.example.com")
+		}, want: []string{"security.cors.allowed_origins"}},
+		{name: "map as a whole", mutate: func(c *Config) {
+			c.RateLimiter.Policies = map[string]RateLimitPolicy{"login": {MaxRequests: 5, Window: time.Minute}}
+		}, want: []string{"rate_limiter.policies"}},
+		{name: "several", mutate: func(c *Config) {
+			c.Logging.Level = "warn"
+			c.Database.Host = "db.internal"
+		}, want: []string{"database.host", "logging.level"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := validConfig(t)
+			next := validConfig(t)
+			tt.mutate(next)
+
+			got := current.Changes(next)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Changes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRequired(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(*Config) {}},
+		{name: "unknown environment", mutate: func(c *Config) { c.Environment.Current = "staging" }, wantErr: "environment.current must be"},
+		{name: "short jwt key", mutate: func(c *Config) { c.Token.JwtKey = "short" }, wantErr: "token.jwt_key must be at least 16"},
+		{name: "export secret reuses jwt key", mutate: func(c *Config) { c.Export.Secret = c.Token.JwtKey }, wantErr: "export.secret must differ"},
+		{name: "unsubscribe secret reuses export secret", mutate: func(c *Config) { c.Digest.UnsubscribeSecret = c.Export.Secret },
+			wantErr: "digest.unsubscribe_secret must differ"},
+		{name: "relative public url", mutate: func(c *Config) { c.Server.PublicURL = "soundtube.example" }, wantErr: "server.public_url must be an absolute"},
+		{name: "plain http in production", mutate: func(c *Config) {
+			c.Environment.Current = "production"
+			c.Server.PublicURL = "http://soundtube.example"
+		}, wantErr: "server.public_url must use https in production"},
+		{name: "unknown transport", mutate: func(c *Config) { c.Email.Transport = "pigeon" }, wantErr: "email.transport must be"},
+		{name: "zero interval", mutate: func(c *Config) { c.Digest.Interval = 0 }, wantErr: "digest.interval must be positive"},
+		{name: "negative timeout", mutate: func(c *Config) { c.Server.ReadTimeout = -time.Second }, wantErr: "server.read_timeout must not be negative"},
+		{name: "percentage over 100", mutate: func(c *Config) {
+			c.Features.Flags = map[string]FeatureFlag{"beta": {Percentage: 150}}
+		}, wantErr: "features.flags.beta.percentage"},
+		{name: "missing static dir", mutate: func(c *Config) { c.Server.StaticDir = "" }, wantErr: "server.static_dir is required"},
+		{name: "missing export dir", mutate: func(c *Config) { c.Export.Dir = "" }, wantErr: "export.dir is required"},
+		{name: "zero export queue", mutate: func(c *Config) { c.Export.QueueSize = 0 }, wantErr: "export.queue_size must be positive"},
+		{name: "zero listening queue", mutate: func(c *Config) { c.Listening.QueueSize = 0 }, wantErr: "listening.queue_size must be positive"},
+		{name: "negative listening batch", mutate: func(c *Config) { c.Listening.BatchSize = -1 }, wantErr: "listening.batch_size must be positive"},
+		{name: "zero send buffer", mutate: func(c *Config) { c.Realtime.SendBuffer = 0 }, wantErr: "realtime.send_buffer must be positive"},
+		{name: "zero max subscriptions", mutate: func(c *Config) { c.Realtime.MaxSubscriptions = 0 }, wantErr: "realtime.max_subscriptions must be positive"},
+		{name: "zero chart size", mutate: func(c *Config) { c.Charts.Size = 0 }, wantErr: "charts.size must be positive"},
+		{name: "zero top k", mutate: func(c *Config) { c.Recommendations.TopK = 0 }, wantErr: "recommendations.top_k must be positive"},
+		{name: "zero email batch", mutate: func(c *Config) { c.Email.BatchSize = 0 }, wantErr: "email.batch_size must be positive"},
+		{name: "zero digest batch", mutate: func(c *Config) { c.Digest.BatchSize = 0 }, wantErr: "digest.batch_size must be positive"},
+		{name: "zero history length", mutate: func(c *Config) { c.Realtime.HistoryLength = 0 }},
+		{name: "negative history length", mutate: func(c *Config) { c.Realtime.HistoryLength = -1 }, wantErr: "realtime.history_length must not be negative"},
+		{name: "negative fanout threshold", mutate: func(c *Config) { c.Feed.FanoutThreshold = -1 }, wantErr: "feed.fanout_threshold must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig(t)
+			tt.mutate(c)
+
+			assertValidation(t, c.Validate(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// envKeyReplacer maps a key such as security.csp.script-src to SOUNDTUBE_SECURITY_CSP_SCRIPT_SRC.
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// bindEnv binds every configuration key to its SOUNDTUBE_* variable. AutomaticEnv alone
+// is not enough: Unmarshal only asks viper for keys it already knows about, so a field
+// missing from both the file and the defaults would never see its variable.
+//
+// Each key also accepts <VAR>_FILE naming a file that holds the value, which is how
+// Docker and Kubernetes mount secrets. The file wins over the variable and the config.
+func bindEnv(v *viper.Viper) error {
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	v.AutomaticEnv()
+
+	for _, key := range configKeys(v, reflect.TypeOf(Config{}), "") {
+		if err := v.BindEnv(key); err != nil {
+			return err
+		}
+
+		if err := readSecretFile(v, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func envName(key string) string {
+	return envPrefix + "_" + envKeyReplacer.Replace(strings.ToUpper(key))
+}
+
+func readSecretFile(v *viper.Viper, key string) error {
+	variable := envName(key) + "_FILE"
+
+	path := os.Getenv(variable)
+	if path == "" {
+		return nil
+	}
+
+	value, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", variable, err)
+	}
+
+	v.Set(key, strings.TrimRight(string(value), "\r\n"))
+	return nil
+}
+
+// configKeys lists the leaf keys of t by their mapstructure tags. Maps have no fixed
+// keys, so the ones present in the defaults or the config file are used.
+func configKeys(v *viper.Viper, t reflect.Type, prefix string) []string {
+	var keys []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := strings.ToLower(name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			keys = append(keys, configKeys(v, field.Type, key)...)
+		case reflect.Map:
+			for _, name := range mapKeys(v, key) {
+				if field.Type.Elem().Kind() == reflect.Struct {
+					keys = append(keys, configKeys(v, field.Type.Elem(), key+"."+name)...)
+				} else {
+					keys = append(keys, key+"."+name)
+				}
+			}
+		default:
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func mapKeys(v *viper.Viper, key string) []string {
+	var names []string
+	for name := range v.GetStringMap(key) {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
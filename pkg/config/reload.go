@@ -0,0 +1,81 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Reloadable returns a copy of c with the settings that are safe to change at runtime
+// taken from next: the log level, rate limits, CORS, email templates and feature flag
+// defaults. Everything else stays as it was at startup; see Changes for what a reload
+// left out.
+func (c *Config) Reloadable(next *Config) *Config {
+	merged := *c
+
+	merged.Logging.Level = next.Logging.Level
+
+	merged.RateLimiter.MaxRequests = next.RateLimiter.MaxRequests
+	merged.RateLimiter.Window = next.RateLimiter.Window
+	merged.RateLimiter.Policies = next.RateLimiter.Policies
+
+	merged.Security.CORS = next.Security.CORS
+
+	merged.Email.Locale = next.Email.Locale
+	merged.Email.TemplatesDir = next.Email.TemplatesDir
+
+	merged.Features.Flags = next.Features.Flags
+
+	return &merged
+}
+
+// Changes lists the keys whose values differ between c and other. Lists and maps are
+// compared as a whole and reported under their own key.
+func (c *Config) Changes(other *Config) []string {
+	return changedKeys(reflect.ValueOf(*c), reflect.ValueOf(*other), "")
+}
+
+func changedKeys(a, b reflect.Value, prefix string) []string {
+	var keys []string
+
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := strings.ToLower(name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, changedKeys(a.Field(i), b.Field(i), key)...)
+			continue
+		}
+
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// Watch calls onChange whenever the file selected by source is written. It reports
+// false when there is no config file to watch.
+func Watch(source Source, onChange func()) bool {
+	v := viper.New()
+	if err := readConfigFile(v, source); err != nil || v.ConfigFileUsed() == "" {
+		return false
+	}
+
+	v.OnConfigChange(func(fsnotify.Event) { onChange() })
+	v.WatchConfig()
+
+	return true
+}
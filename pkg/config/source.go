@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	envPrefix      = "SOUNDTUBE"
+	defaultProfile = "dev"
+)
+
+// Source selects the configuration file. File wins over Profile; a profile is looked up
+// as <profile>.yaml in Dirs, which defaults to configs, ../configs and ../../configs under
+// the working directory and then configs next to the executable, so the binary runs from
+// the repository root as well as cmd/api.
+type Source struct {
+	Profile string
+	File    string
+	Dirs    []string
+}
+
+// SourceFromFlags reads -profile and -config from args, falling back to SOUNDTUBE_PROFILE
+// and SOUNDTUBE_CONFIG. Without either the dev profile is loaded if it exists.
+func SourceFromFlags(args []string) (Source, error) {
+	var source Source
+
+	flags := flag.NewFlagSet("soundtube", flag.ContinueOnError)
+	flags.StringVar(&source.Profile, "profile", os.Getenv(envPrefix+"_PROFILE"), "configuration profile: configs/<profile>.yaml")
+	flags.StringVar(&source.File, "config", os.Getenv(envPrefix+"_CONFIG"), "path to a configuration file, overrides -profile")
+
+	if err := flags.Parse(args); err != nil {
+		return Source{}, err
+	}
+
+	return source, nil
+}
+
+func (s Source) dirs() []string {
+	if len(s.Dirs) > 0 {
+		return s.Dirs
+	}
+
+	dirs := []string{"configs", filepath.Join("..", "configs"), filepath.Join("..", "..", "configs")}
+	if executable, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Join(filepath.Dir(executable), "configs"))
+	}
+
+	return dirs
+}
+
+// readConfigFile loads the selected file. A missing dev profile is not an error: the
+// defaults and environment alone are enough for a container deployment.
+func readConfigFile(v *viper.Viper, source Source) error {
+	if source.File != "" {
+		v.SetConfigFile(source.File)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("read config %s: %w", source.File, err)
+		}
+		return nil
+	}
+
+	profile := source.Profile
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	v.SetConfigName(profile)
+	v.SetConfigType("yaml")
+	for _, dir := range source.dirs() {
+		v.AddConfigPath(dir)
+	}
+
+	err := v.ReadInConfig()
+
+	var notFound viper.ConfigFileNotFoundError
+	if errors.As(err, &notFound) && source.Profile == "" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read config profile %q: %w", profile, err)
+	}
+
+	return nil
+}
+
+// baseDir is what relative paths in the config are resolved against when server.base_dir
+// is not set: the project directory holding the configs directory the file was read
+// from, or the file's own directory when it is not in one. Without a file it is the
+// project directory of the first existing candidate configs directory, else the
+// working directory.
+func (s Source) baseDir(used string) string {
+	dir := ""
+	if used != "" {
+		dir = filepath.Dir(used)
+	} else {
+		for _, candidate := range s.dirs() {
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				dir = candidate
+				break
+			}
+		}
+	}
+
+	switch {
+	case dir == "":
+		return "."
+	case filepath.Base(dir) == "configs":
+		return filepath.Dir(dir)
+	default:
+		return dir
+	}
+}
+
+// resolvePaths joins the relative directories of c onto Server.BaseDir, which defaults
+// to base, so they no longer depend on the working directory.
+func (c *Config) resolvePaths(base string) {
+	if c.Server.BaseDir == "" {
+		c.Server.BaseDir = base
+	}
+
+	for _, path := range []*string{&c.Server.StaticDir, &c.Export.Dir, &c.Email.DropDir, &c.Email.TemplatesDir} {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(c.Server.BaseDir, *path)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// decodeHook keeps viper's string conversions and refuses bare numbers for durations:
+// they used to be seconds, minutes or hours depending on the field, and silently
+// reading them as nanoseconds would be worse than failing.
+func decodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		durationHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		listHook,
+	)
+}
+
+// listHook splits comma-separated strings, as set in the environment, into any slice
+// type. mapstructure's own StringToSliceHookFunc only produces []string, which leaves
+// lists of numbers such as feature flag users unreadable from the environment.
+func listHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Slice {
+		return data, nil
+	}
+
+	value := reflect.ValueOf(data).String()
+	if value == "" {
+		return []string{}, nil
+	}
+
+	items := strings.Split(value, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items, nil
+}
+
+func durationHook(from, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return nil, fmt.Errorf("duration %v needs a unit, e.g. \"30s\", \"15m\" or \"24h\"", data)
+	}
+
+	return data, nil
+}
+
+// Validate reports every invalid setting, not just the first, joined into one error.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(oneOf(c.Environment.Current, "development", "test", "production"),
+		"environment.current must be development, test or production, got %q", c.Environment.Current)
+	check(oneOf(c.Logging.Level, "", "debug", "info", "warn", "error"),
+		"logging.level must be debug, info, warn or error, got %q", c.Logging.Level)
+	check(oneOf(c.Logging.Format, "", "json", "text"),
+		"logging.format must be json or text, got %q", c.Logging.Format)
+
+	check(c.Database.Host != "", "database.host is required")
+	check(c.Database.DBName != "", "database.dbname is required")
+	check(c.Database.User != "", "database.user is required")
+	check(c.Redis.Addr != "", "redis.addr is required")
+	check(c.Server.Port != "", "server.port is required")
+	check(c.Server.StaticDir != "", "server.static_dir is required")
+	check(c.Export.Dir != "", "export.dir is required")
+	check(absoluteURL(c.Server.PublicURL), "server.public_url must be an absolute http(s) URL, got %q", c.Server.PublicURL)
+	check(c.Environment.Current != "production" || strings.HasPrefix(c.Server.PublicURL, "https://"),
+		"server.public_url must use https in production")
+
+	check(len(c.Token.JwtKey) >= 16, "token.jwt_key must be at least 16 characters")
+	check(c.Token.Exp > 0, "token.exp must be positive")
+	check(len(c.Export.Secret) >= 16, "export.secret must be at least 16 characters")
+	check(c.Export.Secret != c.Token.JwtKey, "export.secret must differ from token.jwt_key")
+	check(len(c.Digest.UnsubscribeSecret) >= 16, "digest.unsubscribe_secret must be at least 16 characters")
+	check(c.Digest.UnsubscribeSecret != c.Token.JwtKey && c.Digest.UnsubscribeSecret != c.Export.Secret,
+		"digest.unsubscribe_secret must differ from token.jwt_key and export.secret")
+
+	check(!c.Security.CORS.AllowCredentials || !slices.ContainsFunc(c.Security.CORS.AllowedOrigins, func(origin string) bool {
+		return strings.TrimSpace(origin) == "*"
+	}), "security.cors.allow_credentials cannot be combined with the \"*\" origin; list the trusted origins instead")
+
+	check(oneOf(c.Traycing.Protocol, "grpc", "http"),
+		"traycing.protocol must be grpc or http, got %q", c.Traycing.Protocol)
+	check(c.Traycing.SampleRatio >= 0 && c.Traycing.SampleRatio <= 1,
+		"traycing.sample_ratio must be between 0 and 1, got %v", c.Traycing.SampleRatio)
+
+	check(oneOf(c.Email.Transport, "smtp", "file", "memory"),
+		"email.transport must be smtp, file or memory, got %q", c.Email.Transport)
+	check(c.Email.Transport != "smtp" || c.Email.SMTHost != "", "email.smtHost is required for the smtp transport")
+	check(oneOf(c.Digest.DefaultFrequency, "off", "daily", "weekly"),
+		"digest.default_frequency must be off, daily or weekly, got %q", c.Digest.DefaultFrequency)
+
+	if c.Metrics.Enabled && c.Metrics.Addr != "" {
+		loopback, err := loopbackAddr(c.Metrics.Addr)
+		check(err == nil, "metrics.addr must be host:port, got %q", c.Metrics.Addr)
+		check(err != nil || loopback || c.Metrics.Token != "",
+			"metrics.token is required when metrics.addr %q is not a loopback address", c.Metrics.Addr)
+	}
+
+	check(oneOf(c.RateLimiter.Backend, "redis", "memory"),
+		"rate_limiter.backend must be redis or memory, got %q", c.RateLimiter.Backend)
+	check(c.RateLimiter.MaxRequests > 0 && c.RateLimiter.Window > 0,
+		"rate_limiter needs positive max_requests and window")
+	for _, name := range slices.Sorted(maps.Keys(c.RateLimiter.Policies)) {
+		policy := c.RateLimiter.Policies[name]
+		check(policy.MaxRequests > 0 && policy.Window > 0,
+			"rate_limiter.policies.%s needs positive max_requests and window", name)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Features.Flags)) {
+		flag := c.Features.Flags[name]
+		check(flag.Percentage >= 0 && flag.Percentage <= 100,
+			"features.flags.%s.percentage must be between 0 and 100, got %d", name, flag.Percentage)
+	}
+
+	for _, size := range []struct {
+		key   string
+		value int
+	}{
+		{"export.queue_size", c.Export.QueueSize},
+		{"feed.max_length", c.Feed.MaxLength},
+		{"listening.play_threshold", c.Listening.PlayThreshold},
+		{"listening.batch_size", c.Listening.BatchSize},
+		{"listening.queue_size", c.Listening.QueueSize},
+		{"charts.size", c.Charts.Size},
+		{"recommendations.top_k", c.Recommendations.TopK},
+		{"realtime.send_buffer", c.Realtime.SendBuffer},
+		{"realtime.max_subscriptions", c.Realtime.MaxSubscriptions},
+		{"email.batch_size", c.Email.BatchSize},
+		{"email.max_attempts", c.Email.MaxAttempts},
+		{"digest.max_items", c.Digest.MaxItems},
+		{"digest.batch_size", c.Digest.BatchSize},
+	} {
+		check(size.value > 0, "%s must be positive, got %d", size.key, size.value)
+	}
+
+	// A zero fan-out threshold makes every author pull-based and a zero history length
+	// turns off resuming event streams.
+	for _, size := range []struct {
+		key   string
+		value int
+	}{
+		{"feed.fanout_threshold", c.Feed.FanoutThreshold},
+		{"realtime.history_length", c.Realtime.HistoryLength},
+	} {
+		check(size.value >= 0, "%s must not be negative, got %d", size.key, size.value)
+	}
+
+	for _, interval := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"export.link_ttl", c.Export.LinkTTL},
+		{"feed.ttl", c.Feed.TTL},
+		{"listening.flush_interval", c.Listening.FlushInterval},
+		{"listening.session_ttl", c.Listening.SessionTTL},
+		{"charts.refresh_interval", c.Charts.RefreshInterval},
+		{"recommendations.refresh_interval", c.Recommendations.RefreshInterval},
+		{"realtime.history_ttl", c.Realtime.HistoryTTL},
+		{"realtime.heartbeat", c.Realtime.Heartbeat},
+		{"email.outbox_interval", c.Email.OutboxInterval},
+		{"digest.interval", c.Digest.Interval},
+		{"features.refresh_interval", c.Features.RefreshInterval},
+		{"health.timeout", c.Health.Timeout},
+	} {
+		check(interval.value > 0, "%s must be positive", interval.key)
+	}
+
+	// Zero timeouts and connection lifetimes mean unlimited; a zero health cache or drain
+	// delay turns it off.
+	for _, timeout := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"database_connections.max_life_time", c.DatabaseConnections.ConnMaxLifetime},
+		{"database_connections.max_idle_time", c.DatabaseConnections.ConnMaxIdleTime},
+		{"health.cache_ttl", c.Health.CacheTTL},
+		{"health.drain_delay", c.Health.DrainDelay},
+	} {
+		check(timeout.value >= 0, "%s must not be negative", timeout.key)
+	}
+
+	return errors.Join(errs...)
+}
+
+func absoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// loopbackAddr reports whether a listen address only accepts local connections. An
+// empty host listens on every interface.
+func loopbackAddr(addr string) (bool, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false, err
+	}
+	if host == "localhost" {
+		return true, nil
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback(), nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	return slices.Contains(allowed, value)
+}
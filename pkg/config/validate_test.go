@@ -0,0 +1,145 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// validConfig returns the defaults plus the settings that have none, which together
+// pass Validate.
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+
+	v := viper.New()
+	setDefaults(v)
+
+	var c Config
+	if err := v.Unmarshal(&c, viper.DecodeHook(decodeHook())); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Database.Host = "localhost"
+	c.Database.DBName = "soundtube"
+	c.Database.User = "soundtube"
+	c.Redis.Addr = "localhost:6379"
+	c.Email.SMTHost = "localhost"
+	c.Token.JwtKey = "jwt-key-0123456789"
+	c.Export.Secret = "export-secret-0123456789"
+	c.Digest.UnsubscribeSecret = "unsubscribe-secret-0123456789"
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	return &c
+}
+
+func assertValidation(t *testing.T, err error, wantErr string) {
+	t.Helper()
+
+	if wantErr == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Fatalf("err = %v, want it to contain %q", err, wantErr)
+	}
+}
+
+func TestValidateMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		addr    string
+		token   string
+		wantErr string
+	}{
+		{name: "loopback ipv4 without token", enabled: true, addr: "127.0.0.1:9090"},
+		{name: "loopback ipv6 without token", enabled: true, addr: "[::1]:9090"},
+		{name: "localhost without token", enabled: true, addr: "localhost:9090"},
+		{name: "all interfaces with token", enabled: true, addr: ":9090", token: "scrape"},
+		{name: "all interfaces without token", enabled: true, addr: ":9090", wantErr: "metrics.token is required"},
+		{name: "public address without token", enabled: true, addr: "10.0.0.5:9090", wantErr: "metrics.token is required"},
+		{name: "not host:port", enabled: true, addr: "9090", wantErr: "metrics.addr must be host:port"},
+		{name: "public server", enabled: true, addr: ""},
+		{name: "disabled", enabled: false, addr: ":9090"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig(t)
+			c.Metrics.Enabled = tt.enabled
+			c.Metrics.Addr = tt.addr
+			c.Metrics.Token = tt.token
+
+			assertValidation(t, c.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestDefaultMetricsAddrIsLoopback(t *testing.T) {
+	c := validConfig(t)
+
+	loopback, err := loopbackAddr(c.Metrics.Addr)
+	if err != nil || !loopback {
+		t.Fatalf("default metrics.addr %q is not loopback (err %v)", c.Metrics.Addr, err)
+	}
+}
+
+func TestLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr     string
+		loopback bool
+		wantErr  bool
+	}{
+		{addr: "127.0.0.1:9090", loopback: true},
+		{addr: "[::1]:9090", loopback: true},
+		{addr: "localhost:9090", loopback: true},
+		{addr: ":9090"},
+		{addr: "0.0.0.0:9090"},
+		{addr: "10.0.0.5:9090"},
+		{addr: "9090", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			loopback, err := loopbackAddr(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if loopback != tt.loopback {
+				t.Fatalf("loopback = %v, want %v", loopback, tt.loopback)
+			}
+		})
+	}
+}
+
+func TestValidateCORSCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		wantErr     string
+	}{
+		{name: "listed origins with credentials", origins: []string{"https://app.example.com"}, credentials: true},
+		{name: "subdomain wildcard with credentials", origins: []string{"https://*.example.com"}, credentials: true},
+		{name: "any origin without credentials", origins: []string{"*"}},
+		{name: "any origin with credentials", origins: []string{"*"}, credentials: true,
+			wantErr: "security.cors.allow_credentials cannot be combined"},
+		{name: "any origin among others with credentials", origins: []string{"https://app.example.com", " * "}, credentials: true,
+			wantErr: "security.cors.allow_credentials cannot be combined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig(t)
+			c.Security.CORS.AllowedOrigins = tt.origins
+			c.Security.CORS.AllowCredentials = tt.credentials
+
+			assertValidation(t, c.Validate(), tt.wantErr)
+		})
+	}
+}
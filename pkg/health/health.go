@@ -0,0 +1,297 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"soundtube/pkg"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses in the health+json format (draft-inadarei-api-health-check).
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// Component types reported for each check.
+const (
+	TypeDatastore = "datastore"
+	TypeSystem    = "system"
+	TypeComponent = "component"
+)
+
+// Check probes one component. A failing critical component fails the whole service,
+// any other only degrades it to warn. DependsOn names components this one needs: when
+// one of them fails, this one is reported as failing too, with the dependency as cause.
+type Check struct {
+	Name      string
+	Type      string
+	Critical  bool
+	Timeout   time.Duration
+	DependsOn []string
+	Probe     func(ctx context.Context) error
+}
+
+// Result is one component's entry in the report.
+type Result struct {
+	ComponentType string    `json:"componentType"`
+	Status        string    `json:"status"`
+	Critical      bool      `json:"critical"`
+	ObservedValue float64   `json:"observedValue"`
+	ObservedUnit  string    `json:"observedUnit"`
+	DependsOn     []string  `json:"dependsOn,omitempty"`
+	Output        string    `json:"output,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+// Report is the /health response. Checks are keyed "<component>:responseTime".
+type Report struct {
+	Status string              `json:"status"`
+	Checks map[string][]Result `json:"checks"`
+}
+
+func (r *Report) HTTPStatus() int {
+	if r.Status == StatusFail {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Registry runs the registered checks concurrently and caches the report for ttl.
+// Callers arriving while a run is in progress wait for it instead of starting their
+// own, so a burst of probes costs one round of checks.
+type Registry struct {
+	logger  *pkg.CustomLogger
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu        sync.Mutex
+	checks    []Check
+	report    *Report
+	checkedAt time.Time
+	running   chan struct{}
+
+	draining atomic.Bool
+}
+
+// NewRegistry caches reports for ttl; timeout applies to checks that set none.
+func NewRegistry(ttl, timeout time.Duration, logger *pkg.CustomLogger) *Registry {
+	return &Registry{ttl: ttl, timeout: timeout, logger: logger}
+}
+
+func (r *Registry) Register(check Check) {
+	if check.Timeout <= 0 {
+		check.Timeout = r.timeout
+	}
+	if check.Type == "" {
+		check.Type = TypeComponent
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, check)
+	r.report = nil
+}
+
+// Drain marks the service as shutting down; Ready fails from then on.
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+func (r *Registry) Draining() bool {
+	return r.draining.Load()
+}
+
+// Ready reports whether the service should receive traffic: it is not draining and no
+// critical component is failing.
+func (r *Registry) Ready(ctx context.Context) (bool, *Report) {
+	if r.Draining() {
+		return false, nil
+	}
+
+	report := r.Report(ctx)
+	return report.Status != StatusFail, report
+}
+
+// Report returns the cached report, running the checks if it is older than ttl.
+func (r *Registry) Report(ctx context.Context) *Report {
+	r.mu.Lock()
+	if r.report != nil && time.Since(r.checkedAt) < r.ttl {
+		report := r.report
+		r.mu.Unlock()
+		return report
+	}
+
+	if r.running == nil {
+		r.running = make(chan struct{})
+		go r.run(slices.Clone(r.checks), r.running)
+	}
+	running := r.running
+	r.mu.Unlock()
+
+	select {
+	case <-running:
+	case <-ctx.Done():
+		return &Report{Status: StatusFail, Checks: map[string][]Result{}}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.report
+}
+
+func (r *Registry) run(checks []Check, done chan struct{}) {
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = probe(check)
+		}()
+	}
+	wg.Wait()
+
+	propagate(checks, results)
+	report := newReport(checks, results)
+
+	r.mu.Lock()
+	previous := r.report
+	r.report = report
+	r.checkedAt = time.Now()
+	r.running = nil
+	r.mu.Unlock()
+	close(done)
+
+	r.logTransitions(checks, previous, report)
+}
+
+// probe runs a check with its timeout. The check runs in its own goroutine so that a
+// client that ignores the context cannot hold up the report.
+func probe(check Check) Result {
+	ctx, cancel := context.WithTimeout(context.Background(), check.Timeout)
+	defer cancel()
+
+	result := Result{
+		ComponentType: check.Type,
+		Status:        StatusPass,
+		Critical:      check.Critical,
+		ObservedUnit:  "ms",
+		DependsOn:     check.DependsOn,
+		Time:          time.Now().UTC(),
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- check.Probe(ctx) }()
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", check.Timeout)
+	}
+
+	result.ObservedValue = float64(time.Since(result.Time).Microseconds()) / 1000
+	if err != nil {
+		result.Status = StatusFail
+		result.Output = err.Error()
+	}
+
+	return result
+}
+
+// propagate fails every component whose dependencies, direct or not, are failing.
+func propagate(checks []Check, results []Result) {
+	index := make(map[string]int, len(checks))
+	for i, check := range checks {
+		index[check.Name] = i
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i, check := range checks {
+			if results[i].Status == StatusFail {
+				continue
+			}
+			for _, dependency := range check.DependsOn {
+				if j, ok := index[dependency]; ok && results[j].Status == StatusFail {
+					results[i].Status = StatusFail
+					results[i].Output = "depends on failing " + dependency
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+func newReport(checks []Check, results []Result) *Report {
+	report := &Report{Status: StatusPass, Checks: make(map[string][]Result, len(checks))}
+
+	for i, check := range checks {
+		report.Checks[check.Name+":responseTime"] = []Result{results[i]}
+
+		switch {
+		case results[i].Status == StatusFail && check.Critical:
+			report.Status = StatusFail
+		case results[i].Status != StatusPass && report.Status == StatusPass:
+			report.Status = StatusWarn
+		}
+	}
+
+	return report
+}
+
+func (r *Registry) logTransitions(checks []Check, previous, current *Report) {
+	for _, check := range checks {
+		key := check.Name + ":responseTime"
+		now := current.Checks[key][0]
+
+		was := StatusPass
+		if previous != nil {
+			if results, ok := previous.Checks[key]; ok {
+				was = results[0].Status
+			}
+		}
+		if now.Status == was {
+			continue
+		}
+
+		if now.Status == StatusFail {
+			r.logger.Warn("health check failing", fmt.Errorf("%s: %s", check.Name, now.Output))
+		} else {
+			r.logger.Info("health check recovered", "component", check.Name)
+		}
+	}
+}
+
+// WritableDir checks that files can be created in each directory, creating the
+// directories if they are missing.
+func WritableDir(dirs ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for _, dir := range dirs {
+			if err := os.MkdirAll(dir, 0o775); err != nil {
+				return err
+			}
+
+			file, err := os.CreateTemp(dir, ".health-*")
+			if err != nil {
+				return err
+			}
+			file.Close()
+
+			if err := os.Remove(file.Name()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
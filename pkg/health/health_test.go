@@ -0,0 +1,202 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"soundtube/pkg"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRegistry(ttl time.Duration) *Registry {
+	return NewRegistry(ttl, time.Second, pkg.NewLogger(slog.New(slog.DiscardHandler), false))
+}
+
+func pass(context.Context) error { return nil }
+func fail(context.Context) error { return errors.New("connection refused") }
+
+func TestRegistryReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []Check
+		wantStatus string
+		wantHTTP   int
+		want       map[string]string
+		wantOutput map[string]string
+	}{
+		{
+			name:       "all passing",
+			checks:     []Check{{Name: "postgres", Critical: true, Probe: pass}, {Name: "redis", Critical: true, Probe: pass}},
+			wantStatus: StatusPass,
+			wantHTTP:   http.StatusOK,
+			want:       map[string]string{"postgres": StatusPass, "redis": StatusPass},
+		},
+		{
+			name:       "critical failure",
+			checks:     []Check{{Name: "postgres", Critical: true, Probe: fail}, {Name: "redis", Critical: true, Probe: pass}},
+			wantStatus: StatusFail,
+			wantHTTP:   http.StatusServiceUnavailable,
+			want:       map[string]string{"postgres": StatusFail, "redis": StatusPass},
+			wantOutput: map[string]string{"postgres": "connection refused"},
+		},
+		{
+			name:       "non-critical failure degrades",
+			checks:     []Check{{Name: "postgres", Critical: true, Probe: pass}, {Name: "smtp", Probe: fail}},
+			wantStatus: StatusWarn,
+			wantHTTP:   http.StatusOK,
+			want:       map[string]string{"postgres": StatusPass, "smtp": StatusFail},
+		},
+		{
+			name: "failure propagates to dependents",
+			checks: []Check{
+				{Name: "redis", Probe: fail},
+				{Name: "rate_limiter", DependsOn: []string{"redis"}, Probe: pass},
+				{Name: "realtime", Critical: true, DependsOn: []string{"rate_limiter"}, Probe: pass},
+			},
+			wantStatus: StatusFail,
+			wantHTTP:   http.StatusServiceUnavailable,
+			want:       map[string]string{"redis": StatusFail, "rate_limiter": StatusFail, "realtime": StatusFail},
+			wantOutput: map[string]string{"rate_limiter": "depends on failing redis", "realtime": "depends on failing rate_limiter"},
+		},
+		{
+			name:       "unknown dependency is ignored",
+			checks:     []Check{{Name: "uploads", DependsOn: []string{"s3"}, Probe: pass}},
+			wantStatus: StatusPass,
+			wantHTTP:   http.StatusOK,
+			want:       map[string]string{"uploads": StatusPass},
+		},
+		{
+			name: "timeout",
+			checks: []Check{{Name: "postgres", Critical: true, Timeout: 10 * time.Millisecond, Probe: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			}}},
+			wantStatus: StatusFail,
+			wantHTTP:   http.StatusServiceUnavailable,
+			want:       map[string]string{"postgres": StatusFail},
+			wantOutput: map[string]string{"postgres": "timed out after 10ms"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := testRegistry(time.Minute)
+			for _, check := range tt.checks {
+				registry.Register(check)
+			}
+
+			report := registry.Report(context.Background())
+			if report.Status != tt.wantStatus || report.HTTPStatus() != tt.wantHTTP {
+				t.Fatalf("status = %s (%d), want %s (%d)", report.Status, report.HTTPStatus(), tt.wantStatus, tt.wantHTTP)
+			}
+			for name, status := range tt.want {
+				result := report.Checks[name+":responseTime"][0]
+				if result.Status != status {
+					t.Errorf("%s = %s, want %s", name, result.Status, status)
+				}
+				if result.ComponentType != TypeComponent || result.ObservedUnit != "ms" {
+					t.Errorf("%s result = %+v", name, result)
+				}
+			}
+			for name, output := range tt.wantOutput {
+				if got := report.Checks[name+":responseTime"][0].Output; got != output {
+					t.Errorf("%s output = %q, want %q", name, got, output)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryCachesReports(t *testing.T) {
+	var probes atomic.Int32
+	registry := testRegistry(time.Minute)
+	registry.Register(Check{Name: "postgres", Probe: func(context.Context) error {
+		probes.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registry.Report(context.Background())
+		}()
+	}
+	wg.Wait()
+	registry.Report(context.Background())
+
+	if got := probes.Load(); got != 1 {
+		t.Fatalf("probes = %d, want 1 for concurrent and cached reports", got)
+	}
+
+	registry.Register(Check{Name: "redis", Probe: pass})
+	registry.Report(context.Background())
+	if got := probes.Load(); got != 2 {
+		t.Fatalf("probes = %d, want a fresh run after Register", got)
+	}
+}
+
+func TestRegistryReady(t *testing.T) {
+	tests := []struct {
+		name      string
+		probe     func(context.Context) error
+		critical  bool
+		drain     bool
+		wantReady bool
+	}{
+		{name: "healthy", probe: pass, critical: true, wantReady: true},
+		{name: "critical failure", probe: fail, critical: true, wantReady: false},
+		{name: "non-critical failure", probe: fail, critical: false, wantReady: true},
+		{name: "draining", probe: pass, critical: true, drain: true, wantReady: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := testRegistry(time.Minute)
+			registry.Register(Check{Name: "postgres", Critical: tt.critical, Probe: tt.probe})
+			if tt.drain {
+				registry.Drain()
+			}
+
+			ready, _ := registry.Ready(context.Background())
+			if ready != tt.wantReady {
+				t.Fatalf("ready = %v, want %v", ready, tt.wantReady)
+			}
+		})
+	}
+}
+
+func TestWritableDir(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dirs    []string
+		wantErr bool
+	}{
+		{name: "existing", dirs: []string{root}},
+		{name: "created when missing", dirs: []string{filepath.Join(root, "exports", "archives")}},
+		{name: "path is a file", dirs: []string{root, filepath.Join(file, "sub")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WritableDir(tt.dirs...)(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package pkg
+
+import "context"
+
+type logContextKey int
+
+const (
+	requestIDKey logContextKey = iota
+	userIDKey
+	clientIPKey
+)
+
+// WithRequestID stores the request id so every record logged with ctx carries it.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok
+}
+
+// WithUserID stores the authenticated user so every record logged with ctx carries it.
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDKey).(int)
+	return userID, ok
+}
+
+// WithClientIP stores the resolved client address so every record logged with ctx carries it.
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey, clientIP)
+}
+
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	clientIP, ok := ctx.Value(clientIPKey).(string)
+	return clientIP, ok
+}
@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"soundtube/pkg/config"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are matched as substrings of lower-cased attribute keys.
+var sensitiveKeys = []string{"password", "token", "secret", "signature", "authorization", "cookie", "jwt", "api_key"}
+
+// NewSlogLogger builds the application logger. The format falls back to text in
+// development and json everywhere else. level is read on every record, so a
+// *slog.LevelVar lets the level change at runtime.
+func NewSlogLogger(w io.Writer, cfg *config.Logging, environment string, level slog.Leveler) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	format := cfg.Format
+	if format == "" && environment == "development" {
+		format = "text"
+	}
+
+	if format == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// ParseLogLevel reads debug, info, warn or error; anything else gets the environment default.
+func ParseLogLevel(level, environment string) slog.Level {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err == nil {
+		return parsed
+	}
+
+	if environment == "development" {
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
+// IsSensitiveKey reports whether values under key must never be written to logs.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() != slog.KindGroup && IsSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+	return attr
+}
+
+// contextHandler adds the request, user and trace ids and the client address found in
+// the record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+
+	if userID, ok := UserIDFromContext(ctx); ok {
+		record.AddAttrs(slog.Int("user_id", userID))
+	}
+
+	if clientIP, ok := ClientIPFromContext(ctx); ok {
+		record.AddAttrs(slog.String("client_ip", clientIP))
+	}
+
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanCtx.TraceID().String()),
+			slog.String("span_id", spanCtx.SpanID().String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
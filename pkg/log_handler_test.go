@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"soundtube/pkg/config"
+	"strings"
+	"testing"
+)
+
+func TestRedaction(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs []any
+		path  []string
+		want  any
+	}{
+		{name: "password", attrs: []any{"password", "hunter2"}, path: []string{"password"}, want: redacted},
+		{name: "case insensitive", attrs: []any{"Authorization", "Bearer abc"}, path: []string{"Authorization"}, want: redacted},
+		{name: "substring", attrs: []any{"reset_token", "abc"}, path: []string{"reset_token"}, want: redacted},
+		{name: "api key", attrs: []any{"api_key", "k"}, path: []string{"api_key"}, want: redacted},
+		{name: "non string value", attrs: []any{"client_secret", 42}, path: []string{"client_secret"}, want: redacted},
+		{name: "inside a group", attrs: []any{slog.Group("request", "cookie", "session=1", "path", "/")}, path: []string{"request", "cookie"}, want: redacted},
+		{name: "group siblings kept", attrs: []any{slog.Group("request", "cookie", "session=1", "path", "/")}, path: []string{"request", "path"}, want: "/"},
+		{name: "ordinary key", attrs: []any{"user_name", "alice"}, path: []string{"user_name"}, want: "alice"},
+		{name: "message is not an attribute", attrs: nil, path: []string{"msg"}, want: "token refreshed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewSlogLogger(&buf, &config.Logging{Format: "json"}, "production", slog.LevelInfo)
+			logger.Info("token refreshed", tt.attrs...)
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("invalid json %q: %v", buf.String(), err)
+			}
+
+			var got any = record
+			for _, key := range tt.path {
+				got = got.(map[string]any)[key]
+			}
+
+			if got != tt.want {
+				t.Fatalf("%s = %v, want %v", strings.Join(tt.path, "."), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextHandlerAddsIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(&buf, &config.Logging{Format: "json"}, "production", slog.LevelInfo)
+
+	ctx := WithClientIP(WithUserID(WithRequestID(context.Background(), "req-1"), 7), "198.51.100.4")
+	logger.With("component", "test").InfoContext(ctx, "hello")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{key: "request_id", want: "req-1"},
+		{key: "user_id", want: float64(7)},
+		{key: "client_ip", want: "198.51.100.4"},
+		{key: "component", want: "test"},
+	}
+
+	for _, tt := range tests {
+		if record[tt.key] != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, record[tt.key], tt.want)
+		}
+	}
+	if _, ok := record["trace_id"]; ok {
+		t.Errorf("trace_id logged without a span")
+	}
+}
+
+func TestNewSlogLoggerFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		environment string
+		wantJSON    bool
+	}{
+		{name: "development default", environment: "development", wantJSON: false},
+		{name: "production default", environment: "production", wantJSON: true},
+		{name: "explicit text", format: "text", environment: "production", wantJSON: false},
+		{name: "explicit json", format: "json", environment: "development", wantJSON: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewSlogLogger(&buf, &config.Logging{Format: tt.format}, tt.environment, slog.LevelInfo).Info("hello")
+
+			if isJSON := json.Valid(buf.Bytes()); isJSON != tt.wantJSON {
+				t.Fatalf("json = %v, want %v: %q", isJSON, tt.wantJSON, buf.String())
+			}
+		})
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		level       string
+		environment string
+		want        slog.Level
+	}{
+		{level: "debug", environment: "production", want: slog.LevelDebug},
+		{level: "WARN", environment: "production", want: slog.LevelWarn},
+		{level: "error", environment: "development", want: slog.LevelError},
+		{level: "", environment: "development", want: slog.LevelDebug},
+		{level: "", environment: "production", want: slog.LevelInfo},
+		{level: "verbose", environment: "test", want: slog.LevelInfo},
+	}
+
+	for _, tt := range tests {
+		if got := ParseLogLevel(tt.level, tt.environment); got != tt.want {
+			t.Errorf("ParseLogLevel(%q, %q) = %s, want %s", tt.level, tt.environment, got, tt.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type CustomLogger struct {
@@ -14,22 +15,16 @@ type CustomLogger struct {
 	needTrace bool
 }
 
-type ErrorResponce struct {
-	Logger  *CustomLogger
-	Error   error
-	Messege string
-}
-
-type InforResponce struct {
-	Logger  *CustomLogger
-	Messege string
-}
-
+// NewLogger starts with a no-op tracer so spans can be opened unconditionally; the real
+// tracer is installed with SetTracer once tracing is configured.
 func NewLogger(logger *slog.Logger, needTrace bool) *CustomLogger {
-	return &CustomLogger{log: logger, needTrace: needTrace}
+	return &CustomLogger{log: logger, tracer: noop.NewTracerProvider().Tracer(""), needTrace: needTrace}
 }
 
 func (log *CustomLogger) SetTracer(tracer trace.Tracer) {
+	if tracer == nil {
+		return
+	}
 	log.tracer = tracer
 }
 
@@ -37,32 +32,53 @@ func (log *CustomLogger) GetTracer() trace.Tracer {
 	return log.tracer
 }
 
-func (log *CustomLogger) Info(info string, args ...any) *InforResponce {
-	log.log.Info(info, args...)
-	return &InforResponce{Logger: log, Messege: info}
+// Info, Warn and Error are for code that runs outside a request, such as startup and
+// background loops. Anything holding a context should use the *Context variants so the
+// record carries the request, user and trace ids.
+func (log *CustomLogger) Info(msg string, args ...any) {
+	log.log.Info(msg, args...)
+}
+
+func (log *CustomLogger) Warn(msg string, err error) {
+	log.log.Warn(msg, "error", err)
+}
+
+func (log *CustomLogger) Error(msg string, err error) {
+	log.log.Error(msg, "error", err)
 }
 
-func (info *InforResponce) WithTrace(ctx context.Context) *InforResponce {
-	if span := trace.SpanFromContext(ctx); info.Logger.needTrace && span.IsRecording() {
-		span.SetStatus(codes.Ok, info.Messege)
+// LogContext writes a record at an explicit level, for callers that pick it at runtime.
+func (log *CustomLogger) LogContext(ctx context.Context, level slog.Level, msg string, args ...any) {
+	log.log.Log(ctx, level, msg, args...)
+}
+
+func (log *CustomLogger) DebugContext(ctx context.Context, msg string, args ...any) {
+	log.log.DebugContext(ctx, msg, args...)
+}
+
+func (log *CustomLogger) InfoContext(ctx context.Context, msg string, args ...any) {
+	log.log.InfoContext(ctx, msg, args...)
+
+	if span := trace.SpanFromContext(ctx); log.needTrace && span.IsRecording() {
+		span.SetStatus(codes.Ok, msg)
 	}
-	return info
 }
 
-func (log *CustomLogger) Warn(msg string, err error) *ErrorResponce {
-	log.log.Warn(msg, "error", err)
-	return &ErrorResponce{Error: err, Messege: msg, Logger: log}
+func (log *CustomLogger) WarnContext(ctx context.Context, msg string, err error) {
+	log.log.WarnContext(ctx, msg, "error", err)
+	log.recordError(ctx, msg, err)
 }
 
-func (log *CustomLogger) Error(msg string, err error) *ErrorResponce {
-	log.log.Error(msg, "error", err)
-	return &ErrorResponce{Error: err, Messege: msg, Logger: log}
+func (log *CustomLogger) ErrorContext(ctx context.Context, msg string, err error) {
+	log.log.ErrorContext(ctx, msg, "error", err)
+	log.recordError(ctx, msg, err)
 }
 
-func (err *ErrorResponce) WithTrace(ctx context.Context) *ErrorResponce {
-	if span := trace.SpanFromContext(ctx); err.Logger.needTrace && span.IsRecording() {
-		span.RecordError(err.Error)
-		span.SetStatus(codes.Error, err.Messege)
+func (log *CustomLogger) recordError(ctx context.Context, msg string, err error) {
+	if span := trace.SpanFromContext(ctx); log.needTrace && span.IsRecording() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.SetStatus(codes.Error, msg)
 	}
-	return err
 }
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewLoggerHasNoopTracer(t *testing.T) {
+	logger := NewLogger(slog.New(slog.DiscardHandler), false)
+
+	_, span := logger.GetTracer().Start(context.Background(), "op")
+	defer span.End()
+	if span.IsRecording() {
+		t.Fatal("default tracer should not record spans")
+	}
+
+	logger.SetTracer(nil)
+	if logger.GetTracer() == nil {
+		t.Fatal("SetTracer(nil) must keep the no-op tracer")
+	}
+}
+
+func TestLoggerSpanStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		needTrace  bool
+		log        func(l *CustomLogger, ctx context.Context)
+		wantStatus codes.Code
+		wantEvents int
+	}{
+		{
+			name:       "info marks ok",
+			needTrace:  true,
+			log:        func(l *CustomLogger, ctx context.Context) { l.InfoContext(ctx, "done") },
+			wantStatus: codes.Ok,
+		},
+		{
+			name:       "error records error",
+			needTrace:  true,
+			log:        func(l *CustomLogger, ctx context.Context) { l.ErrorContext(ctx, "db error", errors.New("boom")) },
+			wantStatus: codes.Error,
+			wantEvents: 1,
+		},
+		{
+			name:       "warn without error",
+			needTrace:  true,
+			log:        func(l *CustomLogger, ctx context.Context) { l.WarnContext(ctx, "slow", nil) },
+			wantStatus: codes.Error,
+		},
+		{
+			name:       "tracing disabled leaves span alone",
+			needTrace:  false,
+			log:        func(l *CustomLogger, ctx context.Context) { l.ErrorContext(ctx, "db error", errors.New("boom")) },
+			wantStatus: codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder))
+
+			logger := NewLogger(slog.New(slog.DiscardHandler), tt.needTrace)
+			logger.SetTracer(provider.Tracer("test"))
+
+			ctx, span := logger.GetTracer().Start(context.Background(), "op")
+			tt.log(logger, ctx)
+			span.End()
+
+			ended := recorder.Ended()
+			if len(ended) != 1 {
+				t.Fatalf("ended spans = %d, want 1", len(ended))
+			}
+			if got := ended[0].Status().Code; got != tt.wantStatus {
+				t.Fatalf("status = %v, want %v", got, tt.wantStatus)
+			}
+			if got := len(ended[0].Events()); got != tt.wantEvents {
+				t.Fatalf("events = %d, want %d", got, tt.wantEvents)
+			}
+		})
+	}
+}
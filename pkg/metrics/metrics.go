@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "soundtube"
+
+// Metrics owns the registry served on /metrics together with every collector the
+// application records into. A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+
+	rateLimited *prometheus.CounterVec
+
+	uploadBytes    prometheus.Histogram
+	uploadDuration prometheus.Histogram
+
+	registrations prometheus.Counter
+	logins        *prometheus.CounterVec
+	reactions     *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by route, method and status code.",
+		}, []string{"method", "route", "status"}),
+
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rate_limiter",
+			Name:      "rejections_total",
+			Help:      "Requests rejected by the rate limiter.",
+		}, []string{"policy", "route"}),
+
+		uploadBytes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "upload",
+			Name:      "size_bytes",
+			Help:      "Size of uploaded sound files.",
+			Buckets:   prometheus.ExponentialBuckets(256*1024, 2, 10),
+		}),
+
+		uploadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "upload",
+			Name:      "duration_seconds",
+			Help:      "Time spent receiving and storing a sound file.",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+		}),
+
+		registrations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "registrations_total",
+			Help:      "Successfully registered users.",
+		}),
+
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Login attempts by result.",
+		}, []string{"result"}),
+
+		reactions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reactions_total",
+			Help:      "Reactions set on sounds by type.",
+		}, []string{"type"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.rateLimited,
+		m.uploadBytes,
+		m.uploadDuration,
+		m.registrations,
+		m.logins,
+		m.reactions,
+	)
+
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDB exports the connection pool stats of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterRedis exports the connection pool stats of client.
+func (m *Metrics) RegisterRedis(client *redis.Client) {
+	m.registry.MustRegister(newRedisPoolCollector(client))
+}
+
+func (m *Metrics) ObserveRequest(method, route, status string, seconds float64) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(method, route, status).Inc()
+	m.requestDuration.WithLabelValues(method, route).Observe(seconds)
+}
+
+func (m *Metrics) RateLimited(policy, route string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(policy, route).Inc()
+}
+
+func (m *Metrics) ObserveUpload(bytes int64, seconds float64) {
+	if m == nil {
+		return
+	}
+	m.uploadBytes.Observe(float64(bytes))
+	m.uploadDuration.Observe(seconds)
+}
+
+func (m *Metrics) Registered() {
+	if m == nil {
+		return
+	}
+	m.registrations.Inc()
+}
+
+// Login records a login attempt; result is "success" or "failure".
+func (m *Metrics) Login(result string) {
+	if m == nil {
+		return
+	}
+	m.logins.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) Reaction(reactionType string) {
+	if m == nil {
+		return
+	}
+	m.reactions.WithLabelValues(reactionType).Inc()
+}
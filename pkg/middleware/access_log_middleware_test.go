@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/url"
+	"testing"
+)
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  url.Values
+	}{
+		{name: "empty", query: "", want: nil},
+		{name: "nothing sensitive", query: "page=2&q=rain", want: url.Values{"page": {"2"}, "q": {"rain"}}},
+		{name: "verification token", query: "token=abc&email=a%40b.c", want: url.Values{"token": {"REDACTED"}, "email": {"a@b.c"}}},
+		{name: "signature and expiry", query: "expires=1700000000&signature=deadbeef",
+			want: url.Values{"expires": {"1700000000"}, "signature": {"REDACTED"}}},
+		{name: "case insensitive key", query: "Access_Token=abc", want: url.Values{"Access_Token": {"REDACTED"}}},
+		{name: "repeated values", query: "password=a&password=b", want: url.Values{"password": {"REDACTED"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactQuery(tt.query)
+			if tt.want == nil {
+				if got != "" {
+					t.Fatalf("redactQuery(%q) = %q, want empty", tt.query, got)
+				}
+				return
+			}
+			if got != tt.want.Encode() {
+				t.Fatalf("redactQuery(%q) = %q, want %q", tt.query, got, tt.want.Encode())
+			}
+		})
+	}
+
+	if got := redactQuery("token=%zz"); got != "[unparsable]" {
+		t.Fatalf("unparsable query = %q", got)
+	}
+}
+
+func TestAccessLogLevel(t *testing.T) {
+	tests := []struct {
+		status int
+		want   slog.Level
+	}{
+		{status: 200, want: slog.LevelInfo},
+		{status: 304, want: slog.LevelInfo},
+		{status: 404, want: slog.LevelWarn},
+		{status: 429, want: slog.LevelWarn},
+		{status: 500, want: slog.LevelError},
+		{status: 503, want: slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		if got := accessLogLevel(tt.status); got != tt.want {
+			t.Errorf("accessLogLevel(%d) = %s, want %s", tt.status, got, tt.want)
+		}
+	}
+}
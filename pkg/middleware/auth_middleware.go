@@ -3,6 +3,7 @@ package middleware
 import (
 	"errors"
 	"net/http"
+	"slices"
 	"soundtube/internal/services"
 	"soundtube/pkg"
 	"strings"
@@ -14,16 +15,9 @@ func AuthMiddleware(s *services.LoginService, l *pkg.CustomLogger) gin.HandlerFu
 	return func(ctx *gin.Context) {
 		tokenStr := ctx.GetHeader("Authorization")
 
-		l.Info("Auth middleware started",
-			"path", ctx.Request.URL.Path,
-			"method", ctx.Request.Method,
-			"authorization_header", tokenStr,
-			"header_length", len(tokenStr),
-		)
-
 		if tokenStr == "" {
 			var err = errors.New("emty token")
-			l.Error("missing authorization header", err)
+			l.WarnContext(ctx.Request.Context(), "missing authorization header", err)
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			ctx.Abort()
 			return
@@ -31,9 +25,9 @@ func AuthMiddleware(s *services.LoginService, l *pkg.CustomLogger) gin.HandlerFu
 
 		tokenStr = strings.TrimPrefix(tokenStr, "Bearer ")
 
-		username, userID, err := s.ValidToken(ctx.Request.Context(), tokenStr)
+		username, userID, role, err := s.ValidToken(ctx.Request.Context(), tokenStr)
 		if err != nil {
-			l.Error("invalid token", err)
+			l.WarnContext(ctx.Request.Context(), "invalid token", err)
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			ctx.Abort()
 			return
@@ -41,9 +35,26 @@ func AuthMiddleware(s *services.LoginService, l *pkg.CustomLogger) gin.HandlerFu
 
 		ctx.Set("username", username)
 		ctx.Set("user_id", userID)
+		ctx.Set("role", role)
 		ctx.Set("token", tokenStr)
+		ctx.Request = ctx.Request.WithContext(pkg.WithUserID(ctx.Request.Context(), userID))
+
+		l.DebugContext(ctx.Request.Context(), "request authorized", "username", username)
+		ctx.Next()
+	}
+}
+
+// RoleMiddleware lets through only users with one of the given roles. It runs after
+// AuthMiddleware, which puts the role from the token on the context.
+func RoleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString("role")
+		if !slices.Contains(roles, role) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+			ctx.Abort()
+			return
+		}
 
-		l.Info("request authorized ", "username", username)
 		ctx.Next()
 	}
 }
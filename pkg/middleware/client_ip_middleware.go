@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net"
+	"soundtube/pkg"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const clientIPKey = "client_ip"
+
+// ClientIPMiddleware resolves the client address once per request. Handlers, the rate
+// limiter and the logs all read it from here instead of the raw headers.
+func ClientIPMiddleware(resolver *pkg.ClientIPResolver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		clientIP := resolver.ClientIP(ctx.Request)
+
+		ctx.Set(clientIPKey, clientIP)
+		ctx.Request = ctx.Request.WithContext(pkg.WithClientIP(ctx.Request.Context(), clientIP))
+
+		trace.SpanFromContext(ctx.Request.Context()).SetAttributes(attribute.String("client.address", clientIP))
+
+		ctx.Next()
+	}
+}
+
+// ClientIP returns the address resolved by ClientIPMiddleware, or the peer address when
+// the middleware did not run.
+func ClientIP(ctx *gin.Context) string {
+	if clientIP := ctx.GetString(clientIPKey); clientIP != "" {
+		return clientIP
+	}
+
+	if host, _, err := net.SplitHostPort(ctx.Request.RemoteAddr); err == nil {
+		return host
+	}
+	return ctx.Request.RemoteAddr
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"soundtube/pkg/config"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSPolicy answers cross-origin requests for the configured origins. Origins are
+// matched exactly, "*" allows any origin and "https://*.example.com" any subdomain.
+// Credentials are never allowed for origins that only "*" matches.
+// Update swaps the rules in place, so reloads reach the running middleware.
+type CORSPolicy struct {
+	rules atomic.Pointer[corsRules]
+}
+
+type corsRules struct {
+	origins          []string
+	allowAny         bool
+	methods          string
+	headers          string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+func NewCORSPolicy(cfg *config.CORS) *CORSPolicy {
+	var policy CORSPolicy
+	policy.Update(cfg)
+
+	return &policy
+}
+
+func (p *CORSPolicy) Update(cfg *config.CORS) {
+	rules := corsRules{
+		methods:          strings.Join(cfg.AllowedMethods, ", "),
+		headers:          strings.Join(cfg.AllowedHeaders, ", "),
+		exposedHeaders:   strings.Join(cfg.ExposedHeaders, ", "),
+		allowCredentials: cfg.AllowCredentials,
+		maxAge:           strconv.Itoa(cfg.MaxAge),
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		if origin == "*" {
+			rules.allowAny = true
+			continue
+		}
+		if origin != "" {
+			rules.origins = append(rules.origins, origin)
+		}
+	}
+
+	p.rules.Store(&rules)
+}
+
+func (p *CORSPolicy) AllowsOrigin(origin string) bool {
+	return p.rules.Load().allowsOrigin(origin)
+}
+
+func (r *corsRules) allowsOrigin(origin string) bool {
+	return origin != "" && (r.allowAny || r.listsOrigin(origin))
+}
+
+// listsOrigin reports whether origin matches one of the configured origins, as opposed
+// to only being let in by "*".
+func (r *corsRules) listsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range r.origins {
+		if allowed == origin {
+			return true
+		}
+
+		scheme, host, found := strings.Cut(allowed, "://*.")
+		if found && strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CORSMiddleware answers preflight requests itself and decorates actual requests from
+// allowed origins. Requests without an Origin header are same-origin and pass through.
+func CORSMiddleware(policy *CORSPolicy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		p := policy.rules.Load()
+
+		origin := ctx.GetHeader("Origin")
+		if origin == "" {
+			ctx.Next()
+			return
+		}
+
+		ctx.Writer.Header().Add("Vary", "Origin")
+		preflight := ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != ""
+
+		if !p.allowsOrigin(origin) {
+			if preflight {
+				ctx.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		// Credentials are only ever granted to a listed origin. An origin let in by "*"
+		// gets the literal wildcard, so the origin is never reflected with credentials.
+		if p.allowAny && (!p.allowCredentials || !p.listsOrigin(origin)) {
+			ctx.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			ctx.Header("Access-Control-Allow-Origin", origin)
+			if p.allowCredentials {
+				ctx.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if !preflight {
+			if p.exposedHeaders != "" {
+				ctx.Header("Access-Control-Expose-Headers", p.exposedHeaders)
+			}
+			ctx.Next()
+			return
+		}
+
+		ctx.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		ctx.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		ctx.Header("Access-Control-Allow-Methods", p.methods)
+		ctx.Header("Access-Control-Allow-Headers", p.headers)
+		ctx.Header("Access-Control-Max-Age", p.maxAge)
+		ctx.AbortWithStatus(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"soundtube/pkg/config"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func corsRouter(cfg *config.CORS) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CORSMiddleware(NewCORSPolicy(cfg)))
+	router.GET("/api/sounds", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.OPTIONS("/api/sounds", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	tests := []struct {
+		name            string
+		origins         []string
+		credentials     bool
+		origin          string
+		preflight       bool
+		wantStatus      int
+		wantAllowOrigin string
+		wantCredentials string
+	}{
+		{name: "same origin", origins: []string{"https://app.example.com"}, wantStatus: http.StatusOK},
+		{name: "exact origin", origins: []string{"https://app.example.com"}, origin: "https://app.example.com",
+			wantStatus: http.StatusOK, wantAllowOrigin: "https://app.example.com"},
+		{name: "origin case and trailing slash", origins: []string{"HTTPS://App.Example.com/"}, origin: "https://app.example.com",
+			wantStatus: http.StatusOK, wantAllowOrigin: "https://app.example.com"},
+		{name: "unknown origin", origins: []string{"https://app.example.com"}, origin: "https://evil.example",
+			wantStatus: http.StatusOK},
+		{name: "unknown origin preflight", origins: []string{"https://app.example.com"}, origin: "https://evil.example",
+			preflight: true, wantStatus: http.StatusForbidden},
+		{name: "subdomain wildcard", origins: []string{"https://*.example.com"}, origin: "https://admin.example.com",
+			wantStatus: http.StatusOK, wantAllowOrigin: "https://admin.example.com"},
+		{name: "subdomain wildcard needs the scheme", origins: []string{"https://*.example.com"}, origin: "http://admin.example.com",
+			wantStatus: http.StatusOK},
+		{name: "subdomain wildcard is not a suffix match", origins: []string{"https://*.example.com"}, origin: "https://evilexample.com",
+			wantStatus: http.StatusOK},
+		{name: "any origin", origins: []string{"*"}, origin: "https://evil.example",
+			wantStatus: http.StatusOK, wantAllowOrigin: "*"},
+		{name: "listed origin with credentials", origins: []string{"https://app.example.com"}, credentials: true,
+			origin: "https://app.example.com", wantStatus: http.StatusOK, wantAllowOrigin: "https://app.example.com", wantCredentials: "true"},
+		{name: "any origin with credentials is never reflected", origins: []string{"*"}, credentials: true,
+			origin: "https://evil.example", wantStatus: http.StatusOK, wantAllowOrigin: "*"},
+		{name: "listed origin next to any with credentials", origins: []string{"*", "https://app.example.com"}, credentials: true,
+			origin: "https://app.example.com", wantStatus: http.StatusOK, wantAllowOrigin: "https://app.example.com", wantCredentials: "true"},
+		{name: "preflight", origins: []string{"https://app.example.com"}, origin: "https://app.example.com", preflight: true,
+			wantStatus: http.StatusNoContent, wantAllowOrigin: "https://app.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := corsRouter(&config.CORS{
+				AllowedOrigins:   tt.origins,
+				AllowedMethods:   []string{"GET", "POST"},
+				AllowedHeaders:   []string{"Authorization"},
+				AllowCredentials: tt.credentials,
+				MaxAge:           600,
+			})
+
+			method := http.MethodGet
+			if tt.preflight {
+				method = http.MethodOptions
+			}
+			req := httptest.NewRequest(method, "/api/sounds", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowOrigin {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Fatalf("Access-Control-Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+			if tt.preflight && tt.wantStatus == http.StatusNoContent {
+				if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+					t.Fatalf("Access-Control-Allow-Methods = %q", got)
+				}
+				if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+					t.Fatalf("Access-Control-Max-Age = %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSPolicyUpdate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policy := NewCORSPolicy(&config.CORS{AllowedOrigins: []string{"https://old.example.com"}})
+	router := gin.New()
+	router.Use(CORSMiddleware(policy))
+	router.GET("/api/sounds", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	policy.Update(&config.CORS{AllowedOrigins: []string{"https://new.example.com"}})
+
+	tests := []struct {
+		origin          string
+		wantAllowOrigin string
+	}{
+		{origin: "https://new.example.com", wantAllowOrigin: "https://new.example.com"},
+		{origin: "https://old.example.com", wantAllowOrigin: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/sounds", nil)
+			req.Header.Set("Origin", tt.origin)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowOrigin {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowOrigin)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"soundtube/internal/domain/feature"
+	"soundtube/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureSubject is who the request is evaluated for. Behind AuthMiddleware that is the
+// signed-in user, elsewhere an anonymous subject.
+func FeatureSubject(ctx *gin.Context) feature.Subject {
+	return feature.Subject{UserID: ctx.GetInt("user_id"), Role: ctx.GetString("role")}
+}
+
+// FeatureMiddleware hides routes behind a feature flag. While the flag is off for the
+// caller the route answers 404, as if it did not exist yet.
+func FeatureMiddleware(features *services.FeatureService, name string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !features.Enabled(name, FeatureSubject(ctx)) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
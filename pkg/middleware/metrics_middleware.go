@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"soundtube/pkg/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records latency and status per route template, so /sounds/:id
+// stays one series no matter how many sounds are requested.
+func MetricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		m.ObserveRequest(methodLabel(ctx.Request.Method), routeLabel(ctx), strconv.Itoa(ctx.Writer.Status()), time.Since(start).Seconds())
+	}
+}
+
+// MetricsTokenMiddleware guards the scrape endpoint with a static bearer token.
+func MetricsTokenMiddleware(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+
+	return func(ctx *gin.Context) {
+		got := []byte(ctx.GetHeader("Authorization"))
+		if subtle.ConstantTimeCompare(got, expected) != 1 {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func routeLabel(ctx *gin.Context) string {
+	if route := ctx.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
+
+// methodLabel folds anything but the standard methods into "other", so clients cannot
+// create series by sending made-up methods.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "other"
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import "testing"
+
+func TestMethodLabel(t *testing.T) {
+	cases := map[string]struct {
+		method string
+		want   string
+	}{
+		"get":       {"GET", "GET"},
+		"options":   {"OPTIONS", "OPTIONS"},
+		"patch":     {"PATCH", "PATCH"},
+		"made up":   {"FOOBAR", "other"},
+		"lowercase": {"get", "other"},
+		"webdav":    {"PROPFIND", "other"},
+	}
+
+	for name, tc := range cases {
+		if got := methodLabel(tc.method); got != tc.want {
+			t.Errorf("%s: methodLabel(%q) = %q, want %q", name, tc.method, got, tc.want)
+		}
+	}
+}
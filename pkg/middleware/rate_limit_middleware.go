@@ -1,19 +1,47 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
+	"soundtube/internal/domain/ratelimit"
 	"soundtube/pkg"
-	"soundtube/scripts"
+	"soundtube/pkg/metrics"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RateLimiterMiddleware(r *pkg.RateLimiter) gin.HandlerFunc {
+// RateLimiterMiddleware enforces the named policy on every request it wraps and reports
+// the outcome in RateLimit-* headers. The policy is looked up per request so reloaded
+// limits apply immediately; a name missing from the set is not limited. Per-user
+// policies must run after AuthMiddleware to see the user; before it they fall back to
+// the client IP. When the limiter itself fails the request is let through rather than
+// turning an outage into a 429 storm.
+func RateLimiterMiddleware(limiter ratelimit.ILimiter, policies *pkg.RateLimitPolicies, name string, m *metrics.Metrics, l *pkg.CustomLogger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		ip := scripts.GetClientIP(ctx.Request)
+		policy, ok := policies.Get(name)
+		if !ok {
+			ctx.Next()
+			return
+		}
 
-		if !r.Allow(ip) {
-			ctx.AbortWithStatusJSON(http.StatusBadRequest,
+		result, err := limiter.Allow(ctx.Request.Context(), policy, rateLimitKey(ctx, policy))
+		if err != nil {
+			l.ErrorContext(ctx.Request.Context(), "rate limiter failed", err)
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("RateLimit-Policy", policy.Header())
+		ctx.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			m.RateLimited(policy.Name(), routeLabel(ctx))
+			ctx.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests,
 				gin.H{"error": "too many requests"})
 			return
 		}
@@ -21,3 +49,19 @@ func RateLimiterMiddleware(r *pkg.RateLimiter) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+func rateLimitKey(ctx *gin.Context, policy ratelimit.Policy) string {
+	if policy.PerUser() {
+		if userID, ok := ctx.Get("user_id"); ok {
+			if id, ok := userID.(int); ok {
+				return "user:" + strconv.Itoa(id)
+			}
+		}
+	}
+
+	return "ip:" + ClientIP(ctx)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
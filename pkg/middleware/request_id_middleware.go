@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"soundtube/pkg"
 	"soundtube/scripts"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +15,7 @@ func RequsetIDMiddleware() gin.HandlerFunc {
 		}
 
 		ctx.Set("request_id", requsetID)
+		ctx.Request = ctx.Request.WithContext(pkg.WithRequestID(ctx.Request.Context(), requsetID))
 
 		ctx.Header("X-Request-ID", requsetID)
 
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestContentSecurityPolicyString(t *testing.T) {
+	cases := map[string]struct {
+		policy ContentSecurityPolicy
+		want   string
+	}{
+		"empty": {ContentSecurityPolicy{}, ""},
+		"sorted directives": {ContentSecurityPolicy{"script-src": "'self'", "default-src": "'none'"},
+			"default-src 'none'; script-src 'self'"},
+		"nonce placeholder":         {ContentSecurityPolicy{"script-src": "'self' 'nonce'"}, "script-src 'self' 'nonce-abc'"},
+		"directive without sources": {ContentSecurityPolicy{"upgrade-insecure-requests": ""}, "upgrade-insecure-requests"},
+	}
+
+	for name, tc := range cases {
+		if got := tc.policy.String("abc"); got != tc.want {
+			t.Errorf("%s: String() = %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestSecurityMiddlewareNonce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(SecurityMiddleware(SecurityHeaders{
+		CSP:            ContentSecurityPolicy{"script-src": "'nonce'"},
+		ReferrerPolicy: "no-referrer",
+	}))
+	router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, CSPNonce(c)) })
+	router.GET("/page", ContentSecurityPolicyMiddleware(ContentSecurityPolicy{"default-src": "'self'", "script-src": "'nonce'"}),
+		func(c *gin.Context) { c.String(http.StatusOK, CSPNonce(c)) })
+
+	tests := []struct {
+		path       string
+		wantPrefix string
+	}{
+		{path: "/", wantPrefix: "script-src 'nonce-"},
+		{path: "/page", wantPrefix: "default-src 'self'; script-src 'nonce-"},
+	}
+
+	seen := map[string]bool{}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		nonce := w.Body.String()
+		if nonce == "" || seen[nonce] {
+			t.Fatalf("%s: nonce %q is empty or reused", tt.path, nonce)
+		}
+		seen[nonce] = true
+
+		csp := w.Header().Get("Content-Security-Policy")
+		if !strings.HasPrefix(csp, tt.wantPrefix) || !strings.Contains(csp, "'nonce-"+nonce+"'") {
+			t.Fatalf("%s: Content-Security-Policy = %q, want the request nonce %q", tt.path, csp, nonce)
+		}
+		if got := w.Header().Get("Referrer-Policy"); got != "no-referrer" {
+			t.Fatalf("%s: Referrer-Policy = %q", tt.path, got)
+		}
+		if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+			t.Fatalf("%s: X-Content-Type-Options = %q", tt.path, got)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketAuthMiddleware lets browsers, which cannot set headers on a WebSocket
+// handshake, pass their token as the "bearer, <token>" subprotocol. It has to run
+// before AuthMiddleware.
+func WebSocketAuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") != "" {
+			ctx.Next()
+			return
+		}
+
+		protocols := strings.Split(ctx.GetHeader("Sec-WebSocket-Protocol"), ",")
+		if len(protocols) == 2 && strings.TrimSpace(protocols[0]) == "bearer" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(protocols[1]))
+		}
+
+		ctx.Next()
+	}
+}
@@ -1,50 +1,139 @@
 package pkg
 
 import (
-	"sort"
+	"context"
+	"soundtube/internal/domain/ratelimit"
 	"soundtube/pkg/config"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type RateLimiter struct {
-	mu          sync.Mutex
-	requests    map[string][]time.Time
-	maxRequests int
-	window      time.Duration
+const rateLimiterSweepInterval = time.Minute
+
+// MemoryRateLimiter is a per-instance GCRA limiter with the same semantics as the Redis
+// one. Keys whose state has fully replenished are dropped by a periodic sweep, so idle
+// clients do not accumulate.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
 }
 
-func NewRateLimiter(cgf *config.RateLimiter) *RateLimiter {
-	return &RateLimiter{
-		requests:    make(map[string][]time.Time),
-		maxRequests: cgf.MaxRequests,
-		window:      time.Duration(cgf.Window),
-	}
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{tats: make(map[string]time.Time), lastSweep: time.Now()}
 }
 
-func (r *RateLimiter) Allow(ip string) bool {
+func (r *MemoryRateLimiter) Allow(_ context.Context, policy ratelimit.Policy, key string) (*ratelimit.Result, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	now := time.Now()
-	windowStart := now.Add(-r.window)
+	r.sweep(now)
+
+	key = policy.Name() + ":" + key
+	emission := policy.EmissionInterval()
+
+	tat, exists := r.tats[key]
+	if !exists || tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(emission)
+	allowAt := newTAT.Add(-policy.Window())
+	if allowAt.After(now) {
+		return &ratelimit.Result{
+			Allowed:    false,
+			Limit:      policy.Limit(),
+			RetryAfter: allowAt.Sub(now),
+			ResetAfter: tat.Sub(now),
+		}, nil
+	}
+
+	r.tats[key] = newTAT
 
-	timestamps, exists := r.requests[ip]
-	if !exists {
-		timestamps = []time.Time{}
+	return &ratelimit.Result{
+		Allowed:    true,
+		Limit:      policy.Limit(),
+		Remaining:  int((policy.Window() - newTAT.Sub(now)) / emission),
+		ResetAfter: newTAT.Sub(now),
+	}, nil
+}
+
+func (r *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < rateLimiterSweepInterval {
+		return
+	}
+
+	for key, tat := range r.tats {
+		if !tat.After(now) {
+			delete(r.tats, key)
+		}
 	}
+	r.lastSweep = now
+}
+
+// FallbackRateLimiter asks the shared limiter first and falls back to the local one
+// while it is unavailable, so a Redis outage degrades limits to per-instance instead of
+// rejecting or waving through every request.
+type FallbackRateLimiter struct {
+	primary  ratelimit.ILimiter
+	fallback ratelimit.ILimiter
+	logger   *CustomLogger
+}
 
-	firstValidIndex := sort.Search(len(timestamps), func(i int) bool {
-		return timestamps[i].After(windowStart)
-	})
-	validTimestamps := timestamps[firstValidIndex:]
+func NewFallbackRateLimiter(primary, fallback ratelimit.ILimiter, logger *CustomLogger) *FallbackRateLimiter {
+	return &FallbackRateLimiter{primary: primary, fallback: fallback, logger: logger}
+}
 
-	if len(validTimestamps) >= r.maxRequests {
-		return false
+func (r *FallbackRateLimiter) Allow(ctx context.Context, policy ratelimit.Policy, key string) (*ratelimit.Result, error) {
+	result, err := r.primary.Allow(ctx, policy, key)
+	if err == nil {
+		return result, nil
 	}
 
-	validTimestamps = append(validTimestamps, now)
-	r.requests[ip] = validTimestamps
+	r.logger.WarnContext(ctx, "shared rate limiter unavailable, using local limits", err)
+	return r.fallback.Allow(ctx, policy, key)
+}
+
+// RateLimitPolicies is the set of named policies the middleware looks up on every
+// request. Store replaces the whole set at once, so a reload never mixes old and new.
+type RateLimitPolicies struct {
+	policies atomic.Pointer[map[string]ratelimit.Policy]
+}
+
+func NewRateLimitPolicies(policies map[string]ratelimit.Policy) *RateLimitPolicies {
+	var set RateLimitPolicies
+	set.Store(policies)
+
+	return &set
+}
+
+func (s *RateLimitPolicies) Get(name string) (ratelimit.Policy, bool) {
+	policy, ok := (*s.policies.Load())[name]
+	return policy, ok
+}
+
+func (s *RateLimitPolicies) Store(policies map[string]ratelimit.Policy) {
+	s.policies.Store(&policies)
+}
+
+// ParseRateLimitPolicies builds the "default" per-IP policy and the named ones from cfg.
+func ParseRateLimitPolicies(cfg *config.RateLimiter) (map[string]ratelimit.Policy, error) {
+	policies := make(map[string]ratelimit.Policy, len(cfg.Policies)+1)
+
+	policy, err := ratelimit.NewPolicy("default", cfg.MaxRequests, cfg.Window, false)
+	if err != nil {
+		return nil, err
+	}
+	policies[policy.Name()] = policy
+
+	for name, p := range cfg.Policies {
+		if policy, err = ratelimit.NewPolicy(name, p.MaxRequests, p.Window, p.PerUser); err != nil {
+			return nil, err
+		}
+		policies[name] = policy
+	}
 
-	return true
+	return policies, nil
 }
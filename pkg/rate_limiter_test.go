@@ -0,0 +1,229 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"soundtube/internal/domain/ratelimit"
+	"soundtube/pkg/config"
+	"testing"
+	"time"
+)
+
+func mustPolicy(t *testing.T, name string, limit int, window time.Duration) ratelimit.Policy {
+	t.Helper()
+
+	policy, err := ratelimit.NewPolicy(name, limit, window, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+func TestMemoryRateLimiterBurst(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	policy := mustPolicy(t, "default", 3, time.Minute)
+
+	tests := []struct {
+		allowed       bool
+		remaining     int
+		minRetryAfter time.Duration
+		minResetAfter time.Duration
+	}{
+		{allowed: true, remaining: 2, minResetAfter: 19 * time.Second},
+		{allowed: true, remaining: 1, minResetAfter: 39 * time.Second},
+		{allowed: true, remaining: 0, minResetAfter: 59 * time.Second},
+		{allowed: false, remaining: 0, minRetryAfter: 19 * time.Second, minResetAfter: 59 * time.Second},
+	}
+
+	for i, tt := range tests {
+		result, err := limiter.Allow(context.Background(), policy, "1.2.3.4")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed != tt.allowed || result.Remaining != tt.remaining || result.Limit != 3 {
+			t.Fatalf("request %d: result = %+v, want allowed %v remaining %d", i+1, result, tt.allowed, tt.remaining)
+		}
+		if result.RetryAfter < tt.minRetryAfter || result.RetryAfter > 20*time.Second {
+			t.Fatalf("request %d: RetryAfter = %s", i+1, result.RetryAfter)
+		}
+		if result.ResetAfter < tt.minResetAfter || result.ResetAfter > time.Minute {
+			t.Fatalf("request %d: ResetAfter = %s", i+1, result.ResetAfter)
+		}
+	}
+}
+
+func TestMemoryRateLimiterKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		firstPolicy string
+		firstKey    string
+		policy      string
+		key         string
+		wantAllowed bool
+	}{
+		{name: "same policy and key", firstPolicy: "login", firstKey: "1.2.3.4", policy: "login", key: "1.2.3.4", wantAllowed: false},
+		{name: "other key", firstPolicy: "login", firstKey: "1.2.3.4", policy: "login", key: "5.6.7.8", wantAllowed: true},
+		{name: "other policy", firstPolicy: "login", firstKey: "1.2.3.4", policy: "auth", key: "1.2.3.4", wantAllowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewMemoryRateLimiter()
+			if result, _ := limiter.Allow(context.Background(), mustPolicy(t, tt.firstPolicy, 1, time.Minute), tt.firstKey); !result.Allowed {
+				t.Fatal("first request rejected")
+			}
+
+			result, err := limiter.Allow(context.Background(), mustPolicy(t, tt.policy, 1, time.Minute), tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Allowed != tt.wantAllowed {
+				t.Fatalf("allowed = %v, want %v", result.Allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestMemoryRateLimiterReplenishes(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	policy := mustPolicy(t, "default", 2, 400*time.Millisecond)
+
+	for range 2 {
+		if result, _ := limiter.Allow(context.Background(), policy, "k"); !result.Allowed {
+			t.Fatal("burst rejected")
+		}
+	}
+	if result, _ := limiter.Allow(context.Background(), policy, "k"); result.Allowed {
+		t.Fatal("request over the limit allowed")
+	}
+
+	// One emission interval later exactly one more request fits.
+	time.Sleep(policy.EmissionInterval() + 10*time.Millisecond)
+	if result, _ := limiter.Allow(context.Background(), policy, "k"); !result.Allowed {
+		t.Fatal("request after one emission interval rejected")
+	}
+	if result, _ := limiter.Allow(context.Background(), policy, "k"); result.Allowed {
+		t.Fatal("second request after one emission interval allowed")
+	}
+}
+
+type failingLimiter struct{ calls int }
+
+func (l *failingLimiter) Allow(context.Context, ratelimit.Policy, string) (*ratelimit.Result, error) {
+	l.calls++
+	return nil, errors.New("redis unavailable")
+}
+
+type allowingLimiter struct{ calls int }
+
+func (l *allowingLimiter) Allow(_ context.Context, policy ratelimit.Policy, _ string) (*ratelimit.Result, error) {
+	l.calls++
+	return &ratelimit.Result{Allowed: true, Limit: policy.Limit()}, nil
+}
+
+func TestFallbackRateLimiter(t *testing.T) {
+	logger := NewLogger(slog.New(slog.DiscardHandler), false)
+	policy := mustPolicy(t, "default", 1, time.Minute)
+
+	tests := []struct {
+		name          string
+		primary       ratelimit.ILimiter
+		wantFallbacks int
+	}{
+		{name: "shared limiter answers", primary: &allowingLimiter{}, wantFallbacks: 0},
+		{name: "shared limiter down", primary: &failingLimiter{}, wantFallbacks: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback := &allowingLimiter{}
+			result, err := NewFallbackRateLimiter(tt.primary, fallback, logger).Allow(context.Background(), policy, "k")
+			if err != nil || !result.Allowed {
+				t.Fatalf("result = %+v, err = %v", result, err)
+			}
+			if fallback.calls != tt.wantFallbacks {
+				t.Fatalf("fallback calls = %d, want %d", fallback.calls, tt.wantFallbacks)
+			}
+		})
+	}
+}
+
+func TestParseRateLimitPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.RateLimiter
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name: "default and named",
+			cfg: config.RateLimiter{MaxRequests: 100, Window: time.Minute, Policies: map[string]config.RateLimitPolicy{
+				"login": {MaxRequests: 5, Window: time.Minute},
+			}},
+			want: map[string]int{"default": 100, "login": 5},
+		},
+		{name: "invalid default", cfg: config.RateLimiter{MaxRequests: 0, Window: time.Minute}, wantErr: true},
+		{
+			name: "invalid named",
+			cfg: config.RateLimiter{MaxRequests: 100, Window: time.Minute, Policies: map[string]config.RateLimitPolicy{
+				"login": {MaxRequests: 5},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := ParseRateLimitPolicies(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(policies) != len(tt.want) {
+				t.Fatalf("policies = %v, want %v", policies, tt.want)
+			}
+			for name, limit := range tt.want {
+				if policies[name].Limit() != limit {
+					t.Fatalf("%s limit = %d, want %d", name, policies[name].Limit(), limit)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitPoliciesStore(t *testing.T) {
+	parse := func(cfg config.RateLimiter) map[string]ratelimit.Policy {
+		policies, err := ParseRateLimitPolicies(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return policies
+	}
+
+	set := NewRateLimitPolicies(parse(config.RateLimiter{MaxRequests: 100, Window: time.Minute,
+		Policies: map[string]config.RateLimitPolicy{"login": {MaxRequests: 5, Window: time.Minute}}}))
+	set.Store(parse(config.RateLimiter{MaxRequests: 50, Window: time.Minute,
+		Policies: map[string]config.RateLimitPolicy{"upload": {MaxRequests: 10, Window: time.Hour}}}))
+
+	tests := []struct {
+		name      string
+		wantOK    bool
+		wantLimit int
+	}{
+		{name: "default", wantOK: true, wantLimit: 50},
+		{name: "upload", wantOK: true, wantLimit: 10},
+		{name: "login", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, ok := set.Get(tt.name)
+			if ok != tt.wantOK {
+				t.Fatalf("Get(%q) ok = %v, want %v", tt.name, ok, tt.wantOK)
+			}
+			if ok && policy.Limit() != tt.wantLimit {
+				t.Fatalf("limit = %d, want %d", policy.Limit(), tt.wantLimit)
+			}
+		})
+	}
+}
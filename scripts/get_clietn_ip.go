@@ -1,10 +0,0 @@
-package scripts
-
-import "net/http"
-
-func GetClientIP(r *http.Request) string {
-	if ip := r.Header.Get("X-Forwarded-For"); ip != "" {
-		return ip
-	}
-	return r.RemoteAddr
-}
@@ -4,6 +4,23 @@ let currentUserName = localStorage.getItem('userName');
 let currentSoundId = null;
 let currentCommentsSoundId = null;
 
+// Buttons name their handler in data-action (and JSON arguments in data-args) instead of
+// inline onclick attributes, which the Content-Security-Policy does not allow.
+const actions = {
+    showAuthModal, hideAuthModal, logout, loadSounds: () => loadSounds(), showUploadSection,
+    setReaction, showComments, hideCommentsModal, addComment, setCommentReaction,
+};
+
+document.addEventListener('click', function(e) {
+    const target = e.target.closest('[data-action]');
+    if (!target || !actions[target.dataset.action]) {
+        return;
+    }
+
+    const args = target.dataset.args ? JSON.parse(target.dataset.args) : [];
+    actions[target.dataset.action](...args);
+});
+
 document.addEventListener('DOMContentLoaded', function() {
     console.log('App loaded, token exists:', !!currentToken);
     checkAuth();
@@ -93,10 +110,10 @@ function showUnauthorizedMessage() {
     const soundsList = document.getElementById('soundsList');
     soundsList.innerHTML = `
         <h3>Последние треки</h3>
-        <div style="text-align: center; padding: 40px;">
+        <div class="empty-state">
             <p>Для просмотра треков необходимо авторизоваться</p>
-            <button class="btn btn-primary" onclick="showAuthModal('login')">Войти</button>
-            <button class="btn btn-secondary" onclick="showAuthModal('register')">Зарегистрироваться</button>
+            <button class="btn btn-primary" data-action="showAuthModal" data-args='["login"]'>Войти</button>
+            <button class="btn btn-secondary" data-action="showAuthModal" data-args='["register"]'>Зарегистрироваться</button>
         </div>
     `;
 }
@@ -260,9 +277,12 @@ async function logout() {
     loadSounds();
 }
 
-async function loadSounds() {
+async function loadSounds(cursor = null) {
     const soundsList = document.getElementById('soundsList');
-    soundsList.innerHTML = '<h3>Последние треки</h3>';
+    if (!cursor) {
+        soundsList.innerHTML = '<h3>Последние треки</h3>';
+    }
+    document.getElementById('loadMoreSounds')?.remove();
 
     try {
         const headers = {};
@@ -270,18 +290,28 @@ async function loadSounds() {
             headers['Authorization'] = `Bearer ${currentToken}`;
         }
 
-        const response = await fetch(`${API_BASE}/sounds/`, { headers });
+        const query = cursor ? `?cursor=${encodeURIComponent(cursor)}` : '';
+        const response = await fetch(`${API_BASE}/sounds/${query}`, { headers });
 
         if (response.ok) {
-            const sounds = await response.json();
-            if (sounds.length === 0) {
+            const page = await response.json();
+            if (page.sounds.length === 0 && !cursor) {
                 soundsList.innerHTML += '<p>Пока нет загруженных треков. Будьте первым!</p>';
             } else {
-                sounds.forEach(sound => {
+                page.sounds.forEach(sound => {
                     const soundElement = createSoundElement(sound);
                     soundsList.appendChild(soundElement);
                 });
             }
+
+            if (page.next_cursor) {
+                const loadMore = document.createElement('button');
+                loadMore.id = 'loadMoreSounds';
+                loadMore.className = 'btn btn-secondary';
+                loadMore.textContent = 'Загрузить ещё';
+                loadMore.onclick = () => loadSounds(page.next_cursor);
+                soundsList.appendChild(loadMore);
+            }
         } else if (response.status === 401) {
             soundsList.innerHTML += '<p>Для просмотра треков необходимо авторизоваться</p>';
         }
@@ -291,6 +321,43 @@ async function loadSounds() {
     }
 }
 
+const LISTEN_HEARTBEAT_SECONDS = 10;
+
+function trackListening(audio, soundId) {
+    let sessionId = null;
+    let lastReported = 0;
+
+    const report = (event) => {
+        if (!currentToken || !sessionId) return;
+        fetch(`${API_BASE}/sounds/${soundId}/listens`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+                'Authorization': `Bearer ${currentToken}`
+            },
+            body: JSON.stringify({ session_id: sessionId, event, position: Math.floor(audio.currentTime) })
+        }).catch(error => console.error('Ошибка отправки события прослушивания:', error));
+    };
+
+    audio.addEventListener('play', () => {
+        if (!sessionId || audio.currentTime === 0) {
+            sessionId = crypto.randomUUID();
+            lastReported = 0;
+            report('start');
+        }
+    });
+    audio.addEventListener('timeupdate', () => {
+        if (audio.currentTime - lastReported >= LISTEN_HEARTBEAT_SECONDS) {
+            lastReported = audio.currentTime;
+            report('progress');
+        }
+    });
+    audio.addEventListener('ended', () => {
+        report('complete');
+        sessionId = null;
+    });
+}
+
 function createSoundElement(sound) {
     const div = document.createElement('div');
     div.className = 'sound-item';
@@ -301,7 +368,7 @@ function createSoundElement(sound) {
     const soundName = sound.name || sound.title || 'Без названия';
     const soundAlbum = sound.album || 'Не указан';
     const soundGenre = sound.genre || 'Не указан';
-    const authorId = sound.author_id || sound.authorID || 'Неизвестен';
+    const authorName = (sound.author && sound.author.username) || 'Неизвестен';
     const filePath = sound.file_path || sound.filePath || sound.filename;
     const likes = sound.likes || 0;
     const dislikes = sound.dislikes || 0;
@@ -313,11 +380,11 @@ function createSoundElement(sound) {
             <span class="sound-album">Альбом: ${escapeHtml(soundAlbum)}</span>
             <span class="sound-genre">Жанр: ${escapeHtml(soundGenre)}</span>
         </div>
-        <div class="sound-author" style="color: #888; font-size: 0.9rem; margin-bottom: 10px;">
-            Автор ID: ${authorId}
+        <div class="sound-author">
+            Автор: ${escapeHtml(authorName)} · Прослушиваний: ${sound.play_count || 0}
         </div>
         ${filePath ? `
-            <audio controls style="width: 100%; margin: 10px 0;">
+            <audio controls class="sound-audio">
                 <source src="/static/${filePath}" type="audio/mpeg">
                 Ваш браузер не поддерживает аудио элементы.
             </audio>
@@ -325,19 +392,24 @@ function createSoundElement(sound) {
         
         <div class="sound-actions">
             <div class="reactions">
-                <button class="reaction-btn ${userReaction === 'like' ? 'active' : ''}" onclick="setReaction(${sound.id}, 'like')">
+                <button class="reaction-btn ${userReaction === 'like' ? 'active' : ''}" data-action="setReaction" data-args='[${sound.id}, "like"]'>
                     👍 ${likes}
                 </button>
-                <button class="reaction-btn ${userReaction === 'dislike' ? 'active' : ''}" onclick="setReaction(${sound.id}, 'dislike')">
+                <button class="reaction-btn ${userReaction === 'dislike' ? 'active' : ''}" data-action="setReaction" data-args='[${sound.id}, "dislike"]'>
                     👎 ${dislikes}
                 </button>
-                <button class="comment-btn" onclick="showComments(${sound.id}, '${escapeHtml(soundName)}')">
+                <button class="comment-btn" data-action="showComments" data-args="${escapeHtml(JSON.stringify([sound.id, soundName]))}">
                     💬 Комментарии
                 </button>
             </div>
         </div>
     `;
 
+    const audio = div.querySelector('audio');
+    if (audio) {
+        trackListening(audio, sound.id);
+    }
+
     return div;
 }
 
@@ -481,10 +553,10 @@ function displayComments(comments) {
             </div>
             <div class="comment-text">${escapeHtml(comment.text)}</div>
             <div class="comment-actions">
-                <button class="reaction-btn" onclick="setCommentReaction(${comment.id}, 'like')">
+                <button class="reaction-btn" data-action="setCommentReaction" data-args='[${comment.id}, "like"]'>
                     👍 ${comment.likes || 0}
                 </button>
-                <button class="reaction-btn" onclick="setCommentReaction(${comment.id}, 'dislike')">
+                <button class="reaction-btn" data-action="setCommentReaction" data-args='[${comment.id}, "dislike"]'>
                     👎 ${comment.dislikes || 0}
                 </button>
             </div>
@@ -562,7 +634,7 @@ document.getElementById('uploadForm').addEventListener('submit', async function(
     const genre = document.getElementById('soundGenre').value;
     const file = document.getElementById('soundFile').files[0];
 
-    if (!name || !album || !genre) {
+    if (!name || !genre) {
         alert('Пожалуйста, заполните все поля');
         return;
     }